@@ -0,0 +1,277 @@
+package mq
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"example/common"
+)
+
+// Transactional outbox
+// 비즈니스 데이터와 메시지를 같은 트랜잭션으로 mq_outbox 에 저장하고, Run 이 비동기로 발행 (publisher confirm 받으면 published_at 기록)
+// 발행 후 커밋 전에 죽으면 다시 발행되니까 at-least-once (consumer 는 message_id 로 중복 처리)
+
+// OutboxSchema : MySQL 5.6 기준
+// publishing 은 amqp.Publishing 전체를 gob 으로 (JSON 을 거치면 headers 의 int32 / 중첩 Table / []byte / time.Time 타입이 바뀜)
+// message_id 는 조회용으로 따로 둠
+const OutboxSchema = `
+CREATE TABLE IF NOT EXISTS mq_outbox (
+	id           BIGINT        NOT NULL AUTO_INCREMENT,
+	exchange     VARCHAR(255)  NOT NULL,
+	routing_key  VARCHAR(255)  NOT NULL,
+	mandatory    TINYINT(1)    NOT NULL DEFAULT 0,
+	message_id   VARCHAR(255)  NOT NULL DEFAULT '',
+	publishing   MEDIUMBLOB    NOT NULL,
+	attempts     INT           NOT NULL DEFAULT 0,
+	last_error   VARCHAR(1024) NOT NULL DEFAULT '',
+	created_at   DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	published_at DATETIME      NULL,
+	PRIMARY KEY (id),
+	KEY idx_published_id (published_at, id)
+) DEFAULT CHARSET=utf8mb4`
+
+const (
+	defaultPollInterval   = 1 * time.Second
+	defaultBatchSize      = 100
+	defaultMaxAttempts    = 10
+	defaultPublishTimeout = 30 * time.Second
+)
+
+// headers 값으로 올 수 있는 타입 (amqp.Table.Validate 기준) 중 gob 기본 타입이 아닌 것
+func init() {
+	gob.Register(amqp.Table{})
+	gob.Register([]interface{}{})
+	gob.Register(amqp.Decimal{})
+	gob.Register(time.Time{})
+}
+
+type outboxRow struct {
+	ID         int64  `db:"id"`
+	Exchange   string `db:"exchange"`
+	RoutingKey string `db:"routing_key"`
+	Mandatory  bool   `db:"mandatory"`
+	Publishing []byte `db:"publishing"`
+}
+
+// Outbox : common.Init 으로 연결된 DB 사용
+type Outbox struct {
+	Producer       *RabbitMqProducer
+	PollInterval   time.Duration // 새 메시지 확인 간격 (Transaction 으로 넣은건 바로 깨움)
+	BatchSize      int           // 한번에 발행할 최대 개수
+	MaxAttempts    int           // 이만큼 실패하면 더 발행 안함 (last_error 확인)
+	PublishTimeout time.Duration // 연결이 끊겨 있으면 이만큼만 기다리고 다음 poll 때 다시
+
+	wake chan struct{}
+}
+
+func NewOutbox(producer *RabbitMqProducer) *Outbox {
+	return &Outbox{
+		Producer:       producer,
+		PollInterval:   defaultPollInterval,
+		BatchSize:      defaultBatchSize,
+		MaxAttempts:    defaultMaxAttempts,
+		PublishTimeout: defaultPublishTimeout,
+		wake:           make(chan struct{}, 1),
+	}
+}
+
+// Enqueue : 호출한 쪽 트랜잭션 안에서 저장 (커밋되어야 Run 이 발행함)
+// Publishing 은 속성 / headers 타입까지 그대로 발행됨, AMQP 로 못 보내는 headers 값이면 저장하지 않고 에러
+func Enqueue(ctx context.Context, tx *sqlx.Tx, messages ...Message) error {
+	for _, message := range messages {
+		publishing, err := encodePublishing(message.Publishing)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			insert into mq_outbox (exchange, routing_key, mandatory, message_id, publishing)
+			values (?, ?, ?, ?, ?)`,
+			message.Exchange,
+			message.RoutingKey,
+			message.Mandatory,
+			message.Publishing.MessageId,
+			publishing,
+		); err != nil {
+			return fmt.Errorf("failed to insert outbox: %w", err)
+		}
+	}
+	return nil
+}
+
+// Transaction : fn 의 DB 변경과 fn 이 돌려준 메시지를 common.WithTransaction 하나로 저장
+// fn 이 에러면 둘 다 롤백, 커밋되면 Run 을 바로 깨움
+func (o *Outbox) Transaction(ctx context.Context, fn func(tx *sqlx.Tx) ([]Message, error)) error {
+	err := common.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		messages, err := fn(tx)
+		if err != nil {
+			return err
+		}
+		return Enqueue(ctx, tx, messages...)
+	})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run : ctx 가 취소될 때까지 outbox 를 발행 (블로킹, 고루틴으로 실행)
+func (o *Outbox) Run(ctx context.Context) {
+	outboxLog := slog.With("mq", "outbox")
+
+	for {
+		count, err := o.relay(ctx)
+		if err != nil && ctx.Err() == nil {
+			outboxLog.Error("Failed to relay outbox", "error", err)
+		} else if count > 0 {
+			outboxLog.Debug("outbox relayed", "count", count)
+		}
+
+		// 꽉 채워서 가져왔으면 남은게 있을 수 있으니 바로 다시 (발행을 시도도 못했으면 에러라서 poll 간격만큼 쉼)
+		if err == nil && count == o.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			outboxLog.Info("Context cancelled, exiting outbox relay")
+			return
+		case <-o.wake:
+		case <-time.After(o.PollInterval):
+		}
+	}
+}
+
+// relay : 발행 안된 row 를 잠그고 (여러 relay 가 떠 있어도 한번만) 배치로 발행, 결과를 같은 트랜잭션에서 기록
+// 연결 대기 / producer 종료로 시도도 못한 row 가 있으면 기록은 커밋하고 에러로 알려줌 (Run 이 바로 다시 돌지 않게)
+func (o *Outbox) relay(ctx context.Context) (int, error) {
+	count, skipped := 0, 0
+	err := common.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		var rows []outboxRow
+		if err := tx.SelectContext(ctx, &rows, `
+			select id, exchange, routing_key, mandatory, publishing
+			from mq_outbox
+			where published_at is null and attempts < ?
+			order by id
+			limit ?
+			for update`,
+			o.MaxAttempts,
+			o.BatchSize,
+		); err != nil {
+			return err
+		}
+		count = len(rows)
+		if count == 0 {
+			return nil
+		}
+
+		var err error
+		skipped, err = o.publish(ctx, tx, rows)
+		return err
+	})
+	if err == nil && skipped > 0 {
+		select {
+		case <-o.Producer.Done():
+			err = ErrClosed
+		default:
+			err = fmt.Errorf("%d outbox messages not published: producer not connected", skipped)
+		}
+	}
+	return count, err
+}
+
+// publish : rows 를 발행하고 결과 기록, 시도도 못한 row 수 반환
+// 읽을 수 없는 row 는 발행하지 않고 실패로 기록 (MaxAttempts 까지 가면 더 안 읽음)
+func (o *Outbox) publish(ctx context.Context, tx sqlx.ExecerContext, rows []outboxRow) (int, error) {
+	ids := make([]int64, 0, len(rows))
+	messages := make([]Message, 0, len(rows))
+	for _, row := range rows {
+		message, err := row.message()
+		if err != nil {
+			if _, err := o.record(ctx, tx, row.ID, err); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		ids = append(ids, row.ID)
+		messages = append(messages, message)
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, o.PublishTimeout)
+	defer cancel()
+
+	skipped := 0
+	for i, publishErr := range o.Producer.PublishBatch(publishCtx, messages) {
+		attempted, err := o.record(ctx, tx, ids[i], publishErr)
+		if err != nil {
+			return 0, err
+		}
+		if !attempted {
+			skipped++
+		}
+	}
+	return skipped, nil
+}
+
+// record : 발행 결과 기록, 연결 대기 / 종료로 시도도 못한건 횟수에 안 넣음 (attempted false)
+// confirm 을 못 받은건 (ErrNotConfirmed) broker 에 들어갔을 수도 있으니 시도로 셈
+func (o *Outbox) record(ctx context.Context, tx sqlx.ExecerContext, id int64, publishErr error) (bool, error) {
+	var err error
+	switch {
+	case publishErr == nil:
+		_, err = tx.ExecContext(ctx, `
+			update mq_outbox
+			set published_at = now(), attempts = attempts + 1, last_error = ''
+			where id = ?`, id)
+	case !errors.Is(publishErr, ErrNotConfirmed) &&
+		(errors.Is(publishErr, context.DeadlineExceeded) || errors.Is(publishErr, context.Canceled) || errors.Is(publishErr, ErrClosed)):
+		return false, nil
+	default:
+		_, err = tx.ExecContext(ctx, `
+			update mq_outbox
+			set attempts = attempts + 1, last_error = left(?, 1024)
+			where id = ?`, publishErr.Error(), id)
+	}
+	return true, err
+}
+
+// encodePublishing : AMQP 로 보낼 수 있는 headers 인지 먼저 확인 (Run 에서 발행할 때 실패하지 않게)
+func encodePublishing(publishing amqp.Publishing) ([]byte, error) {
+	if err := publishing.Headers.Validate(); err != nil {
+		return nil, fmt.Errorf("unsupported outbox headers: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(publishing); err != nil {
+		return nil, fmt.Errorf("failed to encode publishing: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// message : 저장한 Publishing 그대로 (길이 0 인 []byte header 만 nil 로 돌아옴, AMQP 로는 같은 값)
+func (r *outboxRow) message() (Message, error) {
+	var publishing amqp.Publishing
+	if err := gob.NewDecoder(bytes.NewReader(r.Publishing)).Decode(&publishing); err != nil {
+		return Message{}, fmt.Errorf("failed to decode outbox publishing: %w", err)
+	}
+
+	return Message{
+		Exchange:   r.Exchange,
+		RoutingKey: r.RoutingKey,
+		Mandatory:  r.Mandatory,
+		Publishing: publishing,
+	}, nil
+}
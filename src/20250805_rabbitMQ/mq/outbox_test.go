@@ -0,0 +1,107 @@
+package mq
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 테스트 헬퍼: 실행한 update 만 기록 (id, 성공 여부)
+type recordedExec struct {
+	published map[int64]bool
+}
+
+func (e *recordedExec) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	id := args[len(args)-1].(int64)
+	e.published[id] = len(args) == 1 // 실패 기록은 last_error 까지 인자 2개
+	return nil, nil
+}
+
+func TestOutboxPublishingRoundTrip(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	publishing := amqp.Publishing{
+		Headers: amqp.Table{
+			"x-retry-count": int32(2),
+			"nested":        amqp.Table{"count": int64(1)},
+			"raw":           []byte{1, 2, 3},
+			"at":            now,
+			"list":          []interface{}{"a", int16(1)},
+		},
+		ContentType:   "application/json",
+		DeliveryMode:  amqp.Transient,
+		Priority:      3,
+		CorrelationId: "corr",
+		ReplyTo:       "reply",
+		Expiration:    "60000",
+		MessageId:     "msg-1",
+		Timestamp:     now,
+		Type:          "created",
+		AppId:         "outbox",
+		Body:          []byte(`{"id":1}`),
+	}
+
+	data, err := encodePublishing(publishing)
+	require.NoError(t, err)
+
+	row := outboxRow{ID: 1, RoutingKey: "jiemu-worker", Mandatory: true, Publishing: data}
+	message, err := row.message()
+	require.NoError(t, err)
+	assert.Equal(t, publishing, message.Publishing)
+	assert.True(t, message.Mandatory)
+
+	// AMQP 로 못 보내는 값은 저장 전에 거부
+	_, err = encodePublishing(amqp.Publishing{Headers: amqp.Table{"bad": map[string]interface{}{"a": 1}}})
+	assert.Error(t, err)
+}
+
+func TestOutboxRecordAttempts(t *testing.T) {
+	ctx := context.Background()
+	outbox := NewOutbox(nil)
+	exec := &recordedExec{published: make(map[int64]bool)}
+
+	cases := []struct {
+		id        int64
+		err       error
+		attempted bool
+	}{
+		{1, nil, true},
+		{2, ErrNacked, true},
+		{3, fmt.Errorf("%w: %w", ErrNotConfirmed, context.DeadlineExceeded), true}, // broker 에 들어갔을 수도 있음
+		{4, context.DeadlineExceeded, false},                                       // 연결 대기중
+		{5, ErrClosed, false},
+	}
+	for _, c := range cases {
+		attempted, err := outbox.record(ctx, exec, c.id, c.err)
+		require.NoError(t, err)
+		assert.Equal(t, c.attempted, attempted, "id %d", c.id)
+	}
+	assert.Equal(t, map[int64]bool{1: true, 2: false, 3: false}, exec.published)
+}
+
+// producer 가 종료되면 시도 안한 걸로 남기고, 읽을 수 없는 row 만 실패로 기록
+func TestOutboxPublishClosedProducer(t *testing.T) {
+	ctx := context.Background()
+	producer := NewRabbitMqProducer(ctx, testRabbitMQURL)
+	close(producer.done)
+	outbox := NewOutbox(producer)
+	exec := &recordedExec{published: make(map[int64]bool)}
+
+	data, err := encodePublishing(amqp.Publishing{Body: []byte("hello")})
+	require.NoError(t, err)
+	rows := []outboxRow{
+		{ID: 1, Publishing: data},
+		{ID: 2, Publishing: []byte("broken")},
+		{ID: 3, Publishing: data},
+	}
+
+	skipped, err := outbox.publish(ctx, exec, rows)
+	require.NoError(t, err)
+	assert.Equal(t, 2, skipped)
+	assert.Equal(t, map[int64]bool{2: false}, exec.published)
+}
@@ -0,0 +1,309 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// RabbitMQ 발행 (publisher confirms + mandatory return + auto-reconnect)
+// 재연결은 20250930_consumer_connection 의 RabbitMqConsumer 와 같은 패턴
+
+const (
+	publishIDHeader = "x-publish-id" // return 된 메시지를 배치 안에서 찾기 위한 값
+
+	defaultConfirmTimeout = 10 * time.Second
+	maxBatchSize          = 256 // return 버퍼 크기, 배치는 이 단위로 나눠서 confirm 대기
+)
+
+var (
+	ErrReturned     = errors.New("message returned: no route")
+	ErrNacked       = errors.New("message nacked by broker")
+	ErrNotConfirmed = errors.New("connection lost before confirm") // broker 에 들어갔을 수도 있음 (재발행하면 중복 가능)
+	ErrClosed       = errors.New("producer closed")
+)
+
+// Message : 발행할 메시지 하나
+type Message struct {
+	Exchange   string
+	RoutingKey string
+	Mandatory  bool // 라우팅되는 큐가 없으면 ErrReturned
+	Publishing amqp.Publishing
+}
+
+type session struct {
+	conn    *amqp.Connection
+	channel *amqp.Channel
+	returns chan amqp.Return
+}
+
+type RabbitMqProducer struct {
+	baseCtx context.Context
+	amqpURL string
+	done    chan struct{}
+
+	ConfirmTimeout time.Duration             // confirm 대기 시간 (기본 10초)
+	Setup          func(*amqp.Channel) error // exchange / queue 선언 (재연결 시마다 다시 호출)
+
+	mu      sync.Mutex // 발행은 한번에 하나씩 (confirm / return 을 배치 단위로 맞춤)
+	seq     uint64
+	current *session
+	ready   chan struct{} // 연결되면 닫힘, 끊기면 새로 만듦
+}
+
+func NewRabbitMqProducer(ctx context.Context, url string) *RabbitMqProducer {
+	return &RabbitMqProducer{
+		baseCtx:        ctx,
+		amqpURL:        url,
+		done:           make(chan struct{}),
+		ConfirmTimeout: defaultConfirmTimeout,
+		ready:          make(chan struct{}),
+	}
+}
+
+func (v *RabbitMqProducer) Done() <-chan struct{} {
+	return v.done
+}
+
+// connect: 연결 + confirm 모드 채널 + return 등록 + 토폴로지 선언
+func (v *RabbitMqProducer) connect() (*session, error) {
+	// TCP KeepAlive 설정
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+
+	conn, err := amqp.DialConfig(v.amqpURL, amqp.Config{
+		Heartbeat: 10 * time.Second,
+		Locale:    "en_US",
+		Dial: func(network, addr string) (net.Conn, error) {
+			return dialer.Dial(network, addr)
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open a channel: %w", err)
+	}
+
+	if err := channel.Confirm(false); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	// basic.return 은 같은 메시지의 ack 보다 먼저 옴 -> confirm 받은 뒤에 버퍼를 비우면 return 여부를 알 수 있음
+	returns := channel.NotifyReturn(make(chan amqp.Return, maxBatchSize))
+
+	if v.Setup != nil {
+		if err := v.Setup(channel); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to setup topology: %w", err)
+		}
+	}
+
+	return &session{conn: conn, channel: channel, returns: returns}, nil
+}
+
+// Start: Auto-reconnect, 끊기면 Publish 는 다시 연결될 때까지 기다림
+func (v *RabbitMqProducer) Start() {
+	defer close(v.done)
+
+	mqLog := slog.With("mq", "producer")
+
+	reconnectDelay := 5 * time.Second
+	maxReconnectDelay := 5 * time.Minute
+
+	for {
+		select {
+		case <-v.baseCtx.Done():
+			mqLog.Info("Base context cancelled, exiting producer")
+			return
+		default:
+		}
+
+		mqLog.Info("Connecting to RabbitMQ...")
+
+		s, err := v.connect()
+		if err != nil {
+			mqLog.Error("Failed to connect", "error", err, "retry_after", reconnectDelay)
+			if !sleepContext(v.baseCtx, reconnectDelay) {
+				return
+			}
+
+			// Exponential backoff
+			reconnectDelay *= 2
+			if reconnectDelay > maxReconnectDelay {
+				reconnectDelay = maxReconnectDelay
+			}
+			continue
+		}
+
+		reconnectDelay = 5 * time.Second
+		mqLog.Info("Successfully connected to RabbitMQ")
+
+		closed := s.conn.NotifyClose(make(chan *amqp.Error, 1))
+		v.setSession(s)
+
+		select {
+		case <-v.baseCtx.Done():
+			v.clearSession()
+			s.conn.Close()
+			return
+		case amqpErr := <-closed:
+			v.clearSession()
+			mqLog.Warn("Connection closed, reconnecting...", "error", amqpErr, "delay", reconnectDelay)
+		}
+
+		if !sleepContext(v.baseCtx, reconnectDelay) {
+			return
+		}
+	}
+}
+
+func (v *RabbitMqProducer) setSession(s *session) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.current = s
+	close(v.ready)
+}
+
+// clearSession: 발행 중이면 끝날때까지 기다렸다가 교체 (mu)
+func (v *RabbitMqProducer) clearSession() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.current = nil
+	v.ready = make(chan struct{})
+}
+
+// acquire: 연결될 때까지 기다렸다가 mu 를 잡은 상태로 반환
+func (v *RabbitMqProducer) acquire(ctx context.Context) (*session, error) {
+	for {
+		v.mu.Lock()
+		if v.current != nil && !v.current.channel.IsClosed() {
+			return v.current, nil
+		}
+		ready := v.ready
+		v.mu.Unlock()
+
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-v.done:
+			return nil, ErrClosed
+		}
+	}
+}
+
+// Publish: broker 가 confirm 할 때까지 기다림
+func (v *RabbitMqProducer) Publish(ctx context.Context, message Message) error {
+	return v.PublishBatch(ctx, []Message{message})[0]
+}
+
+// PublishBatch: 한번에 발행하고 confirm 은 모아서 기다림, 결과는 메시지 순서대로 (성공이면 nil)
+func (v *RabbitMqProducer) PublishBatch(ctx context.Context, messages []Message) []error {
+	results := make([]error, len(messages))
+	for start := 0; start < len(messages); start += maxBatchSize {
+		end := min(start+maxBatchSize, len(messages))
+		copy(results[start:end], v.publishBatch(ctx, messages[start:end]))
+	}
+	return results
+}
+
+func (v *RabbitMqProducer) publishBatch(ctx context.Context, messages []Message) []error {
+	results := make([]error, len(messages))
+
+	s, err := v.acquire(ctx)
+	if err != nil {
+		for i := range results {
+			results[i] = err
+		}
+		return results
+	}
+	defer v.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, v.ConfirmTimeout)
+	defer cancel()
+
+	confirms := make([]*amqp.DeferredConfirmation, len(messages))
+	ids := make(map[int64]int, len(messages))
+	for i, message := range messages {
+		v.seq++
+		publishing := message.Publishing
+		publishing.Headers = withHeader(publishing.Headers, publishIDHeader, int64(v.seq))
+		ids[int64(v.seq)] = i
+
+		confirms[i], results[i] = s.channel.PublishWithDeferredConfirmWithContext(
+			ctx,
+			message.Exchange,
+			message.RoutingKey,
+			message.Mandatory,
+			false, // immediate (RabbitMQ 미지원)
+			publishing,
+		)
+	}
+
+	for i, confirm := range confirms {
+		if results[i] != nil || confirm == nil {
+			continue
+		}
+
+		acked, err := confirm.WaitContext(ctx)
+		switch {
+		case err != nil:
+			results[i] = fmt.Errorf("%w: %w", ErrNotConfirmed, err)
+		case !acked && s.channel.IsClosed():
+			results[i] = ErrNotConfirmed
+		case !acked:
+			results[i] = ErrNacked
+		}
+	}
+
+	// confirm 까지 받았으면 return 은 이미 버퍼에 들어와 있음
+	for {
+		select {
+		case ret, ok := <-s.returns:
+			if !ok {
+				return results // 채널 닫힘
+			}
+			if id, ok := ret.Headers[publishIDHeader].(int64); ok {
+				if i, ok := ids[id]; ok && results[i] == nil {
+					results[i] = fmt.Errorf("%w: %d %s", ErrReturned, ret.ReplyCode, ret.ReplyText)
+				}
+			}
+		default:
+			return results
+		}
+	}
+}
+
+func withHeader(headers amqp.Table, key string, value any) amqp.Table {
+	copied := make(amqp.Table, len(headers)+1)
+	for k, val := range headers {
+		copied[k] = val
+	}
+	copied[key] = value
+	return copied
+}
+
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -2,33 +2,25 @@ package main
 
 import (
 	"fmt"
-)
-
-type Segment struct {
-	Text string `json:"text"`
-	Idx  int    `json:"idx"`
-}
-
-func ProcessSegments(segments []Segment, trie *WordReplacementTrie) []Segment {
-	processedSegments := make([]Segment, len(segments))
+	"log"
+	"strings"
 
-	for i, segment := range segments {
-		processedSegments[i] = Segment{
-			Idx:  segment.Idx,
-			Text: trie.ReplaceWords(segment.Text), // replace words
-		}
-	}
+	"example/stt/dictionary"
+	"example/stt/subtitle"
+)
 
-	return processedSegments
+// ProcessSegments : 문장 + 단어 단위 치환 (SentenceFrames 도 같이 고쳐야 나중에 다시 나눠도 치환이 유지됨)
+func ProcessSegments(segments []subtitle.SubtitleSegment, trie *dictionary.WordReplacementTrie) []subtitle.SubtitleSegment {
+	return trie.ReplaceSegments(segments)
 }
 
 func main() {
-	jsonData := &Dict{
+	jsonData := &dictionary.Dict{
 		Version: "2025-09-17 14:30:00",
-		Entries: []Entry{
+		Entries: []dictionary.Entry{
 			{
 				Word: "hello",
-				Patterns: []Pattern{
+				Patterns: []dictionary.Pattern{
 					{Value: "hEllo"},
 					{Value: "HELLO"},
 					{Value: "Hello"},
@@ -37,7 +29,7 @@ func main() {
 			},
 			{
 				Word: "world",
-				Patterns: []Pattern{
+				Patterns: []dictionary.Pattern{
 					{Value: "World"},
 					{Value: "WORLD"},
 					{Value: "wrld"},
@@ -45,38 +37,86 @@ func main() {
 			},
 			{
 				Word: "Go",
-				Patterns: []Pattern{
+				Patterns: []dictionary.Pattern{
 					{Value: "golang"},
 					{Value: "GO"},
 					{Value: "go"},
 					{Value: "Golang"},
 				},
 			},
+			{
+				Word: "v$1",
+				Patterns: []dictionary.Pattern{
+					{Type: dictionary.PatternTypeRegex, Value: `\bversion\s+(\d+)\b`, Flags: "i"},
+				},
+			},
+		},
+	}
+
+	// 고객사 사전 (전역 사전 위에 덮어씀)
+	customerDict := &dictionary.Dict{
+		Version: "arirang 2025-11-06",
+		Entries: []dictionary.Entry{
+			{
+				Word:     "Kulture Wave",
+				Patterns: []dictionary.Pattern{{Value: "Culture Wave"}},
+			},
 		},
 	}
 
 	// 트라이 생성 및 초기화
-	trie := NewWordReplacementTrie()
-	trie.BuildTrie(jsonData)
+	trie := dictionary.NewWordReplacementTrie()
+	if err := trie.BuildTrie(jsonData, customerDict); err != nil {
+		log.Fatalf("invalid dictionary: %v", err)
+	}
 
 	// 테스트용 세그먼트 데이터
-	segments := []Segment{
-		{Idx: 1, Text: "hEllo, World! This is a test."},
-		{Idx: 2, Text: "I love golang and GO programming."},
-		{Idx: 3, Text: "HELLO wrld, how are you?"},
+	segments := []subtitle.SubtitleSegment{
+		newSegment(1, 0, "hEllo,", "World!", "This", "is", "a", "test."),
+		newSegment(2, 3, "I", "love", "golang", "and", "GO", "programming."),
+		newSegment(3, 6, "HELLO", "wrld,", "how", "are", "you?"),
+		newSegment(4, 9, "Welcome", "to", "culture", "wave,", "Version", "2", "is", "out."),
 	}
 
 	processedSegments := ProcessSegments(segments, trie)
 
 	fmt.Println("Original segments:")
 	for _, segment := range segments {
-		fmt.Printf("Idx: %d, Text: %s\n", segment.Idx, segment.Text)
+		printSegment(segment)
 	}
 
 	fmt.Println("========================")
 
 	fmt.Println("Processed segments:")
 	for _, segment := range processedSegments {
-		fmt.Printf("Idx: %d, Text: %s\n", segment.Idx, segment.Text)
+		printSegment(segment)
+	}
+}
+
+// newSegment : 단어마다 0.5초씩 (whisper 처럼 단어 앞에 공백)
+func newSegment(idx int, start float64, words ...string) subtitle.SubtitleSegment {
+	frames := make([]subtitle.SentenceFrames, len(words))
+	for i, word := range words {
+		frames[i] = subtitle.SentenceFrames{
+			WordIdx:       i,
+			Word:          " " + word,
+			WordStartTime: start + float64(i)*0.5,
+			WordEndTime:   start + float64(i+1)*0.5,
+		}
+	}
+
+	return subtitle.SubtitleSegment{
+		Idx:            idx,
+		StartTime:      start,
+		EndTime:        start + float64(len(words))*0.5,
+		Sentence:       strings.Join(words, " "),
+		SentenceFrames: frames,
+	}
+}
+
+func printSegment(segment subtitle.SubtitleSegment) {
+	fmt.Printf("Idx: %d, Text: %s\n", segment.Idx, segment.Sentence)
+	for _, frame := range segment.SentenceFrames {
+		fmt.Printf("    [%.2f-%.2f]%s\n", frame.WordStartTime, frame.WordEndTime, frame.Word)
 	}
 }
@@ -16,11 +16,19 @@ import "C"
 // 이제: go run *.go  <- 그냥 이렇게만 실행!
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/streamer45/silero-vad-go/speech"
+
+	"example/stt/ffmpeg"
+	"example/stt/vad/silero"
 )
 
 /**
@@ -74,9 +82,11 @@ func main() {
 	//	return
 	//}
 
+	ctx := context.Background()
+
 	totalStart := time.Now()
 	wavExtractStart := time.Now()
-	wavAudioPath, err := ExtractAudioToWav(os.Args[1])
+	wavAudioPath, err := ffmpeg.ExtractAudioToWav(ctx, os.Args[1])
 
 	if err != nil {
 		log.Fatalf("Error extracting audio: %v", err)
@@ -89,15 +99,29 @@ func main() {
 
 	filterStart := time.Now()
 
-	_, resultFilterPath, err := VadFilter(wavAudioPath)
+	// 보수적 파라미터 (Threshold 0.4~0.6, MinSilence 300~800, Pad 50~200 A/B)
+	vadConfig := &speech.DetectorConfig{
+		ModelPath:            "silero_vad.onnx",
+		Threshold:            0.4,
+		MinSilenceDurationMs: 700,
+		SpeechPadMs:          200,
+	}
+
+	ext := filepath.Ext(wavAudioPath)
+	resultFilterPath := strings.TrimSuffix(wavAudioPath, ext) + "_vad_filtered" + ext
+
+	filterSegments, _, _, err := silero.Filter(vadConfig, wavAudioPath, resultFilterPath)
+	if err != nil {
+		log.Fatalf("Error filtering audio: %v", err)
+	}
 	filterDuration := time.Since(filterStart)
-	fmt.Printf("⏱️ 무음구간 변환 시간: %v\n", filterDuration)
+	fmt.Printf("⏱️ 무음구간 변환 시간: %v (음성 구간 %d개)\n", filterDuration, len(filterSegments))
 	fmt.Printf("📁 결과 오디오 파일: %s\n", resultFilterPath)
 
 	extractStart := time.Now()
-	audioPath, err := ExtractAudioToMP3(resultFilterPath)
-	if err != nil {
-		fmt.Printf("Error extracting audio to MP3: %s", err)
+	audioPath := strings.TrimSuffix(resultFilterPath, ext) + "_extracted.webm"
+	if err = ffmpeg.ExtractAudio(ctx, resultFilterPath, audioPath); err != nil {
+		fmt.Printf("Error extracting audio to webm: %s", err)
 		return
 	}
 	fmt.Printf("result audioPath : %s\n", audioPath)
@@ -107,8 +131,10 @@ func main() {
 
 	//apiStart := time.Now()
 	//
-	//TranscribeAudio(config, audioPath, filterSegments)
-	////TranscribeAudio(config, audioPath, make([]speech.Segment, 0))
+	//client := whisper.NewClient(config.OpenAIKey)
+	//response, err := client.Transcribe(ctx, audioPath, filepath.Base(audioPath))
+	//subtitles := subtitle.FilterBySpeech(whisper.ConvertWhisperResponse(response), filterSegments, 0.5)
+	//subtitles = subtitle.RemoveDuplicateTexts(subtitles)
 	//
 	//apiDuration := time.Since(apiStart)
 	//fmt.Printf("⏱️  API 호출 시간: %v\n", apiDuration)
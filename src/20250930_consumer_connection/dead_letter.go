@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// 실패한 메시지 재시도 + Dead Letter Queue
+//
+//	<queue> --(실패)--> <queue>.retry.<delay>ms --(TTL 만료)--> <queue>
+//	        --(MaxAttempts 실패)--> <queue>.dlx (exchange) --> <queue>.dlq
+//
+// 재시도 횟수는 x-death 대신 직접 붙이는 헤더로 셈 (TTL 큐마다 x-death 항목이 따로 쌓여서 합산이 번거로움)
+
+const (
+	RetryCountHeader    = "x-retry-count"    // 지금까지 실패한 횟수
+	FailureReasonHeader = "x-failure-reason" // DLQ 로 갈때 마지막 에러
+	FailedAtHeader      = "x-failed-at"
+	OriginalQueueHeader = "x-original-queue"
+
+	defaultMaxAttempts = 5
+	publishTimeout     = 10 * time.Second
+)
+
+var defaultRetryDelays = []time.Duration{5 * time.Second, 30 * time.Second, 2 * time.Minute, 10 * time.Minute}
+
+func (v *RabbitMqConsumer) DeadLetterExchange() string {
+	return v.queueName + ".dlx"
+}
+
+func (v *RabbitMqConsumer) DeadLetterQueue() string {
+	return v.queueName + ".dlq"
+}
+
+// RetryQueue : delay 별 TTL 큐, 만료되면 default exchange 로 원래 큐에 다시 들어감
+func (v *RabbitMqConsumer) RetryQueue(delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%dms", v.queueName, delay.Milliseconds())
+}
+
+// retryDelay : n 번째 재시도 대기 시간 (RetryDelays 가 모자라면 마지막 값)
+func (v *RabbitMqConsumer) retryDelay(attempt int) time.Duration {
+	delays := v.RetryDelays
+	if len(delays) == 0 {
+		delays = defaultRetryDelays
+	}
+	return delays[min(attempt, len(delays))-1]
+}
+
+// declareDeadLetter : DLX / DLQ / 재시도 TTL 큐 선언 (재연결 시마다 다시 선언)
+func (v *RabbitMqConsumer) declareDeadLetter(channel *amqp.Channel) error {
+	if err := channel.ExchangeDeclare(v.DeadLetterExchange(), amqp.ExchangeDirect, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare dead letter exchange: %w", err)
+	}
+	if _, err := channel.QueueDeclare(v.DeadLetterQueue(), true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare dead letter queue: %w", err)
+	}
+	if err := channel.QueueBind(v.DeadLetterQueue(), v.queueName, v.DeadLetterExchange(), false, nil); err != nil {
+		return fmt.Errorf("failed to bind dead letter queue: %w", err)
+	}
+
+	for attempt := 1; attempt < v.MaxAttempts; attempt++ {
+		delay := v.retryDelay(attempt)
+		if _, err := channel.QueueDeclare(
+			v.RetryQueue(delay),
+			true,  // durable
+			false, // autoDelete
+			false, // exclusive
+			false, // noWait
+			amqp.Table{
+				"x-message-ttl":             delay.Milliseconds(),
+				"x-dead-letter-exchange":    "", // default exchange
+				"x-dead-letter-routing-key": v.queueName,
+			},
+		); err != nil {
+			return fmt.Errorf("failed to declare retry queue: %w", err)
+		}
+	}
+	return nil
+}
+
+// retryOrDeadLetter : 실패한 메시지를 재시도 큐나 DLQ 로 옮기고 Ack
+// 옮기지 못하면 유실되지 않게 기존처럼 Nack(requeue)
+func (v *RabbitMqConsumer) retryOrDeadLetter(channel *amqp.Channel, message amqp.Delivery, cause error, reqLog *slog.Logger) {
+	attempt := retryCount(message) + 1
+
+	publishing := republishing(message)
+	publishing.Headers[RetryCountHeader] = int32(attempt)
+
+	exchange, key := "", ""
+	if attempt < v.MaxAttempts {
+		delay := v.retryDelay(attempt)
+		key = v.RetryQueue(delay)
+		reqLog.Warn("Retry scheduled", "attempt", attempt, "max_attempts", v.MaxAttempts, "delay", delay)
+	} else {
+		publishing.Headers[FailureReasonHeader] = cause.Error()
+		publishing.Headers[FailedAtHeader] = time.Now().UTC().Format(time.RFC3339)
+		publishing.Headers[OriginalQueueHeader] = v.queueName
+		exchange, key = v.DeadLetterExchange(), v.queueName
+		reqLog.Error("Max attempts exceeded, moving to dead letter queue", "attempt", attempt, "dlq", v.DeadLetterQueue())
+	}
+
+	if err := v.publishConfirmed(channel, exchange, key, publishing); err != nil {
+		reqLog.Error("Failed to publish for retry, requeueing", "error", err)
+		if nackErr := message.Nack(false, true); nackErr != nil {
+			reqLog.Error("Failed to nack message", "error", nackErr)
+		}
+		return
+	}
+
+	if err := message.Ack(false); err != nil {
+		reqLog.Error("Failed to ack message", "error", err)
+	}
+}
+
+// publishConfirmed : broker 가 받은걸 확인한 다음에 원본을 Ack 해야 유실이 없음 (channel 은 confirm 모드)
+func (v *RabbitMqConsumer) publishConfirmed(channel *amqp.Channel, exchange, key string, publishing amqp.Publishing) error {
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	confirm, err := channel.PublishWithDeferredConfirmWithContext(ctx, exchange, key, false, false, publishing)
+	if err != nil {
+		return err
+	}
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !acked {
+		return errors.New("publish nacked by broker")
+	}
+	return nil
+}
+
+func retryCount(message amqp.Delivery) int {
+	switch count := message.Headers[RetryCountHeader].(type) {
+	case int32:
+		return int(count)
+	case int64:
+		return int(count)
+	case int:
+		return count
+	default:
+		return 0
+	}
+}
+
+// republishing : 원본 속성 그대로 복사 (Expiration 은 TTL 큐랑 겹치니까 제외)
+func republishing(message amqp.Delivery) amqp.Publishing {
+	headers := make(amqp.Table, len(message.Headers)+4)
+	for k, val := range message.Headers {
+		headers[k] = val
+	}
+
+	return amqp.Publishing{
+		Headers:         headers,
+		ContentType:     message.ContentType,
+		ContentEncoding: message.ContentEncoding,
+		DeliveryMode:    amqp.Persistent,
+		Priority:        message.Priority,
+		CorrelationId:   message.CorrelationId,
+		ReplyTo:         message.ReplyTo,
+		MessageId:       message.MessageId,
+		Timestamp:       message.Timestamp,
+		Type:            message.Type,
+		UserId:          message.UserId,
+		AppId:           message.AppId,
+		Body:            message.Body,
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 테스트 헬퍼: 재시도 큐 / DLQ / DLX 정리
+func cleanupDeadLetter(tb testing.TB, consumer *RabbitMqConsumer) {
+	conn, err := amqp.Dial(testRabbitMQURL)
+	require.NoError(tb, err)
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	require.NoError(tb, err)
+	defer ch.Close()
+
+	queues := []string{consumer.DeadLetterQueue()}
+	for _, delay := range consumer.RetryDelays {
+		queues = append(queues, consumer.RetryQueue(delay))
+	}
+	for _, queue := range queues {
+		if _, err := ch.QueueDelete(queue, false, false, false); err != nil {
+			tb.Logf("Queue delete failed (may not exist): %v", err)
+		}
+	}
+	if err := ch.ExchangeDelete(consumer.DeadLetterExchange(), false, false); err != nil {
+		tb.Logf("Exchange delete failed (may not exist): %v", err)
+	}
+}
+
+// 테스트 헬퍼: DLQ 에서 메시지 하나 꺼내기
+func getDeadLetter(tb testing.TB, consumer *RabbitMqConsumer) (amqp.Delivery, bool) {
+	conn, err := amqp.Dial(testRabbitMQURL)
+	require.NoError(tb, err)
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	require.NoError(tb, err)
+	defer ch.Close()
+
+	message, ok, err := ch.Get(consumer.DeadLetterQueue(), true)
+	require.NoError(tb, err)
+	return message, ok
+}
+
+// Test 6: 지연 재시도 후 성공
+func TestRetryWithDelay(t *testing.T) {
+	queueName := testQueueName + "-retry-delay"
+	cleanupQueue(t, queueName)
+	defer cleanupQueue(t, queueName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	publishMessages(t, queueName, 1)
+
+	consumer := NewRabbitMqConsumer(ctx, testRabbitMQURL, queueName, 1, 10*time.Second)
+	consumer.MaxAttempts = 3
+	consumer.RetryDelays = []time.Duration{500 * time.Millisecond, time.Second}
+	defer cleanupDeadLetter(t, consumer)
+
+	var mu sync.Mutex
+	attempts := make([]time.Time, 0)
+	consumer.ProcessFunc = func(ctx context.Context, message amqp.Delivery) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		attempts = append(attempts, time.Now())
+		if len(attempts) < 3 {
+			// 두 번째 시도까지 실패
+			return fmt.Errorf("simulated processing error %d", len(attempts))
+		}
+		assert.Equal(t, 2, retryCount(message))
+		return nil
+	}
+
+	go consumer.Start()
+
+	assert.Eventually(t, func() bool {
+		return consumer.ProcessCount.Load() == 3
+	}, 15*time.Second, 100*time.Millisecond, "Should succeed on third attempt")
+
+	cancel()
+	<-consumer.Done()
+
+	// 재시도마다 TTL 만큼 기다렸다가 다시 들어옴
+	mu.Lock()
+	defer mu.Unlock()
+	assert.GreaterOrEqual(t, attempts[1].Sub(attempts[0]), 500*time.Millisecond)
+	assert.GreaterOrEqual(t, attempts[2].Sub(attempts[1]), time.Second)
+
+	_, ok := getDeadLetter(t, consumer)
+	assert.False(t, ok, "Dead letter queue should be empty")
+}
+
+// Test 7: MaxAttempts 를 다 실패하면 DLQ 로 (무한 재전달 안됨)
+func TestDeadLetterAfterMaxAttempts(t *testing.T) {
+	queueName := testQueueName + "-dead-letter"
+	cleanupQueue(t, queueName)
+	defer cleanupQueue(t, queueName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	publishMessages(t, queueName, 1)
+
+	consumer := NewRabbitMqConsumer(ctx, testRabbitMQURL, queueName, 1, 10*time.Second)
+	consumer.MaxAttempts = 3
+	consumer.RetryDelays = []time.Duration{200 * time.Millisecond}
+	defer cleanupDeadLetter(t, consumer)
+
+	consumer.ProcessFunc = func(ctx context.Context, message amqp.Delivery) error {
+		return fmt.Errorf("poison message")
+	}
+
+	go consumer.Start()
+
+	var deadLetter amqp.Delivery
+	assert.Eventually(t, func() bool {
+		message, ok := getDeadLetter(t, consumer)
+		deadLetter = message
+		return ok
+	}, 15*time.Second, 100*time.Millisecond, "Should move to dead letter queue")
+
+	// DLQ 로 간 다음에는 더 이상 처리하지 않음
+	time.Sleep(1 * time.Second)
+	assert.Equal(t, int32(3), consumer.ProcessCount.Load())
+
+	cancel()
+	<-consumer.Done()
+
+	assert.Equal(t, "msg-0", deadLetter.MessageId)
+	assert.Equal(t, "test message 0", string(deadLetter.Body))
+	assert.Equal(t, 3, retryCount(deadLetter))
+	assert.Equal(t, "poison message", deadLetter.Headers[FailureReasonHeader])
+	assert.Equal(t, queueName, deadLetter.Headers[OriginalQueueHeader])
+	assert.NotEmpty(t, deadLetter.Headers[FailedAtHeader])
+}
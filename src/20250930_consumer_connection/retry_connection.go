@@ -27,6 +27,9 @@ type RabbitMqConsumer struct {
 	done         chan struct{}
 	ProcessFunc  func(ctx context.Context, message amqp.Delivery) error // 테스트용
 	ProcessCount atomic.Int32
+
+	MaxAttempts int             // 첫 시도 포함 최대 처리 횟수, 다 실패하면 DLQ 로 (Start 전에 설정)
+	RetryDelays []time.Duration // n 번째 재시도 대기 시간, 모자라면 마지막 값 (Start 전에 설정)
 }
 
 func NewRabbitMqConsumer(ctx context.Context, url, queueName string, prefetch int, jobTimeout time.Duration) *RabbitMqConsumer {
@@ -37,6 +40,9 @@ func NewRabbitMqConsumer(ctx context.Context, url, queueName string, prefetch in
 		prefetch:   prefetch,
 		jobTimeout: jobTimeout,
 		done:       make(chan struct{}),
+
+		MaxAttempts: defaultMaxAttempts,
+		RetryDelays: defaultRetryDelays,
 	}
 }
 
@@ -84,6 +90,20 @@ func (v *RabbitMqConsumer) setup() (*amqp.Connection, *amqp.Channel, error) {
 		return nil, nil, fmt.Errorf("failed to declare a queue: %w", err)
 	}
 
+	// 재시도 TTL 큐 + DLX / DLQ
+	if err := v.declareDeadLetter(channel); err != nil {
+		channel.Close()
+		conn.Close()
+		return nil, nil, err
+	}
+
+	// 재시도 / DLQ 로 옮길때 publish 확인용
+	if err := channel.Confirm(false); err != nil {
+		channel.Close()
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
 	// QoS 설정 (재연결 시마다 다시 설정)
 	if err := channel.Qos(v.prefetch, 0, false); err != nil {
 		channel.Close()
@@ -95,10 +115,10 @@ func (v *RabbitMqConsumer) setup() (*amqp.Connection, *amqp.Channel, error) {
 }
 
 // consume: setup + consumer 등록
-func (v *RabbitMqConsumer) consume() (*amqp.Connection, <-chan amqp.Delivery, error) {
+func (v *RabbitMqConsumer) consume() (*amqp.Connection, *amqp.Channel, <-chan amqp.Delivery, error) {
 	conn, channel, err := v.setup()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	deliveries, err := channel.ConsumeWithContext(
@@ -114,10 +134,10 @@ func (v *RabbitMqConsumer) consume() (*amqp.Connection, <-chan amqp.Delivery, er
 	if err != nil {
 		channel.Close()
 		conn.Close()
-		return nil, nil, fmt.Errorf("failed to register a consumer: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to register a consumer: %w", err)
 	}
 
-	return conn, deliveries, nil
+	return conn, channel, deliveries, nil
 }
 
 // Start: Auto-reconnect + Worker Pool 패턴
@@ -141,7 +161,7 @@ func (v *RabbitMqConsumer) Start() {
 		mqLog.Info("Connecting to RabbitMQ...", "queue", v.queueName)
 
 		// 연결 시도
-		conn, deliveries, err := v.consume()
+		conn, channel, deliveries, err := v.consume()
 		if err != nil {
 			mqLog.Error("Failed to connect", "error", err, "retry_after", reconnectDelay)
 			time.Sleep(reconnectDelay)
@@ -160,7 +180,7 @@ func (v *RabbitMqConsumer) Start() {
 
 		// 워커 풀로 메시지 처리
 		// deliveries 채널이 닫히면 모든 워커가 종료되고 여기로 돌아옴
-		v.runWorkerPool(deliveries, mqLog)
+		v.runWorkerPool(channel, deliveries, mqLog)
 
 		// 연결 정리
 		conn.Close()
@@ -170,7 +190,7 @@ func (v *RabbitMqConsumer) Start() {
 }
 
 // runWorkerPool: 워커 풀 실행 (기존 Start 로직과 동일)
-func (v *RabbitMqConsumer) runWorkerPool(deliveries <-chan amqp.Delivery, mqLog *slog.Logger) {
+func (v *RabbitMqConsumer) runWorkerPool(channel *amqp.Channel, deliveries <-chan amqp.Delivery, mqLog *slog.Logger) {
 	ctx, cancel := context.WithCancel(v.baseCtx)
 	defer cancel()
 
@@ -195,7 +215,7 @@ func (v *RabbitMqConsumer) runWorkerPool(deliveries <-chan amqp.Delivery, mqLog
 					}
 
 					// 메시지 처리 (20분 걸려도 OK - 다른 워커들이 계속 처리함)
-					if shouldStop := v.handleMessage(message, mqLog); shouldStop {
+					if shouldStop := v.handleMessage(channel, message, mqLog); shouldStop {
 						mqLog.Debug("stop consuming due to shutdown or handling result", "work_id", workId)
 						return
 					}
@@ -223,7 +243,7 @@ func (v *RabbitMqConsumer) runWorkerPool(deliveries <-chan amqp.Delivery, mqLog
 }
 
 // handleMessage: 개별 메시지 처리
-func (v *RabbitMqConsumer) handleMessage(message amqp.Delivery, mqLog *slog.Logger) bool {
+func (v *RabbitMqConsumer) handleMessage(channel *amqp.Channel, message amqp.Delivery, mqLog *slog.Logger) bool {
 	// 타임아웃 컨텍스트 생성
 	ctx, cancel := context.WithTimeout(v.baseCtx, v.jobTimeout)
 	defer cancel()
@@ -231,6 +251,7 @@ func (v *RabbitMqConsumer) handleMessage(message amqp.Delivery, mqLog *slog.Logg
 	reqLog := mqLog.With(
 		"message_id", message.MessageId,
 		"delivery_tag", message.DeliveryTag,
+		"retry_count", retryCount(message),
 	)
 
 	reqLog.Info("Processing message", "body_length", len(message.Body))
@@ -239,10 +260,16 @@ func (v *RabbitMqConsumer) handleMessage(message amqp.Delivery, mqLog *slog.Logg
 	if err := v.processBusinessLogic(ctx, message); err != nil {
 		reqLog.Error("Failed to process message", "error", err)
 
-		// 처리 실패 시 재큐잉
-		if nackErr := message.Nack(false, true); nackErr != nil {
-			reqLog.Error("Failed to nack message", "error", nackErr)
+		// 종료 중에 취소된건 실패 횟수에 안 넣고 바로 재큐잉
+		if v.baseCtx.Err() != nil {
+			if nackErr := message.Nack(false, true); nackErr != nil {
+				reqLog.Error("Failed to nack message", "error", nackErr)
+			}
+			return false
 		}
+
+		// 처리 실패 시 지연 재시도, MaxAttempts 넘으면 DLQ
+		v.retryOrDeadLetter(channel, message, err, reqLog)
 		return false
 	}
 
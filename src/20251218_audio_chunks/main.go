@@ -8,58 +8,130 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/streamer45/silero-vad-go/speech"
+
+	"example/stt/chunking"
+	"example/stt/correction"
+	"example/stt/diarize"
+	"example/stt/diarize/onnx"
+	"example/stt/dictionary"
+	"example/stt/ffmpeg"
+	"example/stt/subtitle"
+	"example/stt/vad"
+	"example/stt/vad/silero"
+	"example/stt/whisper"
 )
 
 type Config struct {
-	OpenAIKey string `json:"openai-key"`
+	OpenAIKey          string   `json:"openai-key"`
+	Language           string   `json:"language"`            // whisper 요청 / 자막 줄바꿈 언어 (ISO-639-1), 비어있으면 "en"
+	DiarizeModelPath   string   `json:"diarize-model-path"`  // 비어있으면 화자 분리 생략
+	HallucinationRetry *int     `json:"hallucination-retry"` // 환각 구간 재요청 반복 횟수 (없으면 기본값, 0이면 재요청 안함)
+	SubtitleFormats    []string `json:"subtitle-formats"`    // 추가로 저장할 자막 포맷 (srt, vtt, ass, ttml)
+
+	Dictionary    *DictionaryConfig    `json:"dictionary"`     // 없으면 사전 치환 생략
+	LLMCorrection *LLMCorrectionConfig `json:"llm-correction"` // 없으면 LLM 교정 생략
+}
+
+// DictionaryConfig : 사전 서비스(20260210_dictionary_service) 의 file 저장소를 그대로 읽음
+type DictionaryConfig struct {
+	Dir  string `json:"dir"`  // 사전 디렉토리 (DICT_DIR)
+	Name string `json:"name"` // 고객사 사전 이름, 없거나 저장소에 없으면 전역 사전
+}
+
+// LLMCorrectionConfig : LLMCorrectSentence 채우는 교정 단계 설정
+type LLMCorrectionConfig struct {
+	Provider     string  `json:"provider"`       // gemini, openai, stub
+	APIKey       string  `json:"api-key"`        // openai 는 비어있으면 openai-key 사용
+	Model        string  `json:"model"`          // 비어있으면 provider 기본 모델
+	Instruction  string  `json:"instruction"`    // DefaultInstruction 뒤에 붙일 고객사 규칙 (예: Culture Wave -> Kulture Wave)
+	MaxEditRatio float64 `json:"max-edit-ratio"` // 0 이면 기본값
+}
+
+type Job struct {
+	RId               string
+	OriginalAudioPath string
+	WavAudioPath      string // mp4 -> wav
+	FilteredAudioPath string // 무음구간 필터 적용파일 경로
+
+	HallucinationRetry whisper.HallucinationRetryConfig
+}
+
+// JobReport : 작업 결과 요약 (청크 성공/실패, 환각 구간 처리 내역)
+type JobReport struct {
+	RId                string                     `json:"rid"`
+	TotalChunks        int                        `json:"total_chunks"`
+	SuccessChunks      int                        `json:"success_chunks"`
+	FailedRanges       []chunking.AudioChunk      `json:"failed_ranges"`
+	HallucinationFixes []whisper.HallucinationFix `json:"hallucination_fixes"` // 원본 타임라인 기준
+	Correction         *correction.Report         `json:"correction,omitempty"`
 }
 
 type ChunkResult struct {
-	Chunk              AudioChunk
+	Chunk              chunking.AudioChunk
 	ChunkPath          string
-	WhisperResponse    *WhisperResponse
+	WhisperResponse    *whisper.WhisperResponse
 	TranscriptionError error
 	Error              error
 	Duration           time.Duration
+	HallucinationFixes []whisper.HallucinationFix // 청크 기준 타임스탬프
 }
 
-func LoadConfig() error {
-	WhisperConfig = &Config{}
+func LoadConfig() (*Config, error) {
+	config := &Config{}
 
 	configFile, err := os.Open("./config.json")
 	if err != nil {
-		return fmt.Errorf("Error opening config file: %s", err)
+		return nil, fmt.Errorf("Error opening config file: %s", err)
 	}
 	defer configFile.Close()
 
 	decoder := json.NewDecoder(configFile)
-	err = decoder.Decode(WhisperConfig)
+	err = decoder.Decode(config)
 	if err != nil {
-		return fmt.Errorf("Error parsing config file: %s", err)
+		return nil, fmt.Errorf("Error parsing config file: %s", err)
 	}
 
-	if WhisperConfig.OpenAIKey == "" {
-		return fmt.Errorf("No openai-key found in config file")
+	if config.OpenAIKey == "" {
+		return nil, fmt.Errorf("No openai-key found in config file")
 	}
 
-	return nil
+	return config, nil
 }
 
 func main() {
-	if LoadConfig() != nil {
-		log.Fatal("Error loading config")
+	appConfig, err := LoadConfig()
+	if err != nil {
+		log.Fatal("Error loading config: ", err)
 	}
 
 	job := &Job{
-		OriginalAudioPath: "./sample/e3.mp4",
-		RId:               "jiemu-test",
+		OriginalAudioPath:  "./sample/e3.mp4",
+		RId:                "jiemu-test",
+		HallucinationRetry: whisper.DefaultHallucinationRetryConfig(),
+	}
+	if appConfig.HallucinationRetry != nil {
+		job.HallucinationRetry.MaxRetry = *appConfig.HallucinationRetry
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 사전은 전사 전에 읽어서 잘못된 설정이면 바로 종료
+	var dict *dictionary.WordReplacementTrie
+	if appConfig.Dictionary != nil {
+		dict, err = loadDictionary(ctx, appConfig.Dictionary)
+		if err != nil {
+			log.Fatal("Error loading dictionary: ", err)
+		}
+		log.Printf("Dictionary loaded: %v\n", dict.Versions)
 	}
 
-	wavPath, err := ExtractAudioToWav(job.OriginalAudioPath)
+	wavPath, err := ffmpeg.ExtractAudioToWav(ctx, job.OriginalAudioPath)
 	if err != nil {
 		log.Fatalf("Error extracting audio from wav: %s", err)
 	}
@@ -73,19 +145,31 @@ func main() {
 		Threshold: 0.5,
 	}
 
-	//segments, _, totalDuration, err := VadFilter(config, job)
-	segments, totalDuration, err := VadFilterDetectOnly(config, job)
+	//segments, _, totalDuration, err := silero.Filter(config, job.WavAudioPath, job.FilteredAudioPath)
+	//segments, totalDuration, err := silero.DetectOnly(config, job.WavAudioPath)
+	segments, totalDuration, err := silero.DetectOnlyStream(config, job.WavAudioPath, func(seg vad.Segment) {
+		log.Printf("[VAD] speech %.3fs - %.3fs\n", seg.SpeechStartAt, seg.SpeechEndAt)
+	})
 	if err != nil {
 		log.Fatal("Error creating VAD filter: ", err)
 	}
 
-	chunkingConfig := ChunkingConfig{
+	// 화자 분리 (옵션)
+	var speakerTurns []diarize.SpeakerSegment
+	if appConfig.DiarizeModelPath != "" {
+		speakerTurns, err = runDiarization(appConfig.DiarizeModelPath, job.WavAudioPath, segments)
+		if err != nil {
+			log.Printf("Warning: Diarization failed, continuing without speaker labels: %v\n", err)
+		}
+	}
+
+	chunkingConfig := chunking.ChunkingConfig{
 		MinDurationSec: 10.0,  // 10초
 		MaxDurationSec: 600.0, // 10분
 		OverlapSec:     1.5,   // 1.5초
 	}
 
-	chunks := CreateAudioChunks(segments, chunkingConfig, totalDuration)
+	chunks := chunking.CreateAudioChunks(segments, chunkingConfig, totalDuration)
 
 	log.Printf("Created %d chunks from %d VAD segments\n", len(chunks), len(segments))
 
@@ -95,23 +179,30 @@ func main() {
 	}
 	log.Printf("Output directory: %s\n", outputDir)
 
-	if err := SaveChunkInfo(chunks, outputDir); err != nil {
+	if err := chunking.SaveChunkInfo(chunks, outputDir); err != nil {
 		log.Printf("Warning: Failed to save chunk info: %v\n", err)
 	}
 
 	// 리소스 모니터 초기화
 	monitor := NewResourceMonitor()
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
 
 	// 500ms 마다 리소스 수집
 	monitor.StartMonitoring(ctx, 500*time.Millisecond)
 	monitor.SetTotalChunks(len(chunks))
 
 	// 청크 작업을 전달할 채널과 결과를 받을 채널
-	chunkJobs := make(chan AudioChunk, len(chunks))
+	chunkJobs := make(chan chunking.AudioChunk, len(chunks))
 	results := make(chan ChunkResult, len(chunks))
 
+	// 실패 청크 재시도 및 완료 정책
+	retryConfig := chunking.RetryConfig{
+		Policy:         chunking.CompletionPolicyQuorum,
+		QuorumRatio:    0.5, // 과반수 성공 시 진행
+		MaxSplitDepth:  2,
+		SplitParts:     2,
+		MinSubChunkSec: 5.0,
+	}
+
 	// 워커 수 (CPU 코어 수만큼 또는 원하는 수로 설정)
 	numWorkers := 3
 	if len(chunks) < numWorkers {
@@ -123,7 +214,10 @@ func main() {
 
 	log.Printf("🚀 Starting chunk processing with %d workers (CPU cores: %d)\n", numWorkers, runtime.NumCPU())
 
-	translator := NewTranslatorWhisper(segments)
+	client := whisper.NewClient(appConfig.OpenAIKey)
+	if appConfig.Language != "" {
+		client.Language = appConfig.Language
+	}
 
 	// 워커 고루틴 시작
 	for w := 0; w < numWorkers; w++ {
@@ -132,56 +226,19 @@ func main() {
 			defer monitor.WorkerEnd(workerID)
 
 			for chunk := range chunkJobs {
-				chunkStartTime := time.Now()
 				log.Printf("[Worker %d] Processing chunk #%d (%.2fs - %.2fs)\n",
 					workerID, chunk.Index, chunk.StartSec, chunk.EndSec)
 
-				result := ChunkResult{
-					Chunk:    chunk,
-					Duration: 0,
-				}
-
-				// 1. 청크 오디오 파일 생성
-				chunkPath, err := ExtractChunkAudio(job.WavAudioPath, chunk, outputDir)
-				result.ChunkPath = chunkPath
+				result := processChunk(ctx, job, chunk, outputDir, client)
 
-				if err != nil {
-					result.Error = err
-					result.Duration = time.Since(chunkStartTime)
-					monitor.ChunkProcessed(false, result.Duration)
-					results <- result
-					continue
-				}
-
-				log.Printf("[Worker %d] Chunk #%d file created, calling Whisper API...\n", workerID, chunk.Index)
-
-				// 2. Whisper API 호출 (webm 변환 포함)
-				webmPath := strings.TrimSuffix(chunkPath, filepath.Ext(chunkPath)) + ".webm"
-
-				// WAV -> WebM 변환
-				extractErr := ExtractAudio(ctx, chunkPath, webmPath)
-				if extractErr != nil {
-					result.TranscriptionError = fmt.Errorf("webm conversion failed: %w", extractErr)
-					result.Duration = time.Since(chunkStartTime)
-					monitor.ChunkProcessed(false, result.Duration)
-					results <- result
-					continue
-				}
-
-				// Whisper API 호출
-				whisperResp, whisperErr := translator.CallWhisperApi(ctx, webmPath, job)
-				result.WhisperResponse = whisperResp
-				result.TranscriptionError = whisperErr
-
-				if whisperErr != nil {
-					log.Printf("[Worker %d] ⚠️  Chunk #%d Whisper API failed: %v\n", workerID, chunk.Index, whisperErr)
-				} else {
+				if result.TranscriptionError != nil {
+					log.Printf("[Worker %d] ⚠️  Chunk #%d Whisper API failed: %v\n", workerID, chunk.Index, result.TranscriptionError)
+				} else if result.Error == nil {
 					log.Printf("[Worker %d] ✓ Chunk #%d transcription completed (%d segments)\n",
-						workerID, chunk.Index, len(whisperResp.Segments))
+						workerID, chunk.Index, len(result.WhisperResponse.Segments))
 				}
 
-				result.Duration = time.Since(chunkStartTime)
-				monitor.ChunkProcessed(whisperErr == nil && err == nil, result.Duration)
+				monitor.ChunkProcessed(!result.HasError(), result.Duration)
 
 				results <- result
 			}
@@ -206,7 +263,7 @@ func main() {
 		select {
 		case result := <-results:
 			receivedCount++
-			if result.Error != nil || result.TranscriptionError != nil {
+			if result.HasError() {
 				log.Printf("✗ Chunk #%d failed (took %s)\n",
 					result.Chunk.Index, result.Duration.Round(time.Millisecond))
 				if result.Error != nil {
@@ -249,136 +306,232 @@ func main() {
 		}
 	}
 
-	// 3. 타임스탬프 보정 및 자막 통합
-	if len(successChunks) > 0 {
-		log.Println("===== Merging Transcriptions =====")
-		allSubtitles := MergeChunkTranscriptions(successChunks, translator)
+	// 3. 실패 청크 재분할 후 재시도
+	unrecoveredChunks := chunksOf(failedChunks)
+	if len(failedChunks) > 0 && retryConfig.MaxSplitDepth > 0 {
+		log.Println("===== Retrying Failed Chunks =====")
+		recovered, unrecovered := chunking.RetryFailedChunks(unrecoveredChunks, retryConfig, func(chunk chunking.AudioChunk) error {
+			result := processChunk(ctx, job, chunk, outputDir, client)
+			if result.HasError() {
+				return fmt.Errorf("%v %v", result.Error, result.TranscriptionError)
+			}
+			successChunks = append(successChunks, result)
+			return nil
+		})
+		log.Printf("Recovered sub-chunks: %d, Unrecovered ranges: %d\n", len(recovered), len(unrecovered))
 
-		// 4. JSON 저장
-		outputJSON := filepath.Join(outputDir, "transcription.json")
-		if err := SaveTranscriptionJSON(allSubtitles, outputJSON); err != nil {
-			log.Printf("❌ Failed to save transcription JSON: %v\n", err)
+		unrecoveredChunks = unrecovered
+	}
+
+	// 4. 완료 정책 체크
+	successRatio, policyErr := chunking.EvaluateCompletion(retryConfig, chunksOf(successChunks), unrecoveredChunks)
+	if policyErr != nil {
+		log.Printf("❌ Completion policy not satisfied (success ratio: %.2f%%): %v\n", successRatio*100, policyErr)
+		return
+	}
+	if len(unrecoveredChunks) > 0 {
+		log.Printf("⚠️  Partial success (%.2f%%) - proceeding with available data, failed ranges are marked\n", successRatio*100)
+	}
+
+	// 5. 타임스탬프 보정 및 자막 통합
+	log.Println("===== Merging Transcriptions =====")
+	allSubtitles := MergeChunkTranscriptions(successChunks, unrecoveredChunks)
+	diarize.AssignSpeakers(allSubtitles, speakerTurns)
+
+	// 사전 치환 (frames 도 같이 고쳐야 Split 이 frames 로 문장을 다시 만들어도 유지됨)
+	if dict != nil {
+		allSubtitles = dict.ReplaceSegments(allSubtitles)
+	}
+
+	// 언어별 기준으로 긴 자막 분할 + 줄바꿈 (config.language, whisper 요청 언어와 같음)
+	allSubtitles = subtitle.NewLineBreaker(subtitle.ProfileForLanguage(client.Language)).Split(allSubtitles)
+
+	// LLM 교정 (Sentence, 타임스탬프는 그대로 두고 LLMCorrectSentence 만 채움)
+	var correctionReport *correction.Report
+	if appConfig.LLMCorrection != nil {
+		log.Println("===== LLM Correction =====")
+		correctionReport, err = runCorrection(ctx, appConfig, allSubtitles)
+		if err != nil {
+			log.Printf("⚠️  LLM correction partially failed: %v\n", err)
+		}
+		if correctionReport != nil {
+			log.Printf("   corrected: %d, unchanged: %d, rejected: %d, failed: %d\n",
+				correctionReport.Corrected, correctionReport.Unchanged, len(correctionReport.Rejected), len(correctionReport.FailedIDs))
+		}
+	}
+
+	// 6. JSON 저장
+	outputJSON := filepath.Join(outputDir, "transcription.json")
+	if err := subtitle.SaveJSON(allSubtitles, outputJSON); err != nil {
+		log.Printf("❌ Failed to save transcription JSON: %v\n", err)
+	} else {
+		log.Printf("✅ Transcription saved to: %s\n", outputJSON)
+		log.Printf("   Total subtitle segments: %d\n", len(allSubtitles))
+	}
+
+	// 자막 파일 저장
+	timing := subtitle.DefaultTimingConfig()
+	for _, format := range appConfig.SubtitleFormats {
+		writer, err := subtitle.NewWriter(format, subtitle.WriteOptions{SpeakerPrefix: len(speakerTurns) > 0, Timing: &timing})
+		if err != nil {
+			log.Printf("❌ %v\n", err)
+			continue
+		}
+
+		outputPath := filepath.Join(outputDir, "transcription"+writer.Extension())
+		if err := subtitle.SaveSubtitle(writer, allSubtitles, outputPath); err != nil {
+			log.Printf("❌ Failed to save %s subtitle: %v\n", format, err)
 		} else {
-			log.Printf("✅ Transcription saved to: %s\n", outputJSON)
-			log.Printf("   Total subtitle segments: %d\n", len(allSubtitles))
+			log.Printf("✅ Subtitle saved to: %s\n", outputPath)
 		}
+	}
+
+	// 7. 작업 리포트 저장
+	report := BuildJobReport(job, len(chunks), successChunks, unrecoveredChunks)
+	report.Correction = correctionReport
+	if err := SaveJobReport(report, filepath.Join(outputDir, "report.json")); err != nil {
+		log.Printf("❌ Failed to save job report: %v\n", err)
+	}
+
+	// 8. 환각 위험도 리포트 저장
+	hallucinationReport := BuildHallucinationReport(successChunks, whisper.DefaultScoreConfig())
+	if err := whisper.SaveHallucinationReport(hallucinationReport, filepath.Join(outputDir, "hallucination_report.json")); err != nil {
+		log.Printf("❌ Failed to save hallucination report: %v\n", err)
 	} else {
-		log.Println("\n⚠️  No successful chunks to merge")
+		log.Printf("🔎 Hallucination risk: flagged %d / %d segments\n", hallucinationReport.FlaggedSegments, hallucinationReport.TotalSegments)
 	}
 }
 
-func CreateAudioChunks(vadSegments []speech.Segment, config ChunkingConfig, totalDurationSec float64) []AudioChunk {
-	if len(vadSegments) == 0 {
-		return nil
+// loadDictionary : 고객사 사전 (전역 사전 위에 얹은 트라이)
+func loadDictionary(ctx context.Context, config *DictionaryConfig) (*dictionary.WordReplacementTrie, error) {
+	store, err := dictionary.NewFileStore(config.Dir)
+	if err != nil {
+		return nil, err
 	}
 
-	chunks := make([]AudioChunk, 0)
-	currentChunk := AudioChunk{
-		StartSec:    0, // 파일 처음부터 시작
-		VADSegments: []speech.Segment{},
-		Index:       0,
+	registry := dictionary.NewRegistry(store)
+	if _, err = registry.Reload(ctx); err != nil {
+		return nil, err
 	}
 
-	for i, seg := range vadSegments {
-		// 현재 청크에 이 세그먼트를 추가했을 때의 duration
-		potentialEndSec := seg.SpeechEndAt
-		potentialDuration := potentialEndSec - currentChunk.StartSec
+	name := config.Name
+	if name == "" {
+		name = dictionary.GlobalDictionary
+	}
+	return registry.Get(name), nil
+}
 
-		// MaxDuration을 초과하면 청크 분할
-		if potentialDuration > config.MaxDurationSec && len(currentChunk.VADSegments) > 0 {
-			// 현재 청크 마무리: 마지막 VAD 세그먼트가 끝나는 지점까지
-			lastSegEnd := currentChunk.VADSegments[len(currentChunk.VADSegments)-1].SpeechEndAt
-			currentChunk.EndSec = lastSegEnd
-			currentChunk.Duration = currentChunk.EndSec - currentChunk.StartSec
-			chunks = append(chunks, currentChunk)
+// runCorrection : provider 별 LLM 클라이언트로 교정 단계 실행
+func runCorrection(ctx context.Context, appConfig *Config, segments []subtitle.SubtitleSegment) (*correction.Report, error) {
+	llmConfig := appConfig.LLMCorrection
 
-			// 새 청크 시작: 이전 청크가 끝난 바로 다음부터 (겹침 없음)
-			currentChunk = AudioChunk{
-				StartSec:    lastSegEnd, // 이전 청크 끝 = 다음 청크 시작
-				VADSegments: []speech.Segment{seg},
-				Index:       len(chunks),
-			}
-		} else {
-			// 현재 청크에 세그먼트 추가
-			currentChunk.VADSegments = append(currentChunk.VADSegments, seg)
+	var client correction.Client
+	switch llmConfig.Provider {
+	case "gemini":
+		gemini, err := correction.NewGeminiClient(ctx, llmConfig.APIKey)
+		if err != nil {
+			return nil, err
 		}
-
-		// 마지막 세그먼트면 청크 저장
-		if i == len(vadSegments)-1 {
-			currentChunk.EndSec = totalDurationSec // 파일 끝까지
-			currentChunk.Duration = currentChunk.EndSec - currentChunk.StartSec
-			chunks = append(chunks, currentChunk)
+		if llmConfig.Model != "" {
+			gemini.Model = llmConfig.Model
 		}
+		client = gemini
+	case "openai":
+		apiKey := llmConfig.APIKey
+		if apiKey == "" {
+			apiKey = appConfig.OpenAIKey
+		}
+		openai := correction.NewOpenAIClient(apiKey)
+		if llmConfig.Model != "" {
+			openai.Model = llmConfig.Model
+		}
+		client = openai
+	case "stub":
+		client = &correction.StubClient{}
+	default:
+		return nil, fmt.Errorf("unsupported llm provider: %s", llmConfig.Provider)
 	}
 
-	// MinDuration 체크: 너무 짧은 청크는 이전 청크와 병합
-	if len(chunks) > 1 {
-		chunks = mergeShortChunks(chunks, config.MinDurationSec)
+	config := correction.DefaultConfig()
+	config.Instruction += llmConfig.Instruction
+	if llmConfig.MaxEditRatio > 0 {
+		config.MaxEditRatio = llmConfig.MaxEditRatio
 	}
 
-	return chunks
+	return correction.NewCorrector(client, config).Correct(ctx, segments)
 }
 
-// mergeShortChunks : MinDuration보다 짧은 청크를 이전 청크와 병합
-func mergeShortChunks(chunks []AudioChunk, minDuration float64) []AudioChunk {
-	merged := make([]AudioChunk, 0, len(chunks))
-
-	for i, chunk := range chunks {
-		if chunk.Duration < minDuration && i > 0 {
-			// 이전 청크와 병합
-			prev := &merged[len(merged)-1]
-			prev.EndSec = chunk.EndSec
-			prev.Duration = prev.EndSec - prev.StartSec
-			prev.VADSegments = append(prev.VADSegments, chunk.VADSegments...)
-		} else {
-			chunk.Index = len(merged)
-			merged = append(merged, chunk)
-		}
+// runDiarization : VAD 구간을 화자별로 나눔
+func runDiarization(modelPath, wavPath string, segments []vad.Segment) ([]diarize.SpeakerSegment, error) {
+	embedder, err := onnx.NewEmbedder(onnx.DefaultEmbedderConfig(modelPath))
+	if err != nil {
+		return nil, err
 	}
+	defer embedder.Destroy()
 
-	return merged
+	diarizer := diarize.NewClusterDiarizer(embedder, diarize.DefaultConfig())
+	return diarize.DiarizeFile(diarizer, wavPath, segments)
 }
 
-// SaveChunkInfo : 청크 정보를 텍스트 파일로 저장
-func SaveChunkInfo(chunks []AudioChunk, outputDir string) error {
-	infoPath := filepath.Join(outputDir, "chunks_info.txt")
-	f, err := os.Create(infoPath)
+// processChunk : 청크 오디오 파일 생성 -> webm 변환 -> Whisper API 호출
+func processChunk(ctx context.Context, job *Job, chunk chunking.AudioChunk, outputDir string, client *whisper.Client) ChunkResult {
+	chunkStartTime := time.Now()
+
+	result := ChunkResult{
+		Chunk:    chunk,
+		Duration: 0,
+	}
+
+	// 1. 청크 오디오 파일 생성
+	chunkPath, err := chunking.ExtractChunkAudio(ctx, job.WavAudioPath, chunk, outputDir)
+	result.ChunkPath = chunkPath
+
 	if err != nil {
-		return err
+		result.Error = err
+		result.Duration = time.Since(chunkStartTime)
+		return result
 	}
-	defer f.Close()
 
-	fmt.Fprintf(f, "Total Chunks: %d\n", len(chunks))
-	fmt.Fprintf(f, "=====================================\n\n")
+	log.Printf("Chunk #%s file created, calling Whisper API...\n", chunk.ChunkLabel())
 
-	for _, chunk := range chunks {
-		fmt.Fprintf(f, "Chunk #%d:\n", chunk.Index)
-		fmt.Fprintf(f, "  Time Range: %.3fs - %.3fs\n", chunk.StartSec, chunk.EndSec)
-		fmt.Fprintf(f, "  Duration: %.2fs = %s\n", chunk.Duration, formatDuration(chunk.Duration))
-		fmt.Fprintf(f, "  Overlap: %.2fs\n", chunk.OverlapSec)
-		fmt.Fprintf(f, "  VAD Segments: %d\n", len(chunk.VADSegments))
-
-		if len(chunk.VADSegments) > 0 {
-			fmt.Fprintf(f, "  Speech Segments:\n")
-			for i, seg := range chunk.VADSegments {
-				fmt.Fprintf(f, "    [%d] %.3fs - %.3fs (%.2fs)\n",
-					i,
-					seg.SpeechStartAt,
-					seg.SpeechEndAt,
-					seg.SpeechEndAt-seg.SpeechStartAt,
-				)
-			}
+	// 2. Whisper API 호출 (webm 변환 포함)
+	webmPath := strings.TrimSuffix(chunkPath, filepath.Ext(chunkPath)) + ".webm"
+
+	// WAV -> WebM 변환
+	extractErr := ffmpeg.ExtractAudio(ctx, chunkPath, webmPath)
+	if extractErr != nil {
+		result.TranscriptionError = fmt.Errorf("webm conversion failed: %w", extractErr)
+		result.Duration = time.Since(chunkStartTime)
+		return result
+	}
+
+	// Whisper API 호출
+	whisperResp, whisperErr := client.Transcribe(ctx, webmPath, job.RId+".webm")
+	result.WhisperResponse = whisperResp
+	result.TranscriptionError = whisperErr
+
+	// 3. 환각 구간 재요청 (청크 wav 에서 구간을 잘라서 다시 호출)
+	if whisperErr == nil && job.HallucinationRetry.MaxRetry > 0 {
+		retryConfig := job.HallucinationRetry
+		retryConfig.TempDir = outputDir
+
+		retried, fixes, err := client.RetryHallucinationSegments(ctx, whisperResp, chunkPath, job.RId+filepath.Ext(chunkPath), retryConfig)
+		if err != nil {
+			log.Printf("Chunk #%s hallucination retry stopped: %v\n", chunk.ChunkLabel(), err)
 		}
-		fmt.Fprintf(f, "\n")
+		result.WhisperResponse = retried
+		result.HallucinationFixes = fixes
 	}
 
-	log.Printf("Chunk info saved to: %s\n", infoPath)
-	return nil
+	result.Duration = time.Since(chunkStartTime)
+
+	return result
 }
 
 // MergeChunkTranscriptions : 청크별 Whisper 응답을 타임스탬프 보정하여 통합
-func MergeChunkTranscriptions(chunkResults []ChunkResult, translator *TranslatorWhisper) []SubtitleSegment {
-	allSubtitles := make([]SubtitleSegment, 0)
+// failedChunks 구간은 Failed placeholder 로 채워서 자막 구멍이 드러나도록 함
+func MergeChunkTranscriptions(chunkResults []ChunkResult, failedChunks []chunking.AudioChunk) []subtitle.SubtitleSegment {
+	allSubtitles := make([]subtitle.SubtitleSegment, 0)
 
 	for _, result := range chunkResults {
 		if result.WhisperResponse == nil {
@@ -388,51 +541,102 @@ func MergeChunkTranscriptions(chunkResults []ChunkResult, translator *Translator
 		// 청크 시작 시간 (타임스탬프 오프셋)
 		timeOffset := result.Chunk.StartSec
 
-		log.Printf("Processing chunk #%d (offset: %.2fs, segments: %d)\n",
-			result.Chunk.Index, timeOffset, len(result.WhisperResponse.Segments))
+		log.Printf("Processing chunk #%s (offset: %.2fs, segments: %d)\n",
+			result.Chunk.ChunkLabel(), timeOffset, len(result.WhisperResponse.Segments))
 
-		// Whisper 응답을 자막 형식으로 변환
-		subtitles := translator.ConvertWhisperResponse(result.WhisperResponse)
-
-		// 타임스탬프 보정: 청크 시작 시간을 더함
-		for i := range subtitles {
-			subtitles[i].StartTime += timeOffset
-			subtitles[i].EndTime += timeOffset
-
-			// SentenceFrames의 타임스탬프도 보정
-			for j := range subtitles[i].SentenceFrames {
-				subtitles[i].SentenceFrames[j].WordStartTime += timeOffset
-				subtitles[i].SentenceFrames[j].WordEndTime += timeOffset
-			}
-		}
+		// Whisper 응답을 자막 형식으로 변환 후 타임스탬프 보정: 청크 시작 시간을 더함
+		subtitles := whisper.ConvertWhisperResponse(result.WhisperResponse)
+		subtitle.ShiftTime(subtitles, timeOffset)
 
 		allSubtitles = append(allSubtitles, subtitles...)
 	}
 
-	// 시간순으로 정렬
-	SortSubtitleSegment(allSubtitles)
+	// 실패 구간 placeholder
+	allSubtitles = append(allSubtitles, chunking.BuildFailedPlaceholders(failedChunks)...)
 
-	// 인덱스 재정렬
-	for i := range allSubtitles {
-		allSubtitles[i].Idx = i
-	}
+	// 시간순으로 정렬 후 인덱스 재정렬
+	subtitle.SortSubtitleSegment(allSubtitles)
+	subtitle.Reindex(allSubtitles)
 
 	log.Printf("Total merged subtitles: %d\n", len(allSubtitles))
 
 	return allSubtitles
 }
 
-// SaveTranscriptionJSON : 자막 데이터를 JSON 파일로 저장
-func SaveTranscriptionJSON(subtitles []SubtitleSegment, outputPath string) error {
-	data, err := json.MarshalIndent(subtitles, "", "  ")
+// BuildJobReport : 청크별 환각 처리 내역을 원본 타임라인으로 보정해서 모음
+func BuildJobReport(job *Job, totalChunks int, successChunks []ChunkResult, failedChunks []chunking.AudioChunk) JobReport {
+	report := JobReport{
+		RId:                job.RId,
+		TotalChunks:        totalChunks,
+		SuccessChunks:      len(successChunks),
+		FailedRanges:       failedChunks,
+		HallucinationFixes: make([]whisper.HallucinationFix, 0),
+	}
+
+	for _, result := range successChunks {
+		for _, fix := range result.HallucinationFixes {
+			fix.StartTime += result.Chunk.StartSec
+			fix.EndTime += result.Chunk.StartSec
+			report.HallucinationFixes = append(report.HallucinationFixes, fix)
+		}
+	}
+
+	sort.Slice(report.HallucinationFixes, func(i, j int) bool {
+		return report.HallucinationFixes[i].StartTime < report.HallucinationFixes[j].StartTime
+	})
+
+	return report
+}
+
+// BuildHallucinationReport : 청크별로 점수 계산 (VAD 구간은 청크 기준으로 당겨서 비교) 후 원본 타임라인으로 합침
+func BuildHallucinationReport(successChunks []ChunkResult, config whisper.ScoreConfig) whisper.HallucinationReport {
+	report := whisper.HallucinationReport{Segments: make([]whisper.SegmentRisk, 0)}
+
+	for _, result := range successChunks {
+		if result.WhisperResponse == nil {
+			continue
+		}
+
+		speech := make([]vad.Segment, len(result.Chunk.VADSegments))
+		for i, seg := range result.Chunk.VADSegments {
+			speech[i] = vad.Segment{
+				SpeechStartAt: seg.SpeechStartAt - result.Chunk.StartSec,
+				SpeechEndAt:   seg.SpeechEndAt - result.Chunk.StartSec,
+			}
+		}
+
+		chunkReport := whisper.ScoreHallucinations(result.WhisperResponse.Segments, speech, config)
+		report.Append(chunkReport, result.Chunk.StartSec)
+	}
+
+	return report
+}
+
+// SaveJobReport : 작업 리포트 JSON 저장
+func SaveJobReport(report JobReport, outputPath string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
 	if err != nil {
 		return fmt.Errorf("JSON marshal failed: %w", err)
 	}
 
-	err = os.WriteFile(outputPath, data, 0644)
-	if err != nil {
+	if err = os.WriteFile(outputPath, data, 0644); err != nil {
 		return fmt.Errorf("file write failed: %w", err)
 	}
 
+	log.Printf("📝 Job report saved to: %s (hallucination fixes: %d)\n", outputPath, len(report.HallucinationFixes))
 	return nil
 }
+
+// HasError : 파일 생성 또는 whisper 호출 중 하나라도 실패했는지
+func (r ChunkResult) HasError() bool {
+	return r.Error != nil || r.TranscriptionError != nil
+}
+
+// chunksOf : 결과 목록에서 청크 구간만 추출
+func chunksOf(results []ChunkResult) []chunking.AudioChunk {
+	chunks := make([]chunking.AudioChunk, 0, len(results))
+	for _, r := range results {
+		chunks = append(chunks, r.Chunk)
+	}
+	return chunks
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"os"
 	"time"
 
@@ -28,8 +29,8 @@ func LoadConfig() error {
 		return fmt.Errorf("Error parsing config.json file: %s", err)
 	}
 
-	if server.WowzaConfig.WowzaHost == "" {
-		return fmt.Errorf("Wowza_host is required")
+	if server.WowzaConfig.WowzaHost == "" && len(server.WowzaConfig.Destinations) == 0 && len(server.WowzaConfig.Routes) == 0 {
+		return fmt.Errorf("wowza_host or destinations is required")
 	}
 
 	return nil
@@ -47,6 +48,17 @@ func main() {
 	})
 	log.SetLevel(log.DebugLevel)
 
+	authorizer, err := server.NewAuthorizer(server.WowzaConfig.Auth)
+	if err != nil {
+		log.Fatalf("Error loading auth config: %s", err)
+	}
+	publishers := &server.PublisherLimit{Max: server.WowzaConfig.Auth.MaxPublishers}
+
+	sessions := server.NewSessionRegistry(server.WowzaConfig.Sessions)
+	mux := http.NewServeMux()
+	sessions.Register(mux)
+	go server.ServeAPI(server.WowzaConfig.Sessions.HTTPAddr, mux)
+
 	log.Info("========================================")
 	log.Info("RTMP Server for wowza forward")
 	log.Info("========================================")
@@ -81,14 +93,22 @@ func main() {
 			})
 			l.Logger.SetLevel(log.DebugLevel)
 
-			h := &server.RelayHandler{}
+			h := &server.RelayHandler{
+				PublishGuard: server.PublishGuard{
+					Authorizer: authorizer,
+					Publishers: publishers,
+					RemoteAddr: conn.RemoteAddr().String(),
+				},
+				Session: sessions.Open(conn),
+			}
+			h.Session.Destinations = h.Statuses
 
 			log.WithFields(log.Fields{
 				"connection_id":    connID,
 				"bandwidth_window": 6 * 1024 * 1024 / 8,
 			}).Debug("연결 설정 완료")
 
-			return conn, &rtmp.ConnConfig{
+			return h.Session.Conn(), &rtmp.ConnConfig{
 				Handler: h,
 
 				ControlState: rtmp.StreamControlStateConfig{
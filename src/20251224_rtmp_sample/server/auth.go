@@ -0,0 +1,321 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnauthorized      = errors.New("unauthorized")
+	ErrTooManyPublishers = errors.New("too many publishers")
+	ErrAlreadyPublishing = errors.New("connection is already publishing")
+)
+
+// PublishRequest : 인증에 필요한 정보 (OnConnect 단계에서는 StreamKey 가 비어있음)
+type PublishRequest struct {
+	App        string     `json:"app"`
+	StreamKey  string     `json:"stream_key"` // query 제거한 이름
+	Query      url.Values `json:"query,omitempty"`
+	RemoteAddr string     `json:"remote_addr"`
+}
+
+// ParsePublishingName : OBS 스트림 키에 붙은 query 분리 ("test?exp=1&sig=ab" -> "test", {exp, sig})
+func ParsePublishingName(name string) (string, url.Values) {
+	key, rawQuery, found := strings.Cut(name, "?")
+	if !found {
+		return name, url.Values{}
+	}
+	query, _ := url.ParseQuery(rawQuery)
+	return key, query
+}
+
+// PublishAuthorizer : publish 허용 여부 (nil 이면 전부 허용)
+type PublishAuthorizer interface {
+	AuthorizeConnect(ctx context.Context, req PublishRequest) error // OnConnect : app 체크
+	AuthorizePublish(ctx context.Context, req PublishRequest) error // OnPublish : app + stream key 체크
+}
+
+// AuthConfig : 인증 설정 (Type 이 비어있으면 인증 안함)
+type AuthConfig struct {
+	Type          string   `json:"type"`           // static, webhook, token
+	File          string   `json:"file"`           // static : app -> stream key 목록 JSON 파일
+	URL           string   `json:"url"`            // webhook
+	Secret        string   `json:"secret"`         // token : HMAC key
+	Apps          []string `json:"apps"`           // webhook, token : 허용 app (비어있으면 전부)
+	MaxPublishers int      `json:"max_publishers"` // 동시 publish 최대 수 (0 이면 제한 없음)
+}
+
+func NewAuthorizer(config AuthConfig) (PublishAuthorizer, error) {
+	switch config.Type {
+	case "":
+		return nil, nil
+	case "static":
+		return LoadStaticAuthorizer(config.File)
+	case "webhook":
+		return &WebhookAuthorizer{URL: config.URL, Apps: config.Apps, Timeout: 3 * time.Second}, nil
+	case "token":
+		if config.Secret == "" {
+			return nil, fmt.Errorf("token auth requires secret")
+		}
+		return &TokenAuthorizer{Secret: []byte(config.Secret), Apps: config.Apps}, nil
+	default:
+		return nil, fmt.Errorf("unsupported auth type: %s", config.Type)
+	}
+}
+
+func allowApp(apps []string, app string) error {
+	if len(apps) > 0 && !slices.Contains(apps, app) {
+		return fmt.Errorf("%w: app %q not allowed", ErrUnauthorized, app)
+	}
+	return nil
+}
+
+// StaticAuthorizer : {"apps": {"live": ["key1", "key2"], "test": ["*"]}} ("*" 는 아무 키나 허용)
+type StaticAuthorizer struct {
+	Apps map[string][]string `json:"apps"`
+}
+
+func LoadStaticAuthorizer(path string) (*StaticAuthorizer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error opening auth file: %w", err)
+	}
+
+	auth := &StaticAuthorizer{}
+	if err = json.Unmarshal(data, auth); err != nil {
+		return nil, fmt.Errorf("Error parsing auth file: %w", err)
+	}
+	return auth, nil
+}
+
+func (a *StaticAuthorizer) AuthorizeConnect(ctx context.Context, req PublishRequest) error {
+	if _, ok := a.Apps[req.App]; !ok {
+		return fmt.Errorf("%w: app %q not allowed", ErrUnauthorized, req.App)
+	}
+	return nil
+}
+
+func (a *StaticAuthorizer) AuthorizePublish(ctx context.Context, req PublishRequest) error {
+	keys, ok := a.Apps[req.App]
+	if !ok {
+		return fmt.Errorf("%w: app %q not allowed", ErrUnauthorized, req.App)
+	}
+	if !slices.Contains(keys, "*") && !slices.Contains(keys, req.StreamKey) {
+		return fmt.Errorf("%w: stream key %q not allowed", ErrUnauthorized, req.StreamKey)
+	}
+	return nil
+}
+
+// WebhookAuthorizer : {"action": "publish", "app", "stream_key", "query", "remote_addr"} 를 POST, 2xx 면 허용
+// connect 단계는 Apps 만 보고 호출 안함 (stream key 가 없어서)
+type WebhookAuthorizer struct {
+	URL     string
+	Apps    []string
+	Timeout time.Duration
+
+	HTTPClient *http.Client
+}
+
+func (a *WebhookAuthorizer) AuthorizeConnect(ctx context.Context, req PublishRequest) error {
+	return allowApp(a.Apps, req.App)
+}
+
+func (a *WebhookAuthorizer) AuthorizePublish(ctx context.Context, req PublishRequest) error {
+	if err := allowApp(a.Apps, req.App); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(struct {
+		Action string `json:"action"`
+		PublishRequest
+	}{Action: "publish", PublishRequest: req})
+	if err != nil {
+		return err
+	}
+
+	if a.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.Timeout)
+		defer cancel()
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("http request fail: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpClient := a.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("auth webhook fail: %w", err) // webhook 이 죽으면 거부
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: webhook status %s", ErrUnauthorized, resp.Status)
+	}
+	return nil
+}
+
+// TokenAuthorizer : 스트림 키 = "<name>?exp=<unix>&sig=<hex>", sig = HMAC-SHA256(secret, "<app>/<name>:<exp>")
+type TokenAuthorizer struct {
+	Secret []byte
+	Apps   []string
+
+	now func() time.Time // 테스트용
+}
+
+// SignStreamKey : 발급용 (OBS 스트림 키에 그대로 넣으면 됨)
+func SignStreamKey(secret []byte, app, name string, expires time.Time) string {
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	return fmt.Sprintf("%s?exp=%s&sig=%s", name, exp, tokenSignature(secret, app, name, exp))
+}
+
+func tokenSignature(secret []byte, app, name, exp string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(app + "/" + name + ":" + exp))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (a *TokenAuthorizer) AuthorizeConnect(ctx context.Context, req PublishRequest) error {
+	return allowApp(a.Apps, req.App)
+}
+
+func (a *TokenAuthorizer) AuthorizePublish(ctx context.Context, req PublishRequest) error {
+	if err := allowApp(a.Apps, req.App); err != nil {
+		return err
+	}
+
+	exp, sig := req.Query.Get("exp"), req.Query.Get("sig")
+	expires, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || sig == "" {
+		return fmt.Errorf("%w: missing token", ErrUnauthorized)
+	}
+
+	now := time.Now
+	if a.now != nil {
+		now = a.now
+	}
+	if now().Unix() > expires {
+		return fmt.Errorf("%w: token expired", ErrUnauthorized)
+	}
+
+	expected := tokenSignature(a.Secret, req.App, req.StreamKey, exp)
+	if !hmac.Equal([]byte(expected), []byte(sig)) {
+		return fmt.Errorf("%w: invalid signature", ErrUnauthorized)
+	}
+	return nil
+}
+
+// PublisherLimit : 서버 전체 동시 publish 수 제한 (Max 0 이면 제한 없음)
+type PublisherLimit struct {
+	Max int
+
+	mu    sync.Mutex
+	count int
+}
+
+func (l *PublisherLimit) Acquire() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Max > 0 && l.count >= l.Max {
+		return fmt.Errorf("%w: max %d", ErrTooManyPublishers, l.Max)
+	}
+	l.count++
+	return nil
+}
+
+func (l *PublisherLimit) Release() {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.count--
+}
+
+func (l *PublisherLimit) Count() int {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.count
+}
+
+// PublishGuard : 핸들러 공통 인증 + 동시 publish 제한 (RelayHandler, Handler 에 embed)
+type PublishGuard struct {
+	Authorizer PublishAuthorizer
+	Publishers *PublisherLimit
+	RemoteAddr string
+
+	app      string
+	acquired bool
+}
+
+func (g *PublishGuard) authorizeConnect(app string) error {
+	g.app = app
+	if g.Authorizer == nil {
+		return nil
+	}
+	return g.Authorizer.AuthorizeConnect(context.Background(), PublishRequest{App: app, RemoteAddr: g.RemoteAddr})
+}
+
+// authorizePublish : 인증 + 슬롯 확보, query 제거한 stream key 반환
+// 연결 하나에 publish 는 한번만 (두번째 publish 를 받으면 슬롯 / 출력이 하나씩 더 잡힘)
+func (g *PublishGuard) authorizePublish(publishingName string) (string, error) {
+	if g.acquired {
+		return "", ErrAlreadyPublishing
+	}
+
+	streamKey, query := ParsePublishingName(publishingName)
+	if streamKey == "" {
+		return "", errors.New("PublishingName is empty")
+	}
+
+	if g.Authorizer != nil {
+		req := PublishRequest{App: g.app, StreamKey: streamKey, Query: query, RemoteAddr: g.RemoteAddr}
+		if err := g.Authorizer.AuthorizePublish(context.Background(), req); err != nil {
+			return "", err
+		}
+	}
+
+	if err := g.Publishers.Acquire(); err != nil {
+		return "", err
+	}
+	g.acquired = true
+	return streamKey, nil
+}
+
+func (g *PublishGuard) release() {
+	if g.acquired {
+		g.Publishers.Release()
+		g.acquired = false
+	}
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+
+	"example/stt/ffmpeg"
+	"example/stt/live"
+)
+
+const pcmReadSize = 1600 // 100ms (16kHz)
+
+// CaptionConfig : 라이브 자막 설정 (VAD 모델 / transcriber 는 CaptionService 에서 주입)
+type CaptionConfig struct {
+	live.Config
+	VTTDir string `json:"vtt_dir"` // <VTTDir>/<stream key>.vtt (비어있으면 os.TempDir()/captions)
+}
+
+// CaptionService : publish 마다 CaptionWriter 를 만들고 자막을 Hub(SSE / WebSocket) 와 WebVTT 로 내보냄
+type CaptionService struct {
+	Config      CaptionConfig
+	Hub         *live.Hub
+	NewDetector live.DetectorFactory
+	Transcriber live.Transcriber
+}
+
+// VTTPath : 스트림별 WebVTT 파일 경로
+func (s *CaptionService) VTTPath(streamKey string) string {
+	dir := s.Config.VTTDir
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "captions")
+	}
+	return filepath.Join(dir, filepath.Clean(filepath.Join("/", streamKey+".vtt")))
+}
+
+func (s *CaptionService) Start(streamKey string) (*CaptionWriter, error) {
+	vtt, err := live.CreateVTTFile(s.VTTPath(streamKey))
+	if err != nil {
+		return nil, err
+	}
+
+	onCaption := func(e live.Event) {
+		log.Infof("📝 [%s] %.3f - %.3f (%dms) %s", e.Stream, e.Segment.StartTime, e.Segment.EndTime, e.LatencyMs, e.Segment.Sentence)
+		s.Hub.Publish(e)
+		if err := vtt.Append(e.Segment); err != nil {
+			log.Warnf("vtt append 실패: %v", err)
+		}
+	}
+
+	captioner, err := live.NewCaptioner(s.Config.Config, streamKey, s.NewDetector, s.Transcriber, onCaption)
+	if err != nil {
+		_ = vtt.Close()
+		return nil, err
+	}
+
+	return &CaptionWriter{service: s, captioner: captioner, vtt: vtt}, nil
+}
+
+// CaptionWriter : RTMP AAC -> (ffmpeg) 16kHz 모노 PCM -> live.Captioner
+// sink 구현이라 Handler 에서 HLS 와 같은 방식으로 씀
+type CaptionWriter struct {
+	service   *CaptionService
+	captioner *live.Captioner
+	vtt       *live.VTTFile
+
+	aac     *aacConfig
+	decoder *ffmpeg.PCMDecoder
+	done    chan struct{}
+}
+
+func (w *CaptionWriter) Write(p *Packet) error {
+	if p.Type != PacketAudio || len(p.Payload) < 2 || p.Payload[0]>>4 != soundFormatAAC {
+		return nil
+	}
+
+	if p.IsSequenceHeader() {
+		config, err := parseAACConfig(p.Payload[2:])
+		if err != nil {
+			return fmt.Errorf("invalid aac sequence header: %w", err)
+		}
+		w.aac = config
+		return nil
+	}
+	if w.aac == nil {
+		return nil
+	}
+
+	if w.decoder == nil {
+		if err := w.start(p.Timestamp); err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.decoder.Write(w.aac.adts(p.Payload[2:])); err != nil {
+		return fmt.Errorf("write to decoder: %w", err)
+	}
+	return nil
+}
+
+// start : 첫 AAC 프레임에서 디코더 시작, 자막 타임스탬프는 이 프레임 기준 (스트림 시작 기준 시각)
+func (w *CaptionWriter) start(timestamp uint32) error {
+	decoder, err := ffmpeg.StartPCMDecoder(context.Background(), "aac", w.captioner.Config.SampleRate)
+	if err != nil {
+		return err
+	}
+
+	w.captioner.Offset = float64(timestamp) / 1000
+	w.decoder = decoder
+	w.done = make(chan struct{})
+	go w.read()
+	return nil
+}
+
+func (w *CaptionWriter) read() {
+	defer close(w.done)
+
+	pcm := make([]float32, pcmReadSize)
+	for {
+		n, err := w.decoder.Read(pcm)
+		if n > 0 {
+			if writeErr := w.captioner.Write(pcm[:n]); writeErr != nil {
+				log.Warnf("caption vad 실패: %v", writeErr)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Warnf("decoder read 실패: %v", err)
+			break
+		}
+	}
+
+	if err := w.decoder.Wait(); err != nil {
+		log.Warnf("decoder 종료 실패: %v", err)
+	}
+}
+
+// Close : 남은 오디오까지 자막으로 만들고 구독자 연결 종료
+func (w *CaptionWriter) Close() error {
+	if w.decoder != nil {
+		_ = w.decoder.CloseInput()
+		<-w.done
+	}
+
+	stats := w.captioner.Close()
+	log.Infof("자막 종료: %s %+v", w.captioner.Stream, stats)
+
+	w.service.Hub.End(w.captioner.Stream)
+	return w.vtt.Close()
+}
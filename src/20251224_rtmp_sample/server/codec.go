@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// FLV(AVCC / raw AAC) -> MPEG-TS(Annex B / ADTS) 변환
+
+var (
+	errShortPayload = errors.New("payload too short")
+	startCode       = []byte{0x00, 0x00, 0x00, 0x01}
+	accessUnitDelim = []byte{0x00, 0x00, 0x00, 0x01, 0x09, 0xF0}
+)
+
+const (
+	naluTypeSPS = 7
+	naluTypeAUD = 9
+)
+
+// avcConfig : AVCDecoderConfigurationRecord 에서 필요한 값
+type avcConfig struct {
+	lengthSize int
+	sps        [][]byte
+	pps        [][]byte
+}
+
+// parseAVCConfig : FLV video sequence header 의 body (FLV 헤더 5 bytes 제외)
+func parseAVCConfig(b []byte) (*avcConfig, error) {
+	if len(b) < 7 {
+		return nil, errShortPayload
+	}
+
+	config := &avcConfig{lengthSize: int(b[4]&0x03) + 1}
+	pos := 5
+
+	// [count][len][set][len][set]...
+	readSets := func(mask byte) ([][]byte, error) {
+		if pos >= len(b) {
+			return nil, errShortPayload
+		}
+		count := int(b[pos] & mask)
+		pos++
+
+		sets := make([][]byte, 0, count)
+		for i := 0; i < count; i++ {
+			if pos+2 > len(b) {
+				return nil, errShortPayload
+			}
+			n := int(binary.BigEndian.Uint16(b[pos:]))
+			pos += 2
+			if pos+n > len(b) {
+				return nil, errShortPayload
+			}
+			sets = append(sets, b[pos:pos+n])
+			pos += n
+		}
+		return sets, nil
+	}
+
+	var err error
+	if config.sps, err = readSets(0x1F); err != nil {
+		return nil, fmt.Errorf("sps: %w", err)
+	}
+	if config.pps, err = readSets(0xFF); err != nil {
+		return nil, fmt.Errorf("pps: %w", err)
+	}
+	return config, nil
+}
+
+// annexB : length-prefixed NALU -> start code, AUD 추가, keyframe 인데 SPS 가 없으면 SPS/PPS 추가
+func (c *avcConfig) annexB(b []byte, keyframe bool) ([]byte, error) {
+	out := make([]byte, 0, len(b)+64)
+	out = append(out, accessUnitDelim...)
+
+	nalus := make([][]byte, 0, 4)
+	hasSPS := false
+	for pos := 0; pos < len(b); {
+		if pos+c.lengthSize > len(b) {
+			return nil, errShortPayload
+		}
+		n := 0
+		for i := 0; i < c.lengthSize; i++ {
+			n = n<<8 | int(b[pos+i])
+		}
+		pos += c.lengthSize
+		if n == 0 || pos+n > len(b) {
+			return nil, errShortPayload
+		}
+
+		nalu := b[pos : pos+n]
+		pos += n
+		switch nalu[0] & 0x1F {
+		case naluTypeAUD:
+			continue
+		case naluTypeSPS:
+			hasSPS = true
+		}
+		nalus = append(nalus, nalu)
+	}
+
+	if keyframe && !hasSPS {
+		for _, set := range append(append([][]byte{}, c.sps...), c.pps...) {
+			out = append(out, startCode...)
+			out = append(out, set...)
+		}
+	}
+	for _, nalu := range nalus {
+		out = append(out, startCode...)
+		out = append(out, nalu...)
+	}
+	return out, nil
+}
+
+// aacConfig : AudioSpecificConfig
+type aacConfig struct {
+	objectType   byte
+	sampleRateID byte
+	channels     byte
+}
+
+var aacSampleRates = []int{96000, 88200, 64000, 48000, 44100, 32000, 24000, 22050, 16000, 12000, 11025, 8000, 7350}
+
+func parseAACConfig(b []byte) (*aacConfig, error) {
+	if len(b) < 2 {
+		return nil, errShortPayload
+	}
+	config := &aacConfig{
+		objectType:   b[0] >> 3,
+		sampleRateID: (b[0]&0x07)<<1 | b[1]>>7,
+		channels:     (b[1] >> 3) & 0x0F,
+	}
+	// ADTS profile 은 2bit (objectType - 1), Main / LC / SSR / LTP 만 담을 수 있음
+	if config.objectType < 1 || config.objectType > 4 {
+		return nil, fmt.Errorf("unsupported audio object type: %d", config.objectType)
+	}
+	if int(config.sampleRateID) >= len(aacSampleRates) {
+		return nil, fmt.Errorf("unsupported sample rate index: %d", config.sampleRateID)
+	}
+	return config, nil
+}
+
+func (c *aacConfig) SampleRate() int {
+	return aacSampleRates[c.sampleRateID]
+}
+
+// adts : raw AAC 프레임 앞에 ADTS 헤더 (7 bytes, CRC 없음)
+func (c *aacConfig) adts(frame []byte) []byte {
+	length := len(frame) + 7
+	profile := c.objectType - 1
+
+	out := make([]byte, 0, length)
+	out = append(out,
+		0xFF,
+		0xF1,
+		profile<<6|c.sampleRateID<<2|c.channels>>2,
+		(c.channels&0x03)<<6|byte(length>>11),
+		byte(length>>3),
+		byte(length&0x07)<<5|0x1F,
+		0xFC,
+	)
+	return append(out, frame...)
+}
@@ -0,0 +1,351 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/yutopp/go-rtmp"
+	rtmpmsg "github.com/yutopp/go-rtmp/message"
+)
+
+const (
+	DestinationTypeRTMP = "rtmp"
+	DestinationTypeFLV  = "flv" // 로컬 녹화
+	DestinationTypeHLS  = "hls" // 로컬 HLS (Dir/<stream key>/index.m3u8)
+
+	defaultBufferSize           = 512 // 패킷 수 (30fps 영상 + 오디오 기준 5~6초 정도)
+	defaultReconnectInterval    = 1   // 단위: second
+	defaultMaxReconnectInterval = 30  // 단위: second
+)
+
+// DestinationConfig : 재전송 목적지 하나
+type DestinationConfig struct {
+	Name              string `json:"name"`
+	Type              string `json:"type"`               // rtmp(기본), flv
+	Host              string `json:"host"`               // rtmp : host:port
+	App               string `json:"app"`                // rtmp : 비어있으면 publish 한 app 그대로
+	StreamKey         string `json:"stream_key"`         // rtmp : 비어있으면 publish 한 stream key 그대로
+	Dir               string `json:"dir"`                // flv, hls : 저장 경로 (비어있으면 os.TempDir())
+	BufferSize        int    `json:"buffer_size"`        // 목적지별 패킷 버퍼, 꽉 차면 이 목적지만 패킷을 버림
+	ReconnectInterval int    `json:"reconnect_interval"` // 재연결 첫 대기 시간, 실패할때마다 2배 (단위: second)
+
+	MaxReconnectInterval int `json:"max_reconnect_interval"` // 재연결 대기 최대값 (단위: second)
+}
+
+// RouteConfig : app / stream key 별 목적지 (비어있는 조건은 전체 매칭)
+type RouteConfig struct {
+	App          string              `json:"app"`
+	StreamKey    string              `json:"stream_key"`
+	Destinations []DestinationConfig `json:"destinations"`
+}
+
+func (r *RouteConfig) Match(app, streamKey string) bool {
+	return (r.App == "" || r.App == app) && (r.StreamKey == "" || r.StreamKey == streamKey)
+}
+
+type DestinationState string
+
+const (
+	StateConnecting   DestinationState = "connecting"
+	StateConnected    DestinationState = "connected"
+	StateReconnecting DestinationState = "reconnecting"
+	StateClosed       DestinationState = "closed"
+)
+
+// DestinationStatus : 목적지별 상태 (통계 API 용)
+type DestinationStatus struct {
+	Name        string           `json:"name"`
+	Target      string           `json:"target"`
+	State       DestinationState `json:"state"`
+	Sent        uint64           `json:"sent"`
+	Dropped     uint64           `json:"dropped"` // 버퍼가 꽉 차서 버린 패킷
+	Reconnects  int              `json:"reconnects"`
+	LastError   string           `json:"last_error,omitempty"`
+	ConnectedAt time.Time        `json:"connected_at,omitempty"`
+}
+
+// sink : 실제로 패킷을 쓰는 쪽 (RTMP upstream, FLV 파일, HLS)
+type sink interface {
+	Write(p *Packet) error
+	Close() error
+}
+
+// Destination : 목적지 하나 + 전용 버퍼 + 전송 goroutine
+// Send 는 절대 block 되지 않아서 느리거나 죽은 목적지가 OnAudio/OnVideo 와 다른 목적지를 막지 않음
+// 연결이 끊기면 backoff 로 재연결하고, 그동안 마지막 GOP 를 모아뒀다가 재연결 후 메타데이터, sequence header 와 같이 다시 보냄
+// (encoder 재시작 없이 upstream 에서 바로 디코딩 가능)
+type Destination struct {
+	Config    DestinationConfig
+	App       string
+	StreamKey string
+
+	cache GOPCache // run goroutine 에서만 접근
+
+	queue  chan *Packet
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu     sync.Mutex
+	status DestinationStatus
+}
+
+// NewDestination : app, streamKey 는 publish 한 값 (설정에 있으면 설정값 우선)
+func NewDestination(config DestinationConfig, app, streamKey string) *Destination {
+	if config.Type == "" {
+		config.Type = DestinationTypeRTMP
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaultBufferSize
+	}
+	if config.ReconnectInterval <= 0 {
+		config.ReconnectInterval = defaultReconnectInterval
+	}
+	if config.MaxReconnectInterval < config.ReconnectInterval {
+		config.MaxReconnectInterval = max(defaultMaxReconnectInterval, config.ReconnectInterval)
+	}
+	if config.App != "" {
+		app = config.App
+	}
+	if config.StreamKey != "" {
+		streamKey = config.StreamKey
+	}
+
+	d := &Destination{
+		Config:    config,
+		App:       app,
+		StreamKey: streamKey,
+		queue:     make(chan *Packet, config.BufferSize),
+		done:      make(chan struct{}),
+	}
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	d.status = DestinationStatus{Name: config.Name, Target: d.target(), State: StateConnecting}
+	return d
+}
+
+func (d *Destination) target() string {
+	if d.Config.Type == DestinationTypeFLV || d.Config.Type == DestinationTypeHLS {
+		return fmt.Sprintf("%s://%s/%s", d.Config.Type, d.Config.Dir, d.StreamKey)
+	}
+	return fmt.Sprintf("rtmp://%s/%s/%s", d.Config.Host, d.App, d.StreamKey)
+}
+
+func (d *Destination) Start() {
+	go d.run()
+}
+
+// Send : 버퍼에 넣기만 함, 꽉 차면 버리고 false
+func (d *Destination) Send(p *Packet) bool {
+	select {
+	case d.queue <- p:
+		return true
+	default:
+		d.mu.Lock()
+		d.status.Dropped++
+		d.mu.Unlock()
+		return false
+	}
+}
+
+// Close : 전송 goroutine 종료 대기
+func (d *Destination) Close() {
+	d.cancel()
+	<-d.done
+}
+
+func (d *Destination) Status() DestinationStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.status
+}
+
+func (d *Destination) setState(state DestinationState, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.status.State = state
+	if err != nil {
+		d.status.LastError = err.Error()
+	}
+	switch state {
+	case StateConnected:
+		d.status.ConnectedAt = time.Now()
+	case StateReconnecting:
+		d.status.Reconnects++
+	}
+}
+
+// run : 연결 -> (GOP 재전송) -> 버퍼에서 꺼내서 전송, 실패하면 backoff 후 다시 연결
+func (d *Destination) run() {
+	defer close(d.done)
+	defer d.setState(StateClosed, nil)
+
+	logger := log.WithFields(log.Fields{"destination": d.Config.Name, "target": d.status.Target})
+
+	failures := 0
+	for {
+		s, err := d.open()
+		if err == nil {
+			d.setState(StateConnected, nil)
+			logger.Infof("✅ 목적지 연결 (replay %d packets)", len(d.cache.Replay()))
+
+			failures = 0
+			if err = d.replay(s); err == nil {
+				err = d.pump(s)
+			}
+			s.Close()
+			if err == nil {
+				return // 종료
+			}
+		}
+
+		failures++
+		delay := d.backoff(failures)
+		logger.Warnf("목적지 전송 실패, %s 후 재연결: %v", delay, err)
+		d.setState(StateReconnecting, err)
+		if !d.wait(delay) {
+			return
+		}
+	}
+}
+
+// backoff : ReconnectInterval * 2^(failures-1), 최대 MaxReconnectInterval
+func (d *Destination) backoff(failures int) time.Duration {
+	delay := time.Duration(d.Config.ReconnectInterval) * time.Second
+	limit := time.Duration(d.Config.MaxReconnectInterval) * time.Second
+	for i := 1; i < failures && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
+
+// replay : 메타데이터 + sequence header + 마지막 GOP
+func (d *Destination) replay(s sink) error {
+	for _, p := range d.cache.Replay() {
+		if err := s.Write(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pump : 종료되면 버퍼에 남은 것까지 쓰고 nil, 전송 실패하면 에러
+func (d *Destination) pump(s sink) error {
+	for {
+		select {
+		case <-d.ctx.Done():
+			return d.drain(s)
+		case p := <-d.queue:
+			if err := d.write(s, p); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// drain : Close 뒤에 남은 패킷 (unpublish 하면 녹화 끝부분이 잘리지 않게), Close 는 Send 와 같은 goroutine 이라 더 들어오지 않음
+func (d *Destination) drain(s sink) error {
+	for {
+		select {
+		case p := <-d.queue:
+			if err := d.write(s, p); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+func (d *Destination) write(s sink, p *Packet) error {
+	d.cache.Add(p)
+	if err := s.Write(p); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.status.Sent++
+	d.mu.Unlock()
+	return nil
+}
+
+// wait : 재연결 대기, 그동안 들어오는 패킷은 GOP 캐시에만 쌓음. 종료되면 false
+func (d *Destination) wait(delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return false
+		case p := <-d.queue:
+			d.cache.Add(p)
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+func (d *Destination) open() (sink, error) {
+	switch d.Config.Type {
+	case DestinationTypeRTMP:
+		return dialRTMP(d.Config.Host, d.App, d.StreamKey)
+	case DestinationTypeFLV:
+		return NewRecorder(RecorderConfig{Dir: d.Config.Dir}, d.App, d.StreamKey)
+	case DestinationTypeHLS:
+		return NewHLSWriter(HLSConfig{Dir: d.Config.Dir}, d.StreamKey)
+	default:
+		return nil, fmt.Errorf("unsupported destination type: %s", d.Config.Type)
+	}
+}
+
+// rtmpSink : upstream 으로 publish (기존 RelayHandler.OnPublish 로직)
+type rtmpSink struct {
+	conn   *rtmp.ClientConn
+	stream *rtmp.Stream
+}
+
+func dialRTMP(host, app, streamKey string) (sink, error) {
+	conn, err := rtmp.Dial("rtmp", host, &rtmp.ConnConfig{
+		Logger: log.StandardLogger(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dial fail: %w", err)
+	}
+
+	if err := conn.Connect(&rtmpmsg.NetConnectionConnect{
+		Command: rtmpmsg.NetConnectionConnectCommand{App: app},
+	}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("connect fail: %w", err)
+	}
+
+	stream, err := conn.CreateStream(&rtmpmsg.NetConnectionCreateStream{}, 128)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("create stream fail: %w", err)
+	}
+
+	if err := stream.Publish(&rtmpmsg.NetStreamPublish{
+		PublishingName: streamKey,
+		PublishingType: "live", // 이거 빼면 EOF 나버림...
+	}); err != nil {
+		stream.Close()
+		conn.Close()
+		return nil, fmt.Errorf("publish fail: %w", err)
+	}
+
+	return &rtmpSink{conn: conn, stream: stream}, nil
+}
+
+func (s *rtmpSink) Write(p *Packet) error {
+	chunkStreamID, msg := p.Message()
+	return s.stream.Write(chunkStreamID, p.Timestamp, msg)
+}
+
+func (s *rtmpSink) Close() error {
+	s.stream.Close()
+	return s.conn.Close()
+}
@@ -0,0 +1,74 @@
+package server
+
+// FLV 태그 헤더 값
+const (
+	soundFormatAAC = 10
+	codecIDAVC     = 7
+	frameTypeKey   = 1
+
+	maxGOPPackets = 2048 // keyframe 간격이 너무 길면 (or keyframe 이 안오면) 버림
+)
+
+// IsKeyframe : 영상 keyframe (sequence header 제외)
+func (p *Packet) IsKeyframe() bool {
+	return p.Type == PacketVideo && len(p.Payload) > 0 && p.Payload[0]>>4 == frameTypeKey && !p.IsSequenceHeader()
+}
+
+// IsSequenceHeader : AVC decoder config / AAC audio specific config
+func (p *Packet) IsSequenceHeader() bool {
+	if len(p.Payload) < 2 {
+		return false
+	}
+	switch p.Type {
+	case PacketVideo:
+		return p.Payload[0]&0x0f == codecIDAVC && p.Payload[1] == 0
+	case PacketAudio:
+		return p.Payload[0]>>4 == soundFormatAAC && p.Payload[1] == 0
+	}
+	return false
+}
+
+// GOPCache : 디코딩을 다시 시작하는데 필요한 패킷들
+// 메타데이터(@setDataFrame) + AAC/AVC sequence header + 마지막 keyframe 부터의 패킷
+type GOPCache struct {
+	metadata    *Packet
+	audioHeader *Packet
+	videoHeader *Packet
+	gop         []*Packet
+}
+
+func (c *GOPCache) Add(p *Packet) {
+	switch {
+	case p.Type == PacketData:
+		c.metadata = p
+	case p.IsSequenceHeader() && p.Type == PacketAudio:
+		c.audioHeader = p
+	case p.IsSequenceHeader() && p.Type == PacketVideo:
+		c.videoHeader = p
+	case p.IsKeyframe():
+		c.gop = append(c.gop[:0], p)
+	case len(c.gop) > 0:
+		if len(c.gop) >= maxGOPPackets {
+			c.gop = c.gop[:0] // 다음 keyframe 까지 안 쌓음
+			return
+		}
+		c.gop = append(c.gop, p)
+	}
+}
+
+// Headers : 메타데이터 + sequence header (있는것만)
+func (c *GOPCache) Headers() []*Packet {
+	headers := make([]*Packet, 0, 3)
+	for _, p := range []*Packet{c.metadata, c.videoHeader, c.audioHeader} {
+		if p != nil {
+			headers = append(headers, p)
+		}
+	}
+	return headers
+}
+
+// Replay : 새로 연결된 쪽에 보낼 순서 (헤더 -> 마지막 GOP)
+func (c *GOPCache) Replay() []*Packet {
+	packets := c.Headers()
+	return append(packets, c.gop...)
+}
@@ -2,14 +2,13 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
+	"time"
 
 	"github.com/labstack/gommon/log"
-	"github.com/yutopp/go-flv"
 	flvtag "github.com/yutopp/go-flv/tag"
 	"github.com/yutopp/go-rtmp"
 	rtmpmsg "github.com/yutopp/go-rtmp/message"
@@ -17,20 +16,41 @@ import (
 
 var _ rtmp.Handler = (*Handler)(nil)
 
+const playWriteTimeout = 10 * time.Second // 이 안에 못 보내면 (안 읽는 player) 연결 끊음
+
 // Handler An RTMP connection handler
 type Handler struct {
 	rtmp.DefaultHandler
-	flvFile *os.File
-	flvEnc  *flv.Encoder
+	PublishGuard
+	Record   RecorderConfig  // 비어있으면 os.TempDir() 에 회전 없이 녹화
+	HLS      *HLSConfig      // nil 이면 HLS 출력 안함
+	Captions *CaptionService // nil 이면 라이브 자막 안함
+	Session  *Session        // nil 이면 세션 집계 안함
+	Hub      *PubSubHub      // nil 이면 play 안됨
+
+	conn       *rtmp.Conn
+	writes     *writeLock // WrapConn 으로 감싼 연결만 play 가능
+	app        string
+	outputs    []sink      // 녹화, HLS, 자막, play 구독자 (publish 전에는 비어있음)
+	subscriber *Subscriber // play 중일때만
+}
+
+// WrapConn : OnConnect 에서 돌려줄 conn 을 감쌈 (play 전송을 라이브러리 쓰기와 직렬화, writeLock 참고)
+func (h *Handler) WrapConn(conn io.ReadWriteCloser) io.ReadWriteCloser {
+	h.writes = newWriteLock()
+	return h.writes.wrap(conn)
 }
 
-func (h *Handler) OnServe(conn *rtmp.Conn) {}
+func (h *Handler) OnServe(conn *rtmp.Conn) {
+	h.conn = conn
+}
 
 func (h *Handler) OnConnect(timestamp uint32, cmd *rtmpmsg.NetConnectionConnect) error {
 	appName := cmd.Command.App
 	log.Printf("OnConnect - App Name: '%s'", appName)
 	log.Printf("OnConnect - Full command: %#v", cmd)
-	return nil
+	h.app = appName
+	return h.authorizeConnect(appName)
 }
 
 func (h *Handler) OnCreateStream(timestamp uint32, cmd *rtmpmsg.NetConnectionCreateStream) error {
@@ -41,123 +61,148 @@ func (h *Handler) OnCreateStream(timestamp uint32, cmd *rtmpmsg.NetConnectionCre
 func (h *Handler) OnPublish(_ *rtmp.StreamContext, timestamp uint32, cmd *rtmpmsg.NetStreamPublish) error {
 	log.Printf("OnPublish: %#v", cmd)
 
-	// (example) Reject a connection when PublishingName is empty
-	if cmd.PublishingName == "" {
-		return errors.New("PublishingName is empty")
+	if h.subscriber != nil {
+		return errors.New("cannot publish on a playing connection")
+	}
+
+	// 인증 + 동시 publish 제한 (PublishingName 이 비어있거나 이미 publish 중인 연결이면 거부)
+	streamKey, err := h.authorizePublish(cmd.PublishingName)
+	if err != nil {
+		log.Printf("OnPublish rejected: %v", err)
+		return err
+	}
+
+	// 같은 서버에서 play 할 수 있게 (같은 stream key 중복 publish 는 거부)
+	if h.Hub != nil {
+		pub, err := h.Hub.Publish(h.app, streamKey)
+		if err != nil {
+			log.Printf("OnPublish rejected: %v", err)
+			return err
+		}
+		h.outputs = append(h.outputs, pub)
 	}
 
 	// Record streams as FLV!
-	p := filepath.Join(
-		os.TempDir(),
-		filepath.Clean(filepath.Join("/", fmt.Sprintf("%s.flv", cmd.PublishingName))),
-	)
-	f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY, 0666)
+	recorder, err := NewRecorder(h.Record, h.app, streamKey)
 	if err != nil {
-		return fmt.Errorf("failed to create flv file : %v", err)
+		return fmt.Errorf("failed to create recorder : %w", err)
 	}
-	h.flvFile = f
+	h.outputs = append(h.outputs, recorder)
+	h.Session.Publish(h.app, streamKey)
+
+	if h.HLS != nil {
+		if hls, err := NewHLSWriter(*h.HLS, streamKey); err != nil {
+			log.Printf("Failed to create hls writer: Err = %+v", err)
+		} else {
+			h.outputs = append(h.outputs, hls)
+		}
+	}
+	if h.Captions != nil {
+		if captions, err := h.Captions.Start(streamKey); err != nil {
+			log.Printf("Failed to start captions: Err = %+v", err)
+		} else {
+			h.outputs = append(h.outputs, captions)
+		}
+	}
+
+	return nil
+}
 
-	enc, err := flv.NewEncoder(f, flv.FlagsAudio|flv.FlagsVideo)
+// OnPlay : publish 중인 스트림을 구독, 전송은 구독자 goroutine 에서 (publisher 를 막지 않음)
+// 구독자 goroutine 은 writeLock 을 잡고 쓰니까 Play.Start 응답이 나간 다음부터 전송됨
+func (h *Handler) OnPlay(ctx *rtmp.StreamContext, timestamp uint32, cmd *rtmpmsg.NetStreamPlay) error {
+	log.Printf("OnPlay: %#v", cmd)
+
+	if h.Hub == nil || h.writes == nil {
+		return errors.New("play is not enabled")
+	}
+	if h.subscriber != nil || len(h.outputs) > 0 {
+		return errors.New("cannot play on this connection")
+	}
+
+	streamKey, _ := ParsePublishingName(cmd.StreamName)
+	subscriber, err := h.Hub.Subscribe(h.app, streamKey)
 	if err != nil {
-		_ = f.Close()
-		return fmt.Errorf("failed to create fiv encoder : %v", err)
+		log.Printf("OnPlay rejected: %v", err)
+		return err
 	}
-	h.flvEnc = enc
+	h.subscriber = subscriber
+
+	conn, writes, streamID, app := h.conn, h.writes, ctx.StreamID, h.app
+	go func() {
+		err := subscriber.Run(func(p *Packet) error {
+			chunkStreamID, msg := p.Message()
+			return writes.do(subscriber.stop, func() error {
+				ctx, cancel := context.WithTimeout(context.Background(), playWriteTimeout)
+				defer cancel()
+				return conn.Write(ctx, chunkStreamID, p.Timestamp, &rtmp.ChunkMessage{StreamID: streamID, Message: msg})
+			})
+		})
+		if err != nil {
+			_ = writes.close()
+		}
+		log.Printf("Play finished: %s/%s dropped=%d err=%v", app, streamKey, subscriber.Dropped(), err)
+	}()
 
 	return nil
 }
 
 func (h *Handler) OnSetDataFrame(timestamp uint32, data *rtmpmsg.NetStreamSetDataFrame) error {
-	r := bytes.NewReader(data.Payload)
-
 	var script flvtag.ScriptData
-	if err := flvtag.DecodeScriptData(r, &script); err != nil {
+	if err := flvtag.DecodeScriptData(bytes.NewReader(data.Payload), &script); err != nil {
 		log.Printf("Failed to decode script data: Err = %+v", err)
 		return nil // ignore
 	}
 
 	log.Printf("SetDataFrame: Script = %#v", script)
 
-	if err := h.flvEnc.Encode(&flvtag.FlvTag{
-		TagType:   flvtag.TagTypeScriptData,
-		Timestamp: timestamp,
-		Data:      &script,
-	}); err != nil {
-		log.Printf("Failed to write script data: Err = %+v", err)
-	}
-
+	h.writeOutputs(&Packet{Type: PacketData, Timestamp: timestamp, Payload: data.Payload})
 	return nil
 }
 
+// OnAudio / OnVideo : 태그별 로그 대신 세션 통계 (GET /sessions)
 func (h *Handler) OnAudio(timestamp uint32, payload io.Reader) error {
-	var audio flvtag.AudioData
-	if err := flvtag.DecodeAudioData(payload, &audio); err != nil {
-		return err
-	}
-
-	flvBody := new(bytes.Buffer)
-	if _, err := io.Copy(flvBody, audio.Data); err != nil {
-		return err
-	}
-	audio.Data = flvBody
-
-	log.Printf("FLV Audio Data: Timestamp = %d, SoundFormat = %+v, SoundRate = %+v, SoundSize = %+v, SoundType = %+v, AACPacketType = %+v, Data length = %+v",
-		timestamp,
-		audio.SoundFormat,
-		audio.SoundRate,
-		audio.SoundSize,
-		audio.SoundType,
-		audio.AACPacketType,
-		len(flvBody.Bytes()),
-	)
-
-	if err := h.flvEnc.Encode(&flvtag.FlvTag{
-		TagType:   flvtag.TagTypeAudio,
-		Timestamp: timestamp,
-		Data:      &audio,
-	}); err != nil {
-		log.Printf("Failed to write audio: Err = %+v", err)
-	}
-
-	return nil
+	return h.onMedia(PacketAudio, timestamp, payload)
 }
 
 func (h *Handler) OnVideo(timestamp uint32, payload io.Reader) error {
-	var video flvtag.VideoData
-	if err := flvtag.DecodeVideoData(payload, &video); err != nil {
-		return err
-	}
+	return h.onMedia(PacketVideo, timestamp, payload)
+}
 
-	flvBody := new(bytes.Buffer)
-	if _, err := io.Copy(flvBody, video.Data); err != nil {
+func (h *Handler) onMedia(packetType PacketType, timestamp uint32, payload io.Reader) error {
+	raw := new(bytes.Buffer)
+	if _, err := io.Copy(raw, payload); err != nil {
 		return err
 	}
-	video.Data = flvBody
-
-	log.Printf("FLV Video Data: Timestamp = %d, FrameType = %+v, CodecID = %+v, AVCPacketType = %+v, CT = %+v, Data length = %+v",
-		timestamp,
-		video.FrameType,
-		video.CodecID,
-		video.AVCPacketType,
-		video.CompositionTime,
-		len(flvBody.Bytes()),
-	)
-
-	if err := h.flvEnc.Encode(&flvtag.FlvTag{
-		TagType:   flvtag.TagTypeVideo,
-		Timestamp: timestamp,
-		Data:      &video,
-	}); err != nil {
-		log.Printf("Failed to write video: Err = %+v", err)
-	}
 
+	p := &Packet{Type: packetType, Timestamp: timestamp, Payload: raw.Bytes()}
+	h.Session.Observe(p)
+	h.writeOutputs(p)
 	return nil
 }
 
+// writeOutputs : 출력 하나가 실패해도 나머지는 계속 씀
+func (h *Handler) writeOutputs(p *Packet) {
+	for _, output := range h.outputs {
+		if err := output.Write(p); err != nil {
+			log.Printf("Failed to write %T: Err = %+v", output, err)
+		}
+	}
+}
+
 func (h *Handler) OnClose() {
 	log.Printf("OnClose")
+	h.release()
 
-	if h.flvFile != nil {
-		_ = h.flvFile.Close()
+	if h.subscriber != nil {
+		_ = h.subscriber.Close()
 	}
+	for _, output := range h.outputs {
+		if err := output.Close(); err != nil {
+			log.Printf("Failed to close %T: Err = %+v", output, err)
+		}
+	}
+	h.outputs = nil
+
+	h.Session.Close() // 녹화 파일까지 닫힌 다음에 session.end
 }
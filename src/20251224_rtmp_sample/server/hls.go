@@ -0,0 +1,267 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultHLSTargetDuration = 4 // 단위: second
+	defaultHLSWindowSize     = 6
+
+	hlsLivePlaylist = "index.m3u8"
+	hlsVODPlaylist  = "vod.m3u8"
+
+	audioOnlyWait = 2000 // ms, 이 시간동안 영상 sequence header 가 없으면 오디오만 있는 스트림으로 봄
+)
+
+// HLSConfig : HLS 출력 설정
+type HLSConfig struct {
+	Dir            string `json:"dir"`             // <Dir>/<stream key>/index.m3u8 (비어있으면 os.TempDir()/hls)
+	TargetDuration int    `json:"target_duration"` // 세그먼트 길이 (keyframe 에서만 자르니까 더 길어질 수 있음, 단위: second)
+	WindowSize     int    `json:"window_size"`     // live playlist 에 남길 세그먼트 수
+}
+
+type hlsSegment struct {
+	Name     string
+	Duration float64
+}
+
+// HLSWriter : FLV 패킷(AVC/AAC) -> MPEG-TS 세그먼트 + live playlist, 종료시 VOD playlist
+// 세그먼트 파일은 지우지 않음 (VOD playlist 에서 전체를 다시 씀)
+type HLSWriter struct {
+	Config HLSConfig
+	Dir    string
+
+	avc *avcConfig
+	aac *aacConfig
+
+	file  *os.File
+	buf   *bufio.Writer
+	muxer *TSMuxer
+
+	segments   []hlsSegment
+	maxSegment float64 // 지금까지 닫힌 세그먼트 중 가장 긴 길이 (TARGETDURATION 은 window 가 밀려도 줄어들면 안됨, RFC 8216 6.2.1)
+	segStart   uint32  // 현재 세그먼트 시작 (ms)
+	lastTS     uint32
+	firstAudio int64 // 첫 오디오 timestamp (audio only 판단용), -1 이면 아직 없음
+}
+
+func NewHLSWriter(config HLSConfig, streamKey string) (*HLSWriter, error) {
+	if config.Dir == "" {
+		config.Dir = filepath.Join(os.TempDir(), "hls")
+	}
+	if config.TargetDuration <= 0 {
+		config.TargetDuration = defaultHLSTargetDuration
+	}
+	if config.WindowSize <= 0 {
+		config.WindowSize = defaultHLSWindowSize
+	}
+
+	dir := filepath.Join(config.Dir, filepath.Clean(filepath.Join("/", streamKey)))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create hls dir failed: %w", err)
+	}
+
+	return &HLSWriter{Config: config, Dir: dir, firstAudio: -1}, nil
+}
+
+// Write : sink 구현 (Destination type "hls" 에서도 사용)
+func (w *HLSWriter) Write(p *Packet) error {
+	if len(p.Payload) < 2 {
+		return nil
+	}
+
+	switch p.Type {
+	case PacketVideo:
+		return w.writeVideo(p)
+	case PacketAudio:
+		return w.writeAudio(p)
+	}
+	return nil // 메타데이터는 TS 에 안 넣음
+}
+
+func (w *HLSWriter) writeVideo(p *Packet) error {
+	if p.Payload[0]&0x0f != codecIDAVC {
+		return nil // H.264 만 지원
+	}
+	if len(p.Payload) < 5 {
+		return errShortPayload
+	}
+
+	if p.IsSequenceHeader() {
+		config, err := parseAVCConfig(p.Payload[5:])
+		if err != nil {
+			return fmt.Errorf("invalid avc sequence header: %w", err)
+		}
+		w.avc = config
+		return nil
+	}
+	if w.avc == nil {
+		return nil
+	}
+
+	keyframe := p.IsKeyframe()
+	if keyframe && (w.file == nil || w.elapsed(p.Timestamp) >= float64(w.Config.TargetDuration)) {
+		if err := w.cut(p.Timestamp); err != nil {
+			return err
+		}
+	}
+	if w.file == nil {
+		return nil // 첫 keyframe 전
+	}
+
+	au, err := w.avc.annexB(p.Payload[5:], keyframe)
+	if err != nil {
+		return err
+	}
+
+	cts := int32(uint32(p.Payload[2])<<16|uint32(p.Payload[3])<<8|uint32(p.Payload[4])) << 8 >> 8 // 24bit signed
+	dts := uint64(p.Timestamp) * 90
+	pts := uint64(max(int64(p.Timestamp)+int64(cts), 0)) * 90
+
+	w.lastTS = p.Timestamp
+	return w.muxer.WriteVideo(pts, dts, au, keyframe)
+}
+
+func (w *HLSWriter) writeAudio(p *Packet) error {
+	if p.Payload[0]>>4 != soundFormatAAC {
+		return nil // AAC 만 지원
+	}
+
+	if p.IsSequenceHeader() {
+		config, err := parseAACConfig(p.Payload[2:])
+		if err != nil {
+			return fmt.Errorf("invalid aac sequence header: %w", err)
+		}
+		w.aac = config
+		return nil
+	}
+	if w.aac == nil {
+		return nil
+	}
+
+	if w.firstAudio < 0 {
+		w.firstAudio = int64(p.Timestamp)
+	}
+	// 오디오만 있는 스트림이면 오디오 기준으로 자름
+	if w.avc == nil && int64(p.Timestamp)-w.firstAudio >= audioOnlyWait &&
+		(w.file == nil || w.elapsed(p.Timestamp) >= float64(w.Config.TargetDuration)) {
+		if err := w.cut(p.Timestamp); err != nil {
+			return err
+		}
+	}
+	if w.file == nil {
+		return nil
+	}
+
+	w.lastTS = max(w.lastTS, p.Timestamp)
+	return w.muxer.WriteAudio(uint64(p.Timestamp)*90, w.aac.adts(p.Payload[2:]))
+}
+
+func (w *HLSWriter) elapsed(ts uint32) float64 {
+	return float64(int64(ts)-int64(w.segStart)) / 1000
+}
+
+// cut : 현재 세그먼트 닫고 새 세그먼트 시작
+func (w *HLSWriter) cut(ts uint32) error {
+	if w.file != nil {
+		if err := w.closeSegment(w.elapsed(ts)); err != nil {
+			return err
+		}
+		if err := w.writePlaylist(false); err != nil {
+			return err
+		}
+	}
+
+	name := fmt.Sprintf("segment_%05d.ts", len(w.segments))
+	f, err := os.Create(filepath.Join(w.Dir, name))
+	if err != nil {
+		return fmt.Errorf("create segment failed: %w", err)
+	}
+
+	w.file = f
+	w.buf = bufio.NewWriter(f)
+	w.muxer = NewTSMuxer(w.buf, w.avc != nil, w.aac != nil)
+	w.segStart = ts
+	w.segments = append(w.segments, hlsSegment{Name: name})
+
+	return w.muxer.WriteTables()
+}
+
+func (w *HLSWriter) closeSegment(duration float64) error {
+	w.segments[len(w.segments)-1].Duration = max(duration, 0.001)
+	w.maxSegment = max(w.maxSegment, duration)
+
+	err := w.buf.Flush()
+	if err == nil {
+		err = w.file.Sync()
+	}
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	w.file, w.buf, w.muxer = nil, nil, nil
+	return err
+}
+
+// Close : 마지막 세그먼트 마무리 + live playlist 에 ENDLIST + VOD playlist
+func (w *HLSWriter) Close() error {
+	if w.file == nil {
+		return nil
+	}
+
+	if err := w.closeSegment(w.elapsed(w.lastTS)); err != nil {
+		return err
+	}
+	if err := w.writePlaylist(true); err != nil {
+		return err
+	}
+
+	log.Infof("HLS 종료: %s (%d segments)", w.Dir, len(w.segments))
+	return writeFileAtomic(filepath.Join(w.Dir, hlsVODPlaylist), w.playlist(w.segments, 0, true, true))
+}
+
+// writePlaylist : 마지막 WindowSize 개 세그먼트만
+func (w *HLSWriter) writePlaylist(ended bool) error {
+	closed := w.segments
+	if !ended && w.file != nil {
+		closed = closed[:len(closed)-1] // 쓰는중인 세그먼트 제외
+	}
+
+	start := max(len(closed)-w.Config.WindowSize, 0)
+	return writeFileAtomic(filepath.Join(w.Dir, hlsLivePlaylist), w.playlist(closed[start:], start, ended, false))
+}
+
+func (w *HLSWriter) playlist(segments []hlsSegment, sequence int, ended, vod bool) []byte {
+	target := max(float64(w.Config.TargetDuration), w.maxSegment)
+
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	if vod {
+		b.WriteString("#EXT-X-PLAYLIST-TYPE:VOD\n")
+	}
+	fmt.Fprintf(&b, "#EXT-X-TARGETDURATION:%d\n", int(math.Ceil(target)))
+	fmt.Fprintf(&b, "#EXT-X-MEDIA-SEQUENCE:%d\n", sequence)
+	for _, s := range segments {
+		fmt.Fprintf(&b, "#EXTINF:%.3f,\n%s\n", s.Duration, s.Name)
+	}
+	if ended {
+		b.WriteString("#EXT-X-ENDLIST\n")
+	}
+	return []byte(b.String())
+}
+
+// writeFileAtomic : 플레이어가 반쯤 쓰인 playlist 를 읽지 않게 temp 에 쓰고 rename
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
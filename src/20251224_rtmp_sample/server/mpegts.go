@@ -0,0 +1,244 @@
+package server
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MPEG-TS muxer (H.264 + AAC 만, HLS 세그먼트용)
+// Ref. ISO/IEC 13818-1
+
+const (
+	tsPacketSize = 188
+
+	pidPAT   = 0x0000
+	pidPMT   = 0x1000
+	pidVideo = 0x0100
+	pidAudio = 0x0101
+
+	streamTypeH264 = 0x1B
+	streamTypeAAC  = 0x0F
+
+	streamIDVideo = 0xE0
+	streamIDAudio = 0xC0
+)
+
+// TSMuxer : 세그먼트 하나당 하나 (continuity counter 는 세그먼트마다 새로 시작해도 됨)
+type TSMuxer struct {
+	w        io.Writer
+	hasVideo bool
+	hasAudio bool
+	cc       map[uint16]byte
+}
+
+func NewTSMuxer(w io.Writer, hasVideo, hasAudio bool) *TSMuxer {
+	return &TSMuxer{w: w, hasVideo: hasVideo, hasAudio: hasAudio, cc: make(map[uint16]byte)}
+}
+
+func (m *TSMuxer) pcrPID() uint16 {
+	if m.hasVideo {
+		return pidVideo
+	}
+	return pidAudio
+}
+
+// WriteTables : PAT + PMT (세그먼트 맨 앞에 한번)
+func (m *TSMuxer) WriteTables() error {
+	pat := []byte{
+		0x00,       // table_id
+		0xB0, 0x00, // section_syntax_indicator + section_length (아래서 채움)
+		0x00, 0x01, // transport_stream_id
+		0xC1,       // version 0, current_next 1
+		0x00, 0x00, // section_number, last_section_number
+		0x00, 0x01, // program_number
+		0xE0 | byte(pidPMT>>8), byte(pidPMT & 0xFF),
+	}
+	if err := m.writeSection(pidPAT, pat); err != nil {
+		return err
+	}
+
+	pcr := m.pcrPID()
+	pmt := []byte{
+		0x02,
+		0xB0, 0x00,
+		0x00, 0x01, // program_number
+		0xC1,
+		0x00, 0x00,
+		0xE0 | byte(pcr>>8), byte(pcr & 0xFF),
+		0xF0, 0x00, // program_info_length
+	}
+	if m.hasVideo {
+		pmt = append(pmt, streamTypeH264, 0xE0|byte(pidVideo>>8), byte(pidVideo&0xFF), 0xF0, 0x00)
+	}
+	if m.hasAudio {
+		pmt = append(pmt, streamTypeAAC, 0xE0|byte(pidAudio>>8), byte(pidAudio&0xFF), 0xF0, 0x00)
+	}
+	return m.writeSection(pidPMT, pmt)
+}
+
+// writeSection : section_length + CRC 채워서 TS 패킷 하나로 씀
+func (m *TSMuxer) writeSection(pid uint16, section []byte) error {
+	length := len(section) - 3 + 4 // section_length 이후 ~ CRC 포함
+	section[1] = 0xB0 | byte(length>>8)
+	section[2] = byte(length)
+	section = binary.BigEndian.AppendUint32(section, crc32MPEG(section))
+
+	packet := make([]byte, tsPacketSize)
+	packet[0] = 0x47
+	packet[1] = 0x40 | byte(pid>>8) // payload_unit_start
+	packet[2] = byte(pid)
+	packet[3] = 0x10 | m.nextCC(pid)
+	packet[4] = 0x00 // pointer_field
+	n := copy(packet[5:], section)
+	for i := 5 + n; i < tsPacketSize; i++ {
+		packet[i] = 0xFF
+	}
+
+	_, err := m.w.Write(packet)
+	return err
+}
+
+// WriteVideo : Annex B access unit 하나 (pts, dts 는 90kHz)
+func (m *TSMuxer) WriteVideo(pts, dts uint64, au []byte, keyframe bool) error {
+	header := []byte{0x00, 0x00, 0x01, streamIDVideo, 0x00, 0x00, 0x80, 0xC0, 10}
+	header = appendTimestamp(header, 0x3, pts)
+	header = appendTimestamp(header, 0x1, dts)
+	// 영상은 PES_packet_length 0 (길이 제한 없음)
+	return m.writePES(pidVideo, append(header, au...), keyframe, dts, true)
+}
+
+// WriteAudio : ADTS 헤더 붙은 AAC 프레임
+func (m *TSMuxer) WriteAudio(pts uint64, frame []byte) error {
+	header := []byte{0x00, 0x00, 0x01, streamIDAudio, 0x00, 0x00, 0x80, 0x80, 5}
+	header = appendTimestamp(header, 0x2, pts)
+
+	length := len(header) - 6 + len(frame)
+	if length > 0xFFFF {
+		return fmt.Errorf("audio PES too large: %d", length)
+	}
+	binary.BigEndian.PutUint16(header[4:], uint16(length))
+	return m.writePES(pidAudio, append(header, frame...), false, pts, !m.hasVideo)
+}
+
+// writePES : PES 를 TS 패킷으로 나눠서 씀, 첫 패킷에 PCR / random access 표시
+func (m *TSMuxer) writePES(pid uint16, pes []byte, randomAccess bool, pcr uint64, withPCR bool) error {
+	first := true
+	packet := make([]byte, tsPacketSize)
+
+	for len(pes) > 0 {
+		// adaptation field (length byte 제외)
+		var af []byte
+		if first && (withPCR || randomAccess) {
+			flags := byte(0)
+			if randomAccess {
+				flags |= 0x40
+			}
+			af = append(af, flags)
+			if withPCR {
+				af[0] |= 0x10
+				af = append(af, byte(pcr>>25), byte(pcr>>17), byte(pcr>>9), byte(pcr>>1), byte(pcr<<7)|0x7E, 0x00)
+			}
+		}
+
+		afSize := 0
+		if af != nil {
+			afSize = 1 + len(af)
+		}
+
+		// 마지막 패킷은 adaptation field 로 stuffing
+		if stuff := 184 - afSize - len(pes); stuff > 0 {
+			switch {
+			case af != nil:
+				af = append(af, fill(stuff)...)
+			case stuff == 1:
+				af = []byte{} // length byte(0) 만
+			default:
+				af = append([]byte{0x00}, fill(stuff-2)...)
+			}
+			afSize = 1 + len(af)
+		}
+
+		packet[0] = 0x47
+		packet[1] = byte(pid >> 8)
+		if first {
+			packet[1] |= 0x40
+		}
+		packet[2] = byte(pid)
+		control := byte(0x10)
+		if af != nil {
+			control = 0x30
+		}
+		packet[3] = control | m.nextCC(pid)
+
+		offset := 4
+		if af != nil {
+			packet[4] = byte(len(af))
+			copy(packet[5:], af)
+			offset += afSize
+		}
+
+		n := copy(packet[offset:], pes)
+		if offset+n != tsPacketSize {
+			return errors.New("ts packet size mismatch")
+		}
+		if _, err := m.w.Write(packet); err != nil {
+			return err
+		}
+
+		pes = pes[n:]
+		first = false
+	}
+	return nil
+}
+
+func (m *TSMuxer) nextCC(pid uint16) byte {
+	cc := m.cc[pid]
+	m.cc[pid] = (cc + 1) & 0x0F
+	return cc
+}
+
+func fill(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = 0xFF
+	}
+	return b
+}
+
+// appendTimestamp : 33bit PTS/DTS -> 5 bytes (marker bit 포함)
+func appendTimestamp(b []byte, prefix byte, ts uint64) []byte {
+	return append(b,
+		prefix<<4|byte(ts>>29)&0x0E|1,
+		byte(ts>>22),
+		byte(ts>>14)&0xFE|1,
+		byte(ts>>7),
+		byte(ts<<1)|1,
+	)
+}
+
+var crcTable = func() [256]uint32 {
+	var table [256]uint32
+	for i := range table {
+		crc := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04C11DB7
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+// crc32MPEG : CRC-32/MPEG-2 (PSI 섹션용, hash/crc32 는 reflected 라 못씀)
+func crc32MPEG(data []byte) uint32 {
+	crc := uint32(0xFFFFFFFF)
+	for _, b := range data {
+		crc = crc<<8 ^ crcTable[byte(crc>>24)^b]
+	}
+	return crc
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+
+	flvtag "github.com/yutopp/go-flv/tag"
+	rtmpmsg "github.com/yutopp/go-rtmp/message"
+)
+
+type PacketType int
+
+const (
+	PacketAudio PacketType = iota
+	PacketVideo
+	PacketData // @setDataFrame (onMetaData)
+)
+
+// RTMP chunk stream id (기존 relay 에서 쓰던 값 + data 는 go-rtmp 예제 기준)
+const (
+	chunkStreamAudio = 4
+	chunkStreamVideo = 6
+	chunkStreamData  = 8
+)
+
+// Packet : publish 된 메시지 하나
+// OnAudio / OnVideo 의 payload reader 는 한번만 읽을 수 있어서 bytes 로 복사해두고, 목적지마다 reader 를 새로 만듦
+// 여러 목적지가 같이 보니까 Payload 는 수정하면 안됨
+type Packet struct {
+	Type      PacketType
+	Timestamp uint32
+	Payload   []byte
+}
+
+func (p *Packet) String() string {
+	return fmt.Sprintf("packet(type=%d, ts=%d, len=%d)", p.Type, p.Timestamp, len(p.Payload))
+}
+
+// Message : RTMP 로 다시 보낼 메시지 (chunk stream id 포함)
+func (p *Packet) Message() (int, rtmpmsg.Message) {
+	switch p.Type {
+	case PacketAudio:
+		return chunkStreamAudio, &rtmpmsg.AudioMessage{Payload: bytes.NewReader(p.Payload)}
+	case PacketVideo:
+		return chunkStreamVideo, &rtmpmsg.VideoMessage{Payload: bytes.NewReader(p.Payload)}
+	default:
+		return chunkStreamData, &rtmpmsg.DataMessage{
+			Name:     "@setDataFrame",
+			Encoding: rtmpmsg.EncodingTypeAMF0,
+			Body:     bytes.NewReader(p.Payload),
+		}
+	}
+}
+
+// FlvTag : FLV 파일에 쓸 태그로 변환
+func (p *Packet) FlvTag() (*flvtag.FlvTag, error) {
+	r := bytes.NewReader(p.Payload)
+
+	switch p.Type {
+	case PacketAudio:
+		var audio flvtag.AudioData
+		if err := flvtag.DecodeAudioData(r, &audio); err != nil {
+			return nil, err
+		}
+		return &flvtag.FlvTag{TagType: flvtag.TagTypeAudio, Timestamp: p.Timestamp, Data: &audio}, nil
+	case PacketVideo:
+		var video flvtag.VideoData
+		if err := flvtag.DecodeVideoData(r, &video); err != nil {
+			return nil, err
+		}
+		return &flvtag.FlvTag{TagType: flvtag.TagTypeVideo, Timestamp: p.Timestamp, Data: &video}, nil
+	default:
+		var script flvtag.ScriptData
+		if err := flvtag.DecodeScriptData(r, &script); err != nil {
+			return nil, err
+		}
+		return &flvtag.FlvTag{TagType: flvtag.TagTypeScriptData, Timestamp: p.Timestamp, Data: &script}, nil
+	}
+}
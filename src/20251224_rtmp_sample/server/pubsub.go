@@ -0,0 +1,199 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	ErrStreamNotFound = errors.New("stream not found")
+	ErrStreamBusy     = errors.New("stream is already publishing")
+)
+
+// PubSubHub : 서버에 publish 된 스트림을 같은 서버의 play 로 내보냄 (app/stream key 로 구분)
+type PubSubHub struct {
+	BufferSize int // 구독자별 패킷 버퍼, 꽉 차면 그 구독자만 다음 keyframe 까지 버림 (기본 defaultBufferSize)
+
+	mu      sync.Mutex
+	streams map[string]*Publication
+}
+
+func NewPubSubHub() *PubSubHub {
+	return &PubSubHub{streams: make(map[string]*Publication)}
+}
+
+func streamName(app, streamKey string) string {
+	return app + "/" + streamKey
+}
+
+// Publish : 같은 app/stream key 가 이미 publish 중이면 ErrStreamBusy
+func (h *PubSubHub) Publish(app, streamKey string) (*Publication, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	name := streamName(app, streamKey)
+	if _, ok := h.streams[name]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrStreamBusy, name)
+	}
+
+	pub := &Publication{hub: h, name: name, subscribers: make(map[*Subscriber]struct{})}
+	h.streams[name] = pub
+	return pub, nil
+}
+
+// Subscribe : 캐시된 메타데이터 + sequence header + 마지막 GOP 부터 받음
+func (h *PubSubHub) Subscribe(app, streamKey string) (*Subscriber, error) {
+	h.mu.Lock()
+	pub, ok := h.streams[streamName(app, streamKey)]
+	h.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrStreamNotFound, streamName(app, streamKey))
+	}
+	return pub.subscribe(h.bufferSize())
+}
+
+func (h *PubSubHub) bufferSize() int {
+	if h.BufferSize <= 0 {
+		return defaultBufferSize
+	}
+	return h.BufferSize
+}
+
+// Publication : publish 하나, sink 구현이라 Handler 출력(녹화, HLS ...) 과 같이 씀
+type Publication struct {
+	hub  *PubSubHub
+	name string
+
+	mu          sync.Mutex
+	cache       GOPCache
+	subscribers map[*Subscriber]struct{}
+	closed      bool
+}
+
+// Write : publisher goroutine 에서 호출, 구독자 버퍼에 넣기만 하고 절대 block 되지 않음
+func (p *Publication) Write(packet *Packet) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.cache.Add(packet)
+	for s := range p.subscribers {
+		s.offer(packet)
+	}
+	return nil
+}
+
+// Close : 목록에서 빼고 구독자 종료 (구독자 queue 는 여기서만 닫음)
+func (p *Publication) Close() error {
+	p.hub.mu.Lock()
+	if p.hub.streams[p.name] == p {
+		delete(p.hub.streams, p.name)
+	}
+	p.hub.mu.Unlock()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	for s := range p.subscribers {
+		close(s.queue)
+	}
+	p.subscribers = nil
+	return nil
+}
+
+func (p *Publication) subscribe(bufferSize int) (*Subscriber, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, fmt.Errorf("%w: %s", ErrStreamNotFound, p.name)
+	}
+
+	// 처음 보낼 패킷은 버퍼 크기와 상관없이 다 들어가게
+	replay := p.cache.Replay()
+	s := &Subscriber{
+		publication: p,
+		queue:       make(chan *Packet, len(replay)+bufferSize),
+		stop:        make(chan struct{}),
+	}
+	for _, packet := range replay {
+		s.queue <- packet
+	}
+	p.subscribers[s] = struct{}{}
+	return s, nil
+}
+
+func (p *Publication) unsubscribe(s *Subscriber) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.subscribers, s)
+}
+
+// Subscribers : 현재 구독자 수
+func (p *Publication) Subscribers() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.subscribers)
+}
+
+// Subscriber : play 하나, 느린 구독자는 자기 패킷만 버림
+type Subscriber struct {
+	publication *Publication
+
+	queue    chan *Packet
+	stop     chan struct{}
+	stopOnce sync.Once
+	dropped  atomic.Uint64
+
+	resync bool // 버린 적 있으면 다음 keyframe 까지 영상 안 보냄 (Publication.mu)
+}
+
+// offer : Publication.mu 를 잡은 상태에서 호출
+func (s *Subscriber) offer(p *Packet) {
+	if s.resync && p.Type == PacketVideo && !p.IsKeyframe() && !p.IsSequenceHeader() {
+		s.dropped.Add(1)
+		return
+	}
+
+	select {
+	case s.queue <- p:
+		if p.IsKeyframe() {
+			s.resync = false
+		}
+	default:
+		s.dropped.Add(1)
+		s.resync = true
+	}
+}
+
+// Run : publish 가 끝나면 nil, write 가 실패하면 에러 (Close 로도 멈춤)
+func (s *Subscriber) Run(write func(p *Packet) error) error {
+	for {
+		select {
+		case <-s.stop:
+			return nil
+		case p, ok := <-s.queue:
+			if !ok {
+				return nil
+			}
+			if err := write(p); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Dropped : 버퍼가 꽉 차서 (또는 keyframe 대기중에) 버린 패킷 수
+func (s *Subscriber) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+func (s *Subscriber) Close() error {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+		s.publication.unsubscribe(s)
+	})
+	return nil
+}
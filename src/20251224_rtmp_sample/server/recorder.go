@@ -0,0 +1,299 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/yutopp/go-flv"
+
+	"example/stt/ffmpeg"
+)
+
+const (
+	defaultNameTemplate = "{stream}_{date}_{time}"
+	defaultSyncInterval = 5 // 단위: second
+	indexSuffix         = ".index.json"
+)
+
+// RecorderConfig : FLV 녹화 설정
+type RecorderConfig struct {
+	Dir          string `json:"dir"`           // 비어있으면 os.TempDir()
+	NameTemplate string `json:"name_template"` // {app} {stream} {date} {time} {seq}, "/" 로 하위 폴더 가능 (확장자 제외)
+	MaxSizeMB    int    `json:"max_size_mb"`   // 넘으면 다음 keyframe 에서 새 파일, 0 이면 안 자름
+	MaxDuration  int    `json:"max_duration"`  // 단위: second, 0 이면 안 자름
+	SyncInterval int    `json:"sync_interval"` // 이 간격으로 flush + fsync + 인덱스 저장 (죽어도 이만큼만 잃음, 단위: second)
+	RemuxMP4     bool   `json:"remux_mp4"`     // 스트림 끝나면 ffmpeg 로 파일마다 mp4 (-c copy) 생성
+}
+
+// KeyframeOffset : seek 용 (time_ms 는 파일 기준)
+type KeyframeOffset struct {
+	TimeMs uint32 `json:"time_ms"`
+	Offset int64  `json:"offset"`
+}
+
+// RecordingIndex : 녹화 파일 옆의 <파일>.index.json
+type RecordingIndex struct {
+	File       string           `json:"file"`
+	App        string           `json:"app"`
+	StreamKey  string           `json:"stream_key"`
+	StartedAt  time.Time        `json:"started_at"`
+	DurationMs uint32           `json:"duration_ms"`
+	Size       int64            `json:"size"`
+	Complete   bool             `json:"complete"` // false 면 녹화 중이거나 비정상 종료
+	Keyframes  []KeyframeOffset `json:"keyframes"`
+}
+
+// countingWriter : 태그 시작 offset 계산용
+type countingWriter struct {
+	w *bufio.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Recorder : publish 하나를 FLV 파일(들)로 녹화, 크기/시간 제한을 넘으면 keyframe 에서 새 파일로 넘어감
+// 새 파일은 메타데이터 + sequence header 부터 시작하고 timestamp 도 0 부터 다시 셈
+type Recorder struct {
+	Config    RecorderConfig
+	App       string
+	StreamKey string
+
+	headers  GOPCache // 메타데이터, sequence header 만 넣음
+	hasVideo bool
+
+	file    *os.File
+	buf     *bufio.Writer
+	counter *countingWriter
+	enc     *flv.Encoder
+	index   RecordingIndex
+	base    uint32 // 현재 파일의 첫 timestamp
+	synced  time.Time
+
+	seq   int
+	files []string
+}
+
+func NewRecorder(config RecorderConfig, app, streamKey string) (*Recorder, error) {
+	if config.Dir == "" {
+		config.Dir = os.TempDir()
+	}
+	if config.NameTemplate == "" {
+		config.NameTemplate = defaultNameTemplate
+	}
+	if config.SyncInterval <= 0 {
+		config.SyncInterval = defaultSyncInterval
+	}
+	if err := os.MkdirAll(config.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("create record dir failed: %w", err)
+	}
+
+	return &Recorder{Config: config, App: app, StreamKey: streamKey}, nil
+}
+
+// Files : 지금까지 만든 파일 경로 (녹화 순서)
+func (r *Recorder) Files() []string {
+	return r.files
+}
+
+// Write : sink 구현 (Destination type "flv" 에서도 사용)
+func (r *Recorder) Write(p *Packet) error {
+	if p.Type == PacketVideo {
+		r.hasVideo = true
+	}
+	if p.Type == PacketData || p.IsSequenceHeader() {
+		r.headers.Add(p)
+		if r.file == nil {
+			return nil // 첫 파일은 첫 미디어 패킷에서 열고 헤더를 같이 씀
+		}
+		return r.writeTag(p)
+	}
+
+	// 영상이 있으면 keyframe 에서만 자름
+	if r.file == nil || ((p.IsKeyframe() || !r.hasVideo) && r.full(p.Timestamp)) {
+		if err := r.rotate(p.Timestamp); err != nil {
+			return err
+		}
+	}
+
+	if p.IsKeyframe() {
+		r.index.Keyframes = append(r.index.Keyframes, KeyframeOffset{TimeMs: r.relative(p.Timestamp), Offset: r.counter.n})
+	}
+	if err := r.writeTag(p); err != nil {
+		return err
+	}
+
+	if time.Since(r.synced) >= time.Duration(r.Config.SyncInterval)*time.Second {
+		return r.sync(false)
+	}
+	return nil
+}
+
+func (r *Recorder) full(ts uint32) bool {
+	if r.file == nil {
+		return true
+	}
+	if r.Config.MaxSizeMB > 0 && r.counter.n >= int64(r.Config.MaxSizeMB)*1024*1024 {
+		return true
+	}
+	return r.Config.MaxDuration > 0 && r.relative(ts) >= uint32(r.Config.MaxDuration)*1000
+}
+
+func (r *Recorder) relative(ts uint32) uint32 {
+	if ts < r.base {
+		return 0 // 오디오가 keyframe 보다 살짝 앞서는 경우
+	}
+	return ts - r.base
+}
+
+func (r *Recorder) writeTag(p *Packet) error {
+	tag, err := p.FlvTag()
+	if err != nil {
+		return nil // 깨진 태그는 건너뜀
+	}
+	if p.Type == PacketData || p.IsSequenceHeader() {
+		tag.Timestamp = 0
+	} else {
+		tag.Timestamp = r.relative(p.Timestamp)
+		r.index.DurationMs = max(r.index.DurationMs, tag.Timestamp)
+	}
+	return r.enc.Encode(tag)
+}
+
+// rotate : 현재 파일을 닫고 새 파일 + 헤더
+func (r *Recorder) rotate(ts uint32) error {
+	if err := r.closeFile(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	f, path, err := r.create(now)
+	if err != nil {
+		return err
+	}
+
+	r.file = f
+	r.buf = bufio.NewWriter(f)
+	r.counter = &countingWriter{w: r.buf}
+	r.base = ts
+	r.synced = now
+	r.seq++
+	r.files = append(r.files, path)
+	r.index = RecordingIndex{File: filepath.Base(path), App: r.App, StreamKey: r.StreamKey, StartedAt: now, Keyframes: make([]KeyframeOffset, 0)}
+
+	if r.enc, err = flv.NewEncoder(r.counter, flv.FlagsAudio|flv.FlagsVideo); err != nil {
+		return fmt.Errorf("failed to create flv encoder : %w", err)
+	}
+	for _, h := range r.headers.Headers() {
+		if err := r.writeTag(h); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("녹화 파일 시작: %s", path)
+	return nil
+}
+
+// create : 같은 이름이 있으면 덮어쓰지 않고 _1, _2 ... 를 붙임 (재 publish, 같은 키 동시 publish)
+func (r *Recorder) create(now time.Time) (*os.File, string, error) {
+	name := strings.NewReplacer(
+		"{app}", r.App,
+		"{stream}", r.StreamKey,
+		"{date}", now.Format("20060102"),
+		"{time}", now.Format("150405"),
+		"{seq}", fmt.Sprintf("%03d", r.seq),
+	).Replace(r.Config.NameTemplate)
+	base := filepath.Join(r.Config.Dir, filepath.Clean(filepath.Join("/", name)))
+
+	if err := os.MkdirAll(filepath.Dir(base), 0755); err != nil {
+		return nil, "", fmt.Errorf("create record dir failed: %w", err)
+	}
+
+	for i := 0; ; i++ {
+		path := base + ".flv"
+		if i > 0 {
+			path = fmt.Sprintf("%s_%d.flv", base, i)
+		}
+
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create flv file : %w", err)
+		}
+		return f, path, nil
+	}
+}
+
+// sync : flush + fsync + 인덱스 저장
+func (r *Recorder) sync(complete bool) error {
+	if err := r.buf.Flush(); err != nil {
+		return err
+	}
+	if err := r.file.Sync(); err != nil {
+		return err
+	}
+	r.synced = time.Now()
+
+	r.index.Size = r.counter.n
+	r.index.Complete = complete
+	data, err := json.MarshalIndent(r.index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(r.file.Name()+indexSuffix, data)
+}
+
+func (r *Recorder) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.sync(true)
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	r.file, r.buf, r.counter, r.enc = nil, nil, nil, nil
+	return err
+}
+
+// Close : 마지막 파일 마무리, RemuxMP4 면 백그라운드에서 mp4 생성
+func (r *Recorder) Close() error {
+	err := r.closeFile()
+
+	if r.Config.RemuxMP4 && len(r.files) > 0 {
+		go remuxAll(append([]string(nil), r.files...))
+	}
+	return err
+}
+
+// remuxAll : 임시 파일에 쓰고 끝나면 rename (변환 중에 서버가 내려가도 잘린 mp4 가 남지 않음, .tmp 만 남음)
+func remuxAll(files []string) {
+	for _, path := range files {
+		out := strings.TrimSuffix(path, ".flv") + ".mp4"
+		tmp := out + ".tmp"
+		if err := ffmpeg.RemuxMP4(context.Background(), path, tmp); err != nil {
+			os.Remove(tmp)
+			log.Warnf("mp4 변환 실패: %s %v", path, err)
+			continue
+		}
+		if err := os.Rename(tmp, out); err != nil {
+			os.Remove(tmp)
+			log.Warnf("mp4 변환 실패: %s %v", path, err)
+			continue
+		}
+		log.Infof("mp4 변환 완료: %s", out)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
 	rtmpmsg "github.com/yutopp/go-rtmp/message"
@@ -14,16 +15,42 @@ import (
 var _ rtmp.Handler = (*RelayHandler)(nil)
 
 type Config struct {
-	WowzaHost string `json:"wowza_host"`
+	WowzaHost    string              `json:"wowza_host"`   // destinations, routes 가 없을때 기본 목적지
+	Destinations []DestinationConfig `json:"destinations"` // 모든 스트림 공통
+	Routes       []RouteConfig       `json:"routes"`       // app / stream key 별 추가 목적지
+	Auth         AuthConfig          `json:"auth"`
+	Sessions     SessionConfig       `json:"sessions"`
 }
 
 var WowzaConfig *Config
 
+// DestinationsFor : 공통 목적지 + 매칭되는 route 목적지 (아무것도 없으면 WowzaHost 하나)
+func (c *Config) DestinationsFor(app, streamKey string) []DestinationConfig {
+	destinations := make([]DestinationConfig, 0, len(c.Destinations))
+	destinations = append(destinations, c.Destinations...)
+	for _, route := range c.Routes {
+		if route.Match(app, streamKey) {
+			destinations = append(destinations, route.Destinations...)
+		}
+	}
+
+	if len(destinations) == 0 && c.WowzaHost != "" {
+		destinations = append(destinations, DestinationConfig{Name: "wowza", Host: c.WowzaHost})
+	}
+	return destinations
+}
+
+// RelayHandler : publish 된 스트림을 설정된 목적지 N 개로 동시에 재전송
 type RelayHandler struct {
 	rtmp.DefaultHandler
-	wowzaConn   *rtmp.ClientConn
-	wowzaStream *rtmp.Stream
-	wowzaApp    string
+	PublishGuard
+	Session *Session // nil 이면 세션 집계 안함
+
+	app       string
+	streamKey string
+
+	mu           sync.Mutex // destinations 는 세션 API 에서도 읽음
+	destinations []*Destination
 }
 
 func (v *RelayHandler) OnConnect(timestamp uint32, cmd *rtmpmsg.NetConnectionConnect) error {
@@ -32,59 +59,43 @@ func (v *RelayHandler) OnConnect(timestamp uint32, cmd *rtmpmsg.NetConnectionCon
 		return errors.New("app name is empty")
 	}
 
-	v.wowzaApp = appName
+	v.app = appName
 
-	return nil
+	return v.authorizeConnect(appName)
 }
 
 // OnPublish : stream start
+// 목적지 연결은 각자 goroutine 에서 하니까 일부가 실패해도 publish 는 받음
 func (v *RelayHandler) OnPublish(ctx *rtmp.StreamContext, timestamp uint32, cmd *rtmpmsg.NetStreamPublish) error {
-	log.Printf("stream start : %s", cmd.PublishingName)
-
-	streamKey := cmd.PublishingName
-
-	logger := log.StandardLogger()
-	logger.SetLevel(log.DebugLevel)
-	wowzaConn, err := rtmp.Dial("rtmp", WowzaConfig.WowzaHost, &rtmp.ConnConfig{
-		Logger: logger,
-	})
+	streamKey, err := v.authorizePublish(cmd.PublishingName)
 	if err != nil {
-		log.Printf("Wowza 연결 실패: %v", err)
+		log.Warnf("publish 거부 : %s/%s (%s) %v", v.app, cmd.PublishingName, v.RemoteAddr, err)
 		return err
 	}
-	v.wowzaConn = wowzaConn
+	log.Printf("stream start : %s", streamKey)
 
-	connectCmd := &rtmpmsg.NetConnectionConnect{
-		Command: rtmpmsg.NetConnectionConnectCommand{
-			App: v.wowzaApp,
-		},
-	}
-	if err := wowzaConn.Connect(connectCmd); err != nil {
-		log.Printf("Wowza Connect 실패: %v", err)
-		return err
-	}
+	v.streamKey = streamKey
 
-	createStreamBody := &rtmpmsg.NetConnectionCreateStream{}
-	chunkSize := uint32(128)
-
-	stream, err := wowzaConn.CreateStream(createStreamBody, chunkSize)
-	if err != nil {
-		log.Printf("Wowza 스트림 생성 실패: %v", err)
-		return err
+	configs := WowzaConfig.DestinationsFor(v.app, v.streamKey)
+	if len(configs) == 0 {
+		return errors.New("no relay destination")
 	}
-	v.wowzaStream = stream
 
-	publishBody := &rtmpmsg.NetStreamPublish{
-		CommandObject:  nil,
-		PublishingName: streamKey,
-		PublishingType: "live", // 이거 뺐더니 EOF 나버림...
-	}
-	if err := stream.Publish(publishBody); err != nil {
-		log.Printf("Wowza publish 실패: %v", err)
-		return err
+	v.mu.Lock()
+	for _, config := range configs {
+		d := NewDestination(config, v.app, v.streamKey)
+		d.Start()
+		v.destinations = append(v.destinations, d)
 	}
+	v.mu.Unlock()
+	v.Session.Publish(v.app, v.streamKey)
 
-	log.Printf("✅ Wowza로 포워딩 시작: %s/%s", v.wowzaApp, streamKey)
+	log.Printf("✅ 포워딩 시작: %s/%s -> %d destinations", v.app, v.streamKey, len(v.destinations))
+	return nil
+}
+
+func (v *RelayHandler) OnSetDataFrame(timestamp uint32, data *rtmpmsg.NetStreamSetDataFrame) error {
+	v.broadcast(&Packet{Type: PacketData, Timestamp: timestamp, Payload: data.Payload})
 	return nil
 }
 
@@ -94,16 +105,9 @@ func (v *RelayHandler) OnAudio(timestamp uint32, payload io.Reader) error {
 		return err
 	}
 
-	if v.wowzaStream != nil {
-		// Audio 메시지 생성
-		audioMsg := &rtmpmsg.AudioMessage{
-			Payload: buf, // bytes.Buffer는 io.Reader 구현
-		}
-
-		if err := v.wowzaStream.Write(4, timestamp, audioMsg); err != nil {
-			log.Printf("Wowza 오디오 전송 실패: %v", err)
-		}
-	}
+	p := &Packet{Type: PacketAudio, Timestamp: timestamp, Payload: buf.Bytes()}
+	v.Session.Observe(p)
+	v.broadcast(p)
 	return nil
 }
 
@@ -113,27 +117,40 @@ func (v *RelayHandler) OnVideo(timestamp uint32, payload io.Reader) error {
 		return err
 	}
 
-	if v.wowzaStream != nil {
-		// Video 메시지 생성
-		videoMsg := &rtmpmsg.VideoMessage{
-			Payload: buf, // bytes.Buffer는 io.Reader 구현
-		}
+	p := &Packet{Type: PacketVideo, Timestamp: timestamp, Payload: buf.Bytes()}
+	v.Session.Observe(p)
+	v.broadcast(p)
+	return nil
+}
 
-		if err := v.wowzaStream.Write(6, timestamp, videoMsg); err != nil {
-			log.Printf("Wowza 비디오 전송 실패: %v", err)
-		}
+// broadcast : 목적지별 버퍼에 넣기만 함 (느린 목적지는 자기 패킷만 버림)
+// destinations 는 이 goroutine 에서만 바뀌니까 lock 없이 읽음
+func (v *RelayHandler) broadcast(p *Packet) {
+	for _, d := range v.destinations {
+		d.Send(p)
 	}
+}
 
-	return nil
+// Statuses : 목적지별 상태
+func (v *RelayHandler) Statuses() []DestinationStatus {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	statuses := make([]DestinationStatus, 0, len(v.destinations))
+	for _, d := range v.destinations {
+		statuses = append(statuses, d.Status())
+	}
+	return statuses
 }
 
 func (v *RelayHandler) OnClose() {
-	log.Println("연결 종료 - Wowza 연결 정리")
+	log.Println("연결 종료 - 목적지 연결 정리")
+	v.release()
 
-	if v.wowzaStream != nil {
-		v.wowzaStream.Close()
-	}
-	if v.wowzaConn != nil {
-		v.wowzaConn.Close()
+	for _, d := range v.destinations {
+		d.Close()
+		log.Printf("목적지 종료: %+v", d.Status())
 	}
+
+	v.Session.Close() // session.end 에 목적지 최종 상태 포함
 }
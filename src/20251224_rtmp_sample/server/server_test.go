@@ -0,0 +1,518 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yutopp/go-flv"
+	flvtag "github.com/yutopp/go-flv/tag"
+)
+
+func TestDestinationsFor(t *testing.T) {
+	config := &Config{
+		WowzaHost:    "wowza:1935",
+		Destinations: []DestinationConfig{{Name: "recorder", Type: DestinationTypeFLV}},
+		Routes: []RouteConfig{
+			{App: "live", Destinations: []DestinationConfig{{Name: "backup", Host: "backup:1935"}}},
+			{App: "live", StreamKey: "vip", Destinations: []DestinationConfig{{Name: "vip", Host: "vip:1935"}}},
+		},
+	}
+
+	names := func(configs []DestinationConfig) []string {
+		result := make([]string, 0)
+		for _, c := range configs {
+			result = append(result, c.Name)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"recorder", "backup"}, names(config.DestinationsFor("live", "test")))
+	assert.Equal(t, []string{"recorder", "backup", "vip"}, names(config.DestinationsFor("live", "vip")))
+	assert.Equal(t, []string{"recorder"}, names(config.DestinationsFor("other", "vip")))
+
+	// 목적지 설정이 없으면 기존처럼 wowza 하나
+	assert.Equal(t, []string{"wowza"}, names((&Config{WowzaHost: "wowza:1935"}).DestinationsFor("live", "test")))
+}
+
+func TestDestinationSendNeverBlocks(t *testing.T) {
+	d := NewDestination(DestinationConfig{Name: "slow", Host: "127.0.0.1:1", BufferSize: 2}, "live", "test")
+
+	// Start 안했으니 아무도 안 꺼내감 -> 버퍼 넘치면 버림
+	for i := 0; i < 5; i++ {
+		d.Send(&Packet{Type: PacketVideo, Timestamp: uint32(i)})
+	}
+	status := d.Status()
+	assert.Equal(t, uint64(3), status.Dropped)
+	assert.Equal(t, "rtmp://127.0.0.1:1/live/test", status.Target)
+}
+
+// Close 해도 버퍼에 남은 패킷은 다 씀 (로컬 녹화 끝부분이 잘리지 않게)
+func TestDestinationDrainOnClose(t *testing.T) {
+	dir := t.TempDir()
+	d := NewDestination(DestinationConfig{Name: "local", Type: DestinationTypeFLV, Dir: dir, BufferSize: 100}, "live", "test")
+
+	const n = 50
+	assert.True(t, d.Send(&Packet{Type: PacketVideo, Payload: []byte{0x17, 0x00, 0, 0, 0, 0x01}}))
+	for i := 1; i < n; i++ {
+		assert.True(t, d.Send(&Packet{Type: PacketVideo, Timestamp: uint32(i * 33), Payload: []byte{0x27, 0x01, 0, 0, 0, 0xAA}}))
+	}
+	d.Start()
+	d.Close()
+	assert.Equal(t, uint64(n), d.Status().Sent)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.flv"))
+	assert.NoError(t, err)
+	if !assert.Len(t, files, 1) {
+		return
+	}
+	f, err := os.Open(files[0])
+	assert.NoError(t, err)
+	defer f.Close()
+	dec, err := flv.NewDecoder(f)
+	assert.NoError(t, err)
+	tags := 0
+	for {
+		var tag flvtag.FlvTag
+		if err := dec.Decode(&tag); err != nil {
+			break
+		}
+		tags++
+		tag.Close()
+	}
+	assert.Equal(t, n, tags)
+}
+
+func TestGOPCache(t *testing.T) {
+	video := func(frameType byte, avcPacketType byte, ts uint32) *Packet {
+		return &Packet{Type: PacketVideo, Timestamp: ts, Payload: []byte{frameType<<4 | codecIDAVC, avcPacketType, 0, 0, 0}}
+	}
+	audio := func(aacPacketType byte, ts uint32) *Packet {
+		return &Packet{Type: PacketAudio, Timestamp: ts, Payload: []byte{soundFormatAAC<<4 | 0x0f, aacPacketType}}
+	}
+
+	metadata := &Packet{Type: PacketData}
+	videoHeader, audioHeader := video(1, 0, 0), audio(0, 0)
+
+	cache := GOPCache{}
+	for _, p := range []*Packet{
+		metadata, videoHeader, audioHeader,
+		audio(1, 10), // keyframe 전이라 버림
+		video(1, 1, 20), video(2, 1, 30), audio(1, 40),
+	} {
+		cache.Add(p)
+	}
+	key := video(1, 1, 50)
+	inter := video(2, 1, 60)
+	cache.Add(key)
+	cache.Add(inter)
+
+	assert.True(t, videoHeader.IsSequenceHeader())
+	assert.False(t, videoHeader.IsKeyframe())
+	assert.True(t, key.IsKeyframe())
+	assert.Equal(t, []*Packet{metadata, videoHeader, audioHeader, key, inter}, cache.Replay())
+}
+
+func TestDestinationBackoff(t *testing.T) {
+	d := NewDestination(DestinationConfig{ReconnectInterval: 1, MaxReconnectInterval: 10}, "live", "test")
+	assert.Equal(t, time.Second, d.backoff(1))
+	assert.Equal(t, 4*time.Second, d.backoff(3))
+	assert.Equal(t, 10*time.Second, d.backoff(10))
+}
+
+func TestAuthorizers(t *testing.T) {
+	ctx := context.Background()
+
+	static := &StaticAuthorizer{Apps: map[string][]string{"live": {"key1"}, "test": {"*"}}}
+	assert.NoError(t, static.AuthorizePublish(ctx, PublishRequest{App: "live", StreamKey: "key1"}))
+	assert.ErrorIs(t, static.AuthorizePublish(ctx, PublishRequest{App: "live", StreamKey: "key2"}), ErrUnauthorized)
+	assert.NoError(t, static.AuthorizePublish(ctx, PublishRequest{App: "test", StreamKey: "anything"}))
+	assert.ErrorIs(t, static.AuthorizeConnect(ctx, PublishRequest{App: "other"}), ErrUnauthorized)
+
+	// webhook : 로컬 테스트 서버가 대신함
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := PublishRequest{}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.StreamKey != "allowed" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer webhookServer.Close()
+
+	webhook := &WebhookAuthorizer{URL: webhookServer.URL, Apps: []string{"live"}}
+	assert.NoError(t, webhook.AuthorizePublish(ctx, PublishRequest{App: "live", StreamKey: "allowed"}))
+	assert.ErrorIs(t, webhook.AuthorizePublish(ctx, PublishRequest{App: "live", StreamKey: "denied"}), ErrUnauthorized)
+	assert.ErrorIs(t, webhook.AuthorizeConnect(ctx, PublishRequest{App: "vod"}), ErrUnauthorized)
+
+	// token
+	secret := []byte("secret")
+	now := time.Unix(1_700_000_000, 0)
+	token := &TokenAuthorizer{Secret: secret, now: func() time.Time { return now }}
+	authorize := func(app, publishingName string) error {
+		key, query := ParsePublishingName(publishingName)
+		return token.AuthorizePublish(ctx, PublishRequest{App: app, StreamKey: key, Query: query})
+	}
+
+	signed := SignStreamKey(secret, "live", "test", now.Add(time.Hour))
+	assert.NoError(t, authorize("live", signed))
+	assert.ErrorIs(t, authorize("vod", signed), ErrUnauthorized) // 다른 app
+	assert.ErrorIs(t, authorize("live", strings.Replace(signed, "test", "other", 1)), ErrUnauthorized)
+	assert.ErrorIs(t, authorize("live", SignStreamKey(secret, "live", "test", now.Add(-time.Second))), ErrUnauthorized)
+	assert.ErrorIs(t, authorize("live", "test"), ErrUnauthorized)
+}
+
+func TestPublishGuardLimit(t *testing.T) {
+	limit := &PublisherLimit{Max: 1}
+	first := &PublishGuard{Publishers: limit}
+	second := &PublishGuard{Publishers: limit}
+
+	key, err := first.authorizePublish("test?foo=bar")
+	assert.NoError(t, err)
+	assert.Equal(t, "test", key)
+
+	_, err = second.authorizePublish("test2")
+	assert.ErrorIs(t, err, ErrTooManyPublishers)
+
+	// 같은 연결에서 다시 publish 해도 슬롯을 더 잡지 않음
+	_, err = first.authorizePublish("test")
+	assert.ErrorIs(t, err, ErrAlreadyPublishing)
+	assert.Equal(t, 1, limit.Count())
+
+	first.release()
+	first.release() // 두번 불러도 한번만 반납
+	assert.Equal(t, 0, limit.Count())
+	_, err = second.authorizePublish("test2")
+	assert.NoError(t, err)
+}
+
+func TestHLSWriter(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewHLSWriter(HLSConfig{Dir: dir, TargetDuration: 2, WindowSize: 2}, "test")
+	assert.NoError(t, err)
+
+	sps := []byte{0x67, 0x42, 0xC0, 0x1E, 0xD9}
+	pps := []byte{0x68, 0xCE, 0x3C, 0x80}
+	record := append([]byte{0x01, 0x42, 0xC0, 0x1E, 0xFF, 0xE1, 0x00, byte(len(sps))}, sps...)
+	record = append(append(record, 0x01, 0x00, byte(len(pps))), pps...)
+
+	video := func(frameType, avcPacketType byte, ts uint32, body []byte) *Packet {
+		return &Packet{Type: PacketVideo, Timestamp: ts, Payload: append([]byte{frameType<<4 | codecIDAVC, avcPacketType, 0, 0, 0}, body...)}
+	}
+	audio := func(aacPacketType byte, ts uint32, body []byte) *Packet {
+		return &Packet{Type: PacketAudio, Timestamp: ts, Payload: append([]byte{soundFormatAAC<<4 | 0x0f, aacPacketType}, body...)}
+	}
+	// 4 byte length + IDR / non-IDR slice
+	idr := append([]byte{0, 0, 0, 200, 0x65}, make([]byte, 199)...)
+	slice := []byte{0, 0, 0, 4, 0x41, 0x9A, 0x00, 0x00}
+
+	assert.NoError(t, w.Write(video(1, 0, 0, record)))
+	assert.NoError(t, w.Write(audio(0, 0, []byte{0x12, 0x10}))) // AAC-LC 44.1kHz stereo
+	for ts := uint32(0); ts <= 7000; ts += 100 {
+		frameType := byte(2)
+		body := slice
+		if ts%2000 == 0 {
+			frameType, body = 1, idr
+		}
+		assert.NoError(t, w.Write(video(frameType, 1, ts, body)))
+		assert.NoError(t, w.Write(audio(1, ts, []byte{0x21, 0x00, 0x49})))
+	}
+
+	// 진행중: 닫힌 세그먼트 3개 중 마지막 2개만
+	live, err := os.ReadFile(filepath.Join(dir, "test", hlsLivePlaylist))
+	assert.NoError(t, err)
+	assert.Contains(t, string(live), "#EXT-X-MEDIA-SEQUENCE:1\n")
+	assert.Contains(t, string(live), "#EXTINF:2.000,\nsegment_00002.ts\n")
+	assert.NotContains(t, string(live), "segment_00000.ts")
+	assert.NotContains(t, string(live), "#EXT-X-ENDLIST")
+
+	assert.NoError(t, w.Close())
+
+	live, _ = os.ReadFile(filepath.Join(dir, "test", hlsLivePlaylist))
+	assert.Contains(t, string(live), "segment_00003.ts\n#EXT-X-ENDLIST\n")
+	vod, err := os.ReadFile(filepath.Join(dir, "test", hlsVODPlaylist))
+	assert.NoError(t, err)
+	assert.Contains(t, string(vod), "#EXT-X-PLAYLIST-TYPE:VOD\n")
+	assert.Equal(t, 4, strings.Count(string(vod), "#EXTINF:"))
+
+	segment, err := os.ReadFile(filepath.Join(dir, "test", "segment_00001.ts"))
+	assert.NoError(t, err)
+	assert.Zero(t, len(segment)%tsPacketSize)
+	for i := 0; i < len(segment); i += tsPacketSize {
+		assert.Equal(t, byte(0x47), segment[i])
+	}
+
+	// 첫 패킷 PAT: section + CRC 전체의 CRC 는 0
+	sectionLength := int(segment[6]&0x0F)<<8 | int(segment[7])
+	assert.Zero(t, crc32MPEG(segment[5:8+sectionLength]))
+
+	// 긴 세그먼트가 window 에서 빠져도 TARGETDURATION 은 그대로
+	w, err = NewHLSWriter(HLSConfig{Dir: dir, TargetDuration: 2, WindowSize: 1}, "long")
+	assert.NoError(t, err)
+	assert.NoError(t, w.Write(video(1, 0, 0, record)))
+	for _, ts := range []uint32{0, 5000, 7000, 9000} {
+		assert.NoError(t, w.Write(video(1, 1, ts, idr)))
+	}
+	live, _ = os.ReadFile(filepath.Join(dir, "long", hlsLivePlaylist))
+	assert.Contains(t, string(live), "#EXT-X-TARGETDURATION:5\n")
+	assert.Contains(t, string(live), "#EXTINF:2.000,\nsegment_00002.ts\n")
+	assert.NotContains(t, string(live), "segment_00000.ts")
+	assert.NoError(t, w.Close())
+
+	// objectType 0 은 ADTS 로 못 씀
+	_, err = parseAACConfig([]byte{0x02, 0x10})
+	assert.Error(t, err)
+}
+
+func TestRecorderRotation(t *testing.T) {
+	dir := t.TempDir()
+	config := RecorderConfig{Dir: dir, NameTemplate: "{app}/{stream}_{seq}", MaxDuration: 2}
+
+	record := func() *Recorder {
+		r, err := NewRecorder(config, "live", "test")
+		assert.NoError(t, err)
+
+		assert.NoError(t, r.Write(&Packet{Type: PacketVideo, Payload: []byte{0x17, 0x00, 0, 0, 0, 0x01}}))
+		for ts := uint32(0); ts <= 5000; ts += 500 {
+			frameType := byte(2)
+			if ts%1000 == 0 {
+				frameType = 1
+			}
+			assert.NoError(t, r.Write(&Packet{Type: PacketVideo, Timestamp: ts, Payload: []byte{frameType<<4 | codecIDAVC, 0x01, 0, 0, 0, 0xAA}}))
+		}
+		assert.NoError(t, r.Close())
+		return r
+	}
+
+	r := record()
+	assert.Equal(t, []string{
+		filepath.Join(dir, "live", "test_000.flv"),
+		filepath.Join(dir, "live", "test_001.flv"),
+		filepath.Join(dir, "live", "test_002.flv"),
+	}, r.Files())
+
+	// 두번째 파일 : 헤더부터, timestamp 는 0 부터
+	f, err := os.Open(r.Files()[1])
+	assert.NoError(t, err)
+	defer f.Close()
+	dec, err := flv.NewDecoder(f)
+	assert.NoError(t, err)
+	timestamps := make([]uint32, 0)
+	for {
+		var tag flvtag.FlvTag
+		if err := dec.Decode(&tag); err != nil {
+			break
+		}
+		timestamps = append(timestamps, tag.Timestamp)
+		if len(timestamps) == 1 {
+			assert.Equal(t, flvtag.AVCPacketTypeSequenceHeader, tag.Data.(*flvtag.VideoData).AVCPacketType)
+		}
+		tag.Close() // body 를 읽어야 다음 태그로 감
+	}
+	assert.Equal(t, []uint32{0, 0, 500, 1000, 1500}, timestamps)
+
+	var index RecordingIndex
+	data, err := os.ReadFile(r.Files()[1] + indexSuffix)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, &index))
+	assert.True(t, index.Complete)
+	assert.Equal(t, uint32(1500), index.DurationMs)
+	assert.Len(t, index.Keyframes, 2)
+
+	raw, _ := os.ReadFile(r.Files()[1])
+	assert.Equal(t, int64(len(raw)), index.Size)
+	for _, k := range index.Keyframes {
+		assert.Equal(t, byte(9), raw[k.Offset])       // video tag
+		assert.Equal(t, byte(0x17), raw[k.Offset+11]) // keyframe + AVC
+	}
+
+	// 같은 이름으로 다시 녹화해도 덮어쓰지 않음
+	again := record()
+	assert.Equal(t, filepath.Join(dir, "live", "test_000_1.flv"), again.Files()[0])
+}
+
+func TestSessionRegistry(t *testing.T) {
+	events := make(chan string, 2)
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Event   string      `json:"event"`
+			Session SessionInfo `json:"session"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		events <- body.Event + " " + body.Session.StreamKey
+	}))
+	defer webhookServer.Close()
+
+	registry := NewSessionRegistry(SessionConfig{WebhookURL: webhookServer.URL, Token: "secret"})
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	session := registry.Open(serverConn)
+	go clientConn.Write([]byte("hello"))
+	n, err := session.Conn().Read(make([]byte, 16))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+
+	session.Publish("live", "test")
+	assert.Equal(t, EventSessionStart+" test", <-events)
+
+	// 30fps 영상 + AAC, 2초 구간
+	session.Observe(&Packet{Type: PacketVideo, Payload: []byte{0x17, 0x00, 0, 0, 0}})
+	for ts := uint32(0); ts <= 2000; ts += 1000 / 30 {
+		session.Observe(&Packet{Type: PacketVideo, Timestamp: ts, Payload: make([]byte, 1000)})
+		session.Observe(&Packet{Type: PacketAudio, Timestamp: ts, Payload: []byte{0xAF, 0x01}})
+	}
+	session.Observe(&Packet{Type: PacketVideo, Timestamp: 2000, Payload: []byte{0x27, 0x01}})
+
+	info := session.Info()
+	assert.Equal(t, uint64(5), info.BytesIn)
+	assert.Equal(t, "h264", info.VideoCodec)
+	assert.Equal(t, "aac", info.AudioCodec)
+	assert.InDelta(t, 30, info.FPS, 1)
+	assert.InDelta(t, 30*1000*8, info.Bitrate, 30*1000*8/10)
+
+	mux := http.NewServeMux()
+	registry.Register(mux)
+	api := httptest.NewServer(mux)
+	defer api.Close()
+
+	// 토큰 없거나 틀리면 401
+	resp, err := http.Get(api.URL + "/sessions")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	req, _ := http.NewRequest(http.MethodDelete, api.URL+"/sessions/1", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Len(t, registry.List(), 1)
+
+	req, _ = http.NewRequest(http.MethodGet, api.URL+"/sessions", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	var list []SessionInfo
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	resp.Body.Close()
+	assert.Len(t, list, 1)
+	assert.Equal(t, "test", list[0].StreamKey)
+
+	req, _ = http.NewRequest(http.MethodGet, api.URL+"/sessions/999", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// kick : conn 이 닫히고, 정리는 OnClose 에서
+	req, _ = http.NewRequest(http.MethodDelete, api.URL+"/sessions/1", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_, err = clientConn.Read(make([]byte, 1))
+	assert.Error(t, err)
+
+	session.Close()
+	assert.Equal(t, EventSessionEnd+" test", <-events)
+	assert.Empty(t, registry.List())
+}
+
+func TestPubSubHub(t *testing.T) {
+	hub := NewPubSubHub()
+	hub.BufferSize = 2
+
+	_, err := hub.Subscribe("live", "test")
+	assert.ErrorIs(t, err, ErrStreamNotFound)
+
+	pub, err := hub.Publish("live", "test")
+	assert.NoError(t, err)
+	_, err = hub.Publish("live", "test")
+	assert.ErrorIs(t, err, ErrStreamBusy)
+
+	header := &Packet{Type: PacketVideo, Payload: []byte{0x17, 0x00}}
+	key := func(ts uint32) *Packet { return &Packet{Type: PacketVideo, Timestamp: ts, Payload: []byte{0x17, 0x01}} }
+	inter := func(ts uint32) *Packet { return &Packet{Type: PacketVideo, Timestamp: ts, Payload: []byte{0x27, 0x01}} }
+
+	for _, p := range []*Packet{header, key(0), inter(33), key(1000), inter(1033)} {
+		assert.NoError(t, pub.Write(p))
+	}
+
+	// 늦게 들어와도 헤더 + 마지막 GOP 부터
+	sub, err := hub.Subscribe("live", "test")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pub.Subscribers())
+
+	// 안 읽는 동안 버퍼(2)가 넘치면 다음 keyframe 까지 영상은 버림
+	for _, p := range []*Packet{inter(1066), inter(1100), inter(1133), inter(1166), key(2000)} {
+		assert.NoError(t, pub.Write(p))
+	}
+	assert.Equal(t, uint64(3), sub.Dropped())
+
+	received := make([]uint32, 0)
+	go func() {
+		for sub.Dropped() < 3 || len(sub.queue) > 0 {
+			time.Sleep(time.Millisecond)
+		}
+		pub.Write(key(3000)) // 버퍼가 비었으니 다시 받음
+		pub.Close()
+	}()
+	assert.NoError(t, sub.Run(func(p *Packet) error {
+		received = append(received, p.Timestamp)
+		return nil
+	}))
+	assert.Equal(t, []uint32{0, 1000, 1033, 1066, 1100, 3000}, received)
+
+	// publish 가 끝나면 다시 publish 가능
+	_, err = hub.Publish("live", "test")
+	assert.NoError(t, err)
+	assert.NoError(t, sub.Close())
+}
+
+func TestWriteLock(t *testing.T) {
+	local, remote := net.Pipe()
+	defer remote.Close()
+
+	lock := newWriteLock()
+	conn := lock.wrap(local)
+	stop := make(chan struct{})
+
+	// 연결 goroutine 이 읽기에서 기다리기 전까지는 못 씀 (OnPlay 뒤 Play.Start 응답 전)
+	written := make(chan struct{})
+	go lock.do(stop, func() error {
+		close(written)
+		return nil
+	})
+	select {
+	case <-written:
+		t.Fatal("should not write while connection goroutine holds the lock")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	read := make(chan error, 1)
+	go func() {
+		_, err := conn.Read(make([]byte, 1))
+		read <- err
+	}()
+	select {
+	case <-written:
+	case <-time.After(time.Second):
+		t.Fatal("should write while connection goroutine is blocked in read")
+	}
+
+	// stop 이 닫히면 기다리지 않고 나옴
+	close(stop)
+	assert.NoError(t, lock.do(stop, func() error { return nil }))
+
+	assert.NoError(t, lock.close())
+	assert.Error(t, <-read)
+}
@@ -0,0 +1,407 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	rateWindow     = 2000 // ms (스트림 timestamp 기준), fps / bitrate 계산 구간
+	webhookTimeout = 5 * time.Second
+	defaultAPIAddr = "127.0.0.1:8080" // 기본은 로컬에서만, 밖에서 보려면 http_addr 를 직접 지정
+
+	EventSessionStart = "session.start"
+	EventSessionEnd   = "session.end"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionConfig : 세션 API / 웹훅 설정
+type SessionConfig struct {
+	HTTPAddr   string `json:"http_addr"`   // 세션 API (server_main 은 자막도 같이), 비어있으면 "127.0.0.1:8080"
+	Token      string `json:"token"`       // /sessions 요청에 "Authorization: Bearer <token>" 필요, 비어있으면 검사 안 함
+	WebhookURL string `json:"webhook_url"` // session.start / session.end 를 POST, 비어있으면 안 보냄
+}
+
+// SessionInfo : API / 웹훅으로 나가는 세션 상태
+type SessionInfo struct {
+	ID           uint64              `json:"id"`
+	App          string              `json:"app"`
+	StreamKey    string              `json:"stream_key"`
+	RemoteAddr   string              `json:"remote_addr"`
+	StartedAt    time.Time           `json:"started_at"`
+	PublishedAt  time.Time           `json:"published_at,omitempty"`
+	BytesIn      uint64              `json:"bytes_in"`
+	BytesOut     uint64              `json:"bytes_out"`
+	AudioCodec   string              `json:"audio_codec,omitempty"`
+	VideoCodec   string              `json:"video_codec,omitempty"`
+	FPS          float64             `json:"fps"`
+	Bitrate      int64               `json:"bitrate"` // bps (audio + video payload)
+	Destinations []DestinationStatus `json:"destinations,omitempty"`
+}
+
+// Session : RTMP 연결 하나 (publish 안한 연결도 포함)
+// 카운터는 conn goroutine 에서 쓰고 API 에서 읽으니까 atomic / mutex 로 보호
+type Session struct {
+	ID         uint64
+	RemoteAddr string
+	StartedAt  time.Time
+
+	// Destinations : relay 목적지 상태 (RelayHandler 만 설정)
+	Destinations func() []DestinationStatus
+
+	registry *SessionRegistry
+	conn     net.Conn
+
+	bytesIn  atomic.Uint64
+	bytesOut atomic.Uint64
+
+	mu          sync.Mutex
+	app         string
+	streamKey   string
+	publishedAt time.Time
+	audioCodec  string
+	videoCodec  string
+
+	windowStart uint32
+	windowSet   bool
+	frames      int
+	payload     int
+	fps         float64
+	bitrate     int64
+}
+
+// countingConn : bytes in / out 집계, 세션 kick 할때 Close
+type countingConn struct {
+	net.Conn
+	session *Session
+}
+
+func (c *countingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.session.bytesIn.Add(uint64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.session.bytesOut.Add(uint64(n))
+	return n, err
+}
+
+// Conn : rtmp.ServerConfig.OnConnect 에서 돌려줄 conn (세션 카운터 집계용)
+func (s *Session) Conn() net.Conn {
+	return &countingConn{Conn: s.conn, session: s}
+}
+
+// Publish : publish 시작, session.start 웹훅
+func (s *Session) Publish(app, streamKey string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.app, s.streamKey, s.publishedAt = app, streamKey, time.Now()
+	s.mu.Unlock()
+
+	s.registry.notify(EventSessionStart, s.Info())
+}
+
+// Observe : 코덱 / fps / bitrate 추정 (OnAudio, OnVideo 에서 호출)
+func (s *Session) Observe(p *Packet) {
+	if s == nil || len(p.Payload) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch p.Type {
+	case PacketAudio:
+		s.audioCodec = soundFormatName(p.Payload[0] >> 4)
+	case PacketVideo:
+		s.videoCodec = videoCodecName(p.Payload[0] & 0x0f)
+		if !p.IsSequenceHeader() {
+			s.frames++
+		}
+	default:
+		return
+	}
+	s.payload += len(p.Payload)
+
+	if !s.windowSet || p.Timestamp < s.windowStart {
+		s.windowStart, s.windowSet = p.Timestamp, true
+		s.frames, s.payload = 0, 0
+		return
+	}
+	if elapsed := p.Timestamp - s.windowStart; elapsed >= rateWindow {
+		seconds := float64(elapsed) / 1000
+		s.fps = float64(s.frames) / seconds
+		s.bitrate = int64(float64(s.payload*8) / seconds)
+		s.windowStart, s.frames, s.payload = p.Timestamp, 0, 0
+	}
+}
+
+func (s *Session) Info() SessionInfo {
+	s.mu.Lock()
+	info := SessionInfo{
+		ID:          s.ID,
+		App:         s.app,
+		StreamKey:   s.streamKey,
+		RemoteAddr:  s.RemoteAddr,
+		StartedAt:   s.StartedAt,
+		PublishedAt: s.publishedAt,
+		AudioCodec:  s.audioCodec,
+		VideoCodec:  s.videoCodec,
+		FPS:         s.fps,
+		Bitrate:     s.bitrate,
+	}
+	s.mu.Unlock()
+
+	info.BytesIn = s.bytesIn.Load()
+	info.BytesOut = s.bytesOut.Load()
+	if s.Destinations != nil {
+		info.Destinations = s.Destinations()
+	}
+	return info
+}
+
+// Close : OnClose 에서 호출, 목록에서 빼고 publish 했던 세션이면 session.end 웹훅
+func (s *Session) Close() {
+	if s == nil {
+		return
+	}
+	s.registry.remove(s)
+}
+
+var soundFormatNames = map[byte]string{
+	0: "pcm", 1: "adpcm", 2: "mp3", 3: "pcm_le", 4: "nellymoser_16k", 5: "nellymoser_8k", 6: "nellymoser",
+	7: "g711_alaw", 8: "g711_mulaw", 10: "aac", 11: "speex", 14: "mp3_8k",
+}
+
+var videoCodecNames = map[byte]string{
+	2: "h263", 3: "screen", 4: "vp6", 5: "vp6_alpha", 6: "screen_v2", 7: "h264", 12: "h265",
+}
+
+func soundFormatName(format byte) string {
+	if name, ok := soundFormatNames[format]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", format)
+}
+
+func videoCodecName(codec byte) string {
+	if name, ok := videoCodecNames[codec]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", codec)
+}
+
+// SessionRegistry : 접속중인 세션 목록 + HTTP API + 웹훅
+type SessionRegistry struct {
+	WebhookURL string
+	Token      string
+	HTTPClient *http.Client
+
+	nextID   atomic.Uint64
+	mu       sync.Mutex
+	sessions map[uint64]*Session
+}
+
+func NewSessionRegistry(config SessionConfig) *SessionRegistry {
+	return &SessionRegistry{
+		WebhookURL: config.WebhookURL,
+		Token:      config.Token,
+		HTTPClient: &http.Client{Timeout: webhookTimeout},
+		sessions:   make(map[uint64]*Session),
+	}
+}
+
+// Open : 새 연결 (rtmp.ServerConfig.OnConnect 에서)
+func (r *SessionRegistry) Open(conn net.Conn) *Session {
+	s := &Session{
+		ID:         r.nextID.Add(1),
+		RemoteAddr: conn.RemoteAddr().String(),
+		StartedAt:  time.Now(),
+		registry:   r,
+		conn:       conn,
+	}
+
+	r.mu.Lock()
+	r.sessions[s.ID] = s
+	r.mu.Unlock()
+	return s
+}
+
+func (r *SessionRegistry) remove(s *Session) {
+	r.mu.Lock()
+	_, ok := r.sessions[s.ID]
+	delete(r.sessions, s.ID)
+	r.mu.Unlock()
+
+	if !ok {
+		return
+	}
+	if info := s.Info(); info.StreamKey != "" {
+		r.notify(EventSessionEnd, info)
+	}
+}
+
+func (r *SessionRegistry) Get(id uint64) (*Session, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[id]
+	return s, ok
+}
+
+// List : ID 순
+func (r *SessionRegistry) List() []SessionInfo {
+	r.mu.Lock()
+	sessions := make([]*Session, 0, len(r.sessions))
+	for _, s := range r.sessions {
+		sessions = append(sessions, s)
+	}
+	r.mu.Unlock()
+
+	infos := make([]SessionInfo, 0, len(sessions))
+	for _, s := range sessions {
+		infos = append(infos, s.Info())
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
+// Kick : 연결을 끊음, 정리(OnClose -> Session.Close)는 conn goroutine 에서 평소처럼 진행됨
+func (r *SessionRegistry) Kick(id uint64) error {
+	s, ok := r.Get(id)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	log.Infof("세션 강제 종료: %d (%s)", id, s.RemoteAddr)
+	return s.conn.Close()
+}
+
+// notify : 웹훅은 연결 처리를 막지 않게 goroutine 에서 보냄
+func (r *SessionRegistry) notify(event string, info SessionInfo) {
+	if r.WebhookURL == "" {
+		return
+	}
+
+	body, err := json.Marshal(struct {
+		Event   string      `json:"event"`
+		Session SessionInfo `json:"session"`
+	}{Event: event, Session: info})
+	if err != nil {
+		log.Warnf("webhook marshal 실패: %v", err)
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.WebhookURL, bytes.NewReader(body))
+		if err != nil {
+			log.Warnf("webhook request 실패: %v", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := r.HTTPClient.Do(req)
+		if err != nil {
+			log.Warnf("webhook %s 실패: %v", event, err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			log.Warnf("webhook %s 실패: status=%s", event, resp.Status)
+		}
+	}()
+}
+
+// Register : GET /sessions, GET /sessions/{id}, DELETE /sessions/{id} (kick)
+// Token 이 있으면 전부 bearer 토큰 검사 (자막 라우트는 플레이어용이라 제외)
+func (r *SessionRegistry) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /sessions", r.authorize(func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, http.StatusOK, r.List())
+	}))
+	mux.HandleFunc("GET /sessions/{id}", r.authorize(func(w http.ResponseWriter, req *http.Request) {
+		s, err := r.lookup(req)
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, s.Info())
+	}))
+	mux.HandleFunc("DELETE /sessions/{id}", r.authorize(func(w http.ResponseWriter, req *http.Request) {
+		s, err := r.lookup(req)
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		if err := r.Kick(s.ID); err != nil && !errors.Is(err, net.ErrClosed) {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+// authorize : Token 이 설정돼 있으면 "Authorization: Bearer <token>" 확인
+func (r *SessionRegistry) authorize(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if r.Token != "" {
+			token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(r.Token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+		}
+		next(w, req)
+	}
+}
+
+func (r *SessionRegistry) lookup(req *http.Request) (*Session, error) {
+	id, err := strconv.ParseUint(req.PathValue("id"), 10, 64)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	s, ok := r.Get(id)
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	return s, nil
+}
+
+// ServeAPI : 세션 API (+ mux 에 붙인 다른 라우트) 서버, 실패해도 RTMP 는 계속 받음
+func ServeAPI(addr string, mux *http.ServeMux) {
+	if addr == "" {
+		addr = defaultAPIAddr
+	}
+
+	log.Infof("📊 API 서버 시작: %s", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Errorf("API 서버 실행 실패: %v", err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
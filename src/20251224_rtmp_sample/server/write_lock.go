@@ -0,0 +1,53 @@
+package server
+
+import (
+	"io"
+)
+
+// writeLock : play 전송을 go-rtmp 쓰기와 겹치지 않게 함
+// go-rtmp (v0.0.7) ChunkStreamer.Write 는 encoder 를 lock 없이 같이 써서, 연결 goroutine 밖에서 conn.Write 를 부르면 명령 응답 / ack 와 섞여서 깨짐
+// 라이브러리 쓰기는 전부 연결 goroutine 에서 읽기 사이에 일어나니까, 연결 goroutine 은 읽기에서 기다리는 동안만 토큰을 내려놓고
+// 다른 goroutine 은 토큰을 잡은 동안에만 씀
+// OnPlay 가 돌아온 뒤 onStatus(NetStream.Play.Start) 를 보낼 때까지도 연결 goroutine 이 잡고 있어서 play 전송은 항상 그 뒤에 나감
+type writeLock struct {
+	token chan struct{} // 비어있으면 누군가 잡고 있음 (처음엔 연결 goroutine)
+	conn  io.Closer
+}
+
+func newWriteLock() *writeLock {
+	return &writeLock{token: make(chan struct{}, 1)}
+}
+
+// wrap : OnConnect 에서 돌려줄 conn (읽기에서 기다리는 동안만 토큰을 내려놓음)
+func (l *writeLock) wrap(conn io.ReadWriteCloser) io.ReadWriteCloser {
+	l.conn = conn
+	return &lockedConn{ReadWriteCloser: conn, lock: l}
+}
+
+// do : 토큰을 잡고 fn 실행, 잡기 전에 stop 이 닫히면 fn 없이 nil
+func (l *writeLock) do(stop <-chan struct{}, fn func() error) error {
+	select {
+	case <-l.token:
+	case <-stop:
+		return nil
+	}
+	defer func() { l.token <- struct{}{} }()
+	return fn()
+}
+
+// close : 연결 goroutine 의 읽기를 깨워서 OnClose 로 정리되게 함
+func (l *writeLock) close() error {
+	return l.conn.Close()
+}
+
+type lockedConn struct {
+	io.ReadWriteCloser
+	lock *writeLock
+}
+
+func (c *lockedConn) Read(p []byte) (int, error) {
+	c.lock.token <- struct{}{}
+	n, err := c.ReadWriteCloser.Read(p)
+	<-c.lock.token
+	return n, err
+}
@@ -0,0 +1,52 @@
+//go:build captions
+
+package main
+
+import (
+	"example/20251224_rtmp_sample/server"
+
+	"github.com/streamer45/silero-vad-go/speech"
+
+	"example/stt/live"
+	"example/stt/vad"
+	"example/stt/vad/silero"
+	"example/stt/whisper"
+)
+
+// 라이브 자막 (silero VAD + whisper), onnxruntime 이 있어야 빌드됨
+// go run -tags captions server_main.go server_captions.go
+
+func init() {
+	newCaptionService = NewCaptionService
+}
+
+func NewCaptionService(config LiveCaptionConfig) *server.CaptionService {
+	detectorConfig := speech.DetectorConfig{
+		ModelPath:            config.ModelPath,
+		SampleRate:           silero.SampleRate,
+		Threshold:            config.Threshold,
+		MinSilenceDurationMs: 500, // 라이브라 파일 처리때(700)보다 빨리 끊음
+		SpeechPadMs:          200,
+	}
+	if detectorConfig.ModelPath == "" {
+		detectorConfig.ModelPath = "silero_vad.onnx"
+	}
+	if detectorConfig.Threshold == 0 {
+		detectorConfig.Threshold = 0.5
+	}
+	config.SampleRate = silero.SampleRate
+
+	client := whisper.NewClient(config.OpenAIKey)
+	if config.Language != "" {
+		client.Language = config.Language
+	}
+
+	return &server.CaptionService{
+		Config: config.CaptionConfig,
+		Hub:    live.NewHub(),
+		NewDetector: func(onSegment func(vad.Segment)) (live.Detector, error) {
+			return silero.NewStreamingDetector(detectorConfig, onSegment)
+		},
+		Transcriber: &live.WhisperTranscriber{Client: client},
+	}
+}
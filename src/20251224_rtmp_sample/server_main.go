@@ -1,15 +1,22 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"example/20251224_rtmp_sample/server"
 	"io"
 	"net"
+	"net/http"
+	"os"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/yutopp/go-rtmp"
 )
 
+// newCaptionService : server_captions.go 를 같이 빌드할 때만 채워짐 (silero VAD 가 onnxruntime 을 링크해서 기본 빌드에서는 뺌)
+var newCaptionService func(config LiveCaptionConfig) *server.CaptionService
+
 func main() {
 	// 로그 포맷 설정
 	log.SetFormatter(&log.TextFormatter{
@@ -18,6 +25,32 @@ func main() {
 	})
 	log.SetLevel(log.DebugLevel)
 
+	config, err := LoadServerConfig("./server.json")
+	if err != nil {
+		log.Fatalf("Error loading server.json file: %s", err)
+	}
+	authorizer, err := server.NewAuthorizer(config.Auth)
+	if err != nil {
+		log.Fatalf("Error loading auth config: %s", err)
+	}
+	publishers := &server.PublisherLimit{Max: config.Auth.MaxPublishers}
+
+	hub := server.NewPubSubHub()
+	sessions := server.NewSessionRegistry(config.Sessions)
+	mux := http.NewServeMux()
+	sessions.Register(mux)
+
+	var captions *server.CaptionService
+	if config.Captions != nil {
+		if newCaptionService == nil {
+			log.Warn("captions 설정이 있지만 자막 없이 빌드됨, 무시 (make run-server-captions)")
+		} else {
+			captions = newCaptionService(*config.Captions)
+			RegisterCaptions(mux, captions)
+		}
+	}
+	go server.ServeAPI(config.Sessions.HTTPAddr, mux)
+
 	log.Info("========================================")
 	log.Info("RTMP 서버 시작 중...")
 	log.Info("========================================")
@@ -52,14 +85,26 @@ func main() {
 			})
 			l.Logger.SetLevel(log.DebugLevel)
 
-			h := &server.Handler{}
+			session := sessions.Open(conn)
+			h := &server.Handler{
+				PublishGuard: server.PublishGuard{
+					Authorizer: authorizer,
+					Publishers: publishers,
+					RemoteAddr: conn.RemoteAddr().String(),
+				},
+				Record:   config.Record,
+				HLS:      config.HLS,
+				Captions: captions,
+				Session:  session,
+				Hub:      hub,
+			}
 
 			log.WithFields(log.Fields{
 				"connection_id":    connID,
 				"bandwidth_window": 6 * 1024 * 1024 / 8,
 			}).Debug("연결 설정 완료")
 
-			return conn, &rtmp.ConnConfig{
+			return h.WrapConn(session.Conn()), &rtmp.ConnConfig{
 				Handler: h,
 
 				ControlState: rtmp.StreamControlStateConfig{
@@ -76,6 +121,7 @@ func main() {
 	log.Info("📡 포트: 1935")
 	log.Info("📺 OBS 설정: rtmp://localhost:1935/live")
 	log.Info("🔑 스트림 키: 아무거나 (예: test)")
+	log.Info("▶️ 재생: rtmp://localhost:1935/live/<스트림 키>")
 	log.Info("========================================")
 	log.Info("클라이언트 연결 대기 중...")
 	log.Info("")
@@ -90,3 +136,53 @@ func main() {
 		}).Panicf("❌ 서버 실행 실패")
 	}
 }
+
+type ServerConfig struct {
+	Auth     server.AuthConfig     `json:"auth"`
+	Record   server.RecorderConfig `json:"record"`
+	HLS      *server.HLSConfig     `json:"hls"`      // 없으면 FLV 녹화만
+	Captions *LiveCaptionConfig    `json:"captions"` // 없으면 라이브 자막 안함 (sessions.http_addr 에서 같이 서비스)
+	Sessions server.SessionConfig  `json:"sessions"`
+}
+
+// LiveCaptionConfig : 라이브 자막 (silero VAD + whisper, server_captions.go 와 같이 빌드해야 동작)
+type LiveCaptionConfig struct {
+	server.CaptionConfig
+	ModelPath string  `json:"model_path"` // silero_vad.onnx
+	Threshold float32 `json:"threshold"`
+	OpenAIKey string  `json:"openai_key"`
+	Language  string  `json:"language"`
+}
+
+// RegisterCaptions : GET /captions/{stream}/events (SSE), /ws (WebSocket), /vtt (WebVTT)
+func RegisterCaptions(mux *http.ServeMux, captions *server.CaptionService) {
+	mux.HandleFunc("GET /captions/{stream}/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		captions.Hub.ServeSSE(w, r, r.PathValue("stream"))
+	})
+	mux.HandleFunc("GET /captions/{stream}/ws", func(w http.ResponseWriter, r *http.Request) {
+		captions.Hub.ServeWebSocket(w, r, r.PathValue("stream"))
+	})
+	mux.HandleFunc("GET /captions/{stream}/vtt", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Cache-Control", "no-cache") // 계속 늘어나는 파일
+		w.Header().Set("Content-Type", "text/vtt; charset=utf-8")
+		http.ServeFile(w, r, captions.VTTPath(r.PathValue("stream")))
+	})
+}
+
+// LoadServerConfig : 파일이 없으면 인증 / HLS 없이 동작 (기존과 동일)
+func LoadServerConfig(path string) (ServerConfig, error) {
+	config := ServerConfig{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+
+	err = json.Unmarshal(data, &config)
+	return config, err
+}
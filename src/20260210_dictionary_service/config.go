@@ -0,0 +1,38 @@
+package main
+
+import (
+	"log"
+
+	"github.com/joho/godotenv"
+	"github.com/kelseyhightower/envconfig"
+)
+
+type Config struct {
+	Port           string `envconfig:"PORT" default:":8080"`
+	Store          string `envconfig:"DICT_STORE" default:"file"`         // file | db
+	Dir            string `envconfig:"DICT_DIR" default:"dictionaries"`   // file 저장소 경로
+	ReloadInterval int    `envconfig:"DICT_RELOAD_INTERVAL" default:"10"` // 저장소 polling 주기 (단위: second, 0 이하면 polling 안함)
+
+	DbConfig DbConfig
+}
+
+type DbConfig struct {
+	Host     string `envconfig:"DB_HOST" default:"localhost"`
+	Port     int    `envconfig:"DB_PORT" default:"3306"`
+	User     string `envconfig:"DB_USER"`
+	Password string `envconfig:"DB_PASSWORD"`
+	Database string `envconfig:"DB_DATABASE"`
+}
+
+func InitConfig() (*Config, error) {
+	// .env 없으면 환경변수만 사용
+	if err := godotenv.Load(); err != nil {
+		log.Printf("skip .env : %v\n", err)
+	}
+
+	config := &Config{}
+	if err := envconfig.Process("", config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
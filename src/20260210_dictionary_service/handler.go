@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"example/stt/dictionary"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	registry *dictionary.Registry
+}
+
+func NewHandler(registry *dictionary.Registry) *Handler {
+	return &Handler{registry: registry}
+}
+
+func (h *Handler) Register(e *echo.Echo) {
+	e.GET("/dictionaries", h.list)
+	e.GET("/dictionaries/:name", h.get)
+	e.PUT("/dictionaries/:name", h.put)
+	e.POST("/dictionaries/:name/entries", h.upsertEntry)
+	e.DELETE("/dictionaries/:name/entries/:word", h.deleteEntry)
+	e.POST("/dictionaries/:name/validate", h.validate)
+	e.POST("/dictionaries/:name/dry-run", h.dryRun)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// DryRunRequest : Dict 가 없으면 현재 저장된 버전으로 돌려봄
+type DryRunRequest struct {
+	Dict    *dictionary.Dict `json:"dict"`
+	Samples []string         `json:"samples"`
+}
+
+// list : 저장된 사전 + 현재 적용중인 버전
+func (h *Handler) list(c echo.Context) error {
+	names, err := h.registry.List(c.Request().Context())
+	if err != nil {
+		return h.fail(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"names":    names,
+		"versions": h.registry.Versions(),
+	})
+}
+
+func (h *Handler) get(c echo.Context) error {
+	dict, err := h.registry.Load(c.Request().Context(), c.Param("name"))
+	if err != nil {
+		return h.fail(c, err)
+	}
+	return c.JSON(http.StatusOK, dict)
+}
+
+// put : 사전 전체 교체 (version 이 비어있으면 자동 생성)
+func (h *Handler) put(c echo.Context) error {
+	dict := &dictionary.Dict{}
+	if err := c.Bind(dict); err != nil {
+		return c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+	}
+
+	if err := h.registry.Save(c.Request().Context(), c.Param("name"), dict); err != nil {
+		return h.fail(c, err)
+	}
+	return c.JSON(http.StatusOK, dict)
+}
+
+func (h *Handler) upsertEntry(c echo.Context) error {
+	entry := dictionary.Entry{}
+	if err := c.Bind(&entry); err != nil {
+		return c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+	}
+
+	dict, err := h.registry.Update(c.Request().Context(), c.Param("name"), func(dict *dictionary.Dict) error {
+		dict.UpsertEntry(entry)
+		return nil
+	})
+	if err != nil {
+		return h.fail(c, err)
+	}
+	return c.JSON(http.StatusOK, dict)
+}
+
+func (h *Handler) deleteEntry(c echo.Context) error {
+	word := c.Param("word")
+	dict, err := h.registry.Update(c.Request().Context(), c.Param("name"), func(dict *dictionary.Dict) error {
+		if !dict.RemoveEntry(word) {
+			return dictionary.ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return h.fail(c, err)
+	}
+	return c.JSON(http.StatusOK, dict)
+}
+
+// validate : 저장하지 않고 검증만
+func (h *Handler) validate(c echo.Context) error {
+	dict := &dictionary.Dict{}
+	if err := c.Bind(dict); err != nil {
+		return c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+	}
+	if err := dictionary.ValidateName(c.Param("name")); err != nil {
+		return h.fail(c, err)
+	}
+	if err := dict.Validate(); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, errorResponse{Error: err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]bool{"valid": true})
+}
+
+// dryRun : 새 버전을 샘플 자막에 적용했을 때 바뀌는 부분
+func (h *Handler) dryRun(c echo.Context) error {
+	req := DryRunRequest{}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	name := c.Param("name")
+	if req.Dict == nil {
+		dict, err := h.registry.Load(ctx, name)
+		if err != nil {
+			return h.fail(c, err)
+		}
+		req.Dict = dict
+	}
+
+	results, err := h.registry.DryRun(ctx, name, req.Dict, req.Samples)
+	if err != nil {
+		return h.fail(c, err)
+	}
+	return c.JSON(http.StatusOK, results)
+}
+
+// fail : dictionary 에러 -> HTTP status
+func (h *Handler) fail(c echo.Context, err error) error {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, dictionary.ErrInvalidDict):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, dictionary.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, dictionary.ErrInvalidName):
+		status = http.StatusBadRequest
+	case errors.Is(err, dictionary.ErrVersionConflict), errors.Is(err, dictionary.ErrConcurrentUpdate):
+		status = http.StatusConflict
+	}
+	return c.JSON(status, errorResponse{Error: err.Error()})
+}
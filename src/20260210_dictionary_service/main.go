@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"example/common"
+	"example/stt/dictionary"
+	"log"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 고객사별 치환 사전 관리 서버
+// 사전 저장 -> 검증 -> 새 버전이면 트라이 다시 빌드해서 교체 (요청 처리중인 쪽은 이전 트라이를 그대로 씀)
+func main() {
+	config, err := InitConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	store, err := newStore(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	registry := dictionary.NewRegistry(store)
+	if _, err = registry.Reload(context.Background()); err != nil {
+		log.Printf("initial reload failed: %v\n", err)
+	}
+
+	// 다른 인스턴스에서 바꾼 것도 반영
+	if config.ReloadInterval > 0 {
+		go registry.Watch(context.Background(), time.Duration(config.ReloadInterval)*time.Second)
+	} else {
+		log.Println("DICT_RELOAD_INTERVAL <= 0, reload polling disabled")
+	}
+
+	e := echo.New()
+	NewHandler(registry).Register(e)
+
+	log.Fatal(e.Start(config.Port))
+}
+
+func newStore(config *Config) (dictionary.Store, error) {
+	if config.Store != "db" {
+		return dictionary.NewFileStore(config.Dir)
+	}
+
+	err := common.Init(&common.DBConfig{
+		Host:            config.DbConfig.Host,
+		Port:            config.DbConfig.Port,
+		Username:        config.DbConfig.User,
+		Password:        config.DbConfig.Password,
+		Database:        config.DbConfig.Database,
+		MaxIdleConns:    5,
+		MaxOpenConns:    5,
+		ConnMaxLifetime: 3 * time.Minute,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = common.GetDB().Exec(dictionary.SQLSchema); err != nil {
+		return nil, err
+	}
+	return dictionary.NewSQLStore(common.GetDB()), nil
+}
@@ -0,0 +1,178 @@
+package chunking
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"example/stt/ffmpeg"
+	"example/stt/vad"
+)
+
+type AudioChunk struct {
+	StartSec   float64
+	EndSec     float64
+	OverlapSec float64
+
+	// 청크에 속한 VAD 세그먼트들
+	VADSegments []vad.Segment
+
+	// 디버깅용
+	Index    int
+	Duration float64
+
+	// 실패 청크 재분할용 : 최초 청크는 Depth 0, Label 은 파일명에 사용 (e.g. 0003, 0003-1)
+	Depth int
+	Label string
+}
+
+type ChunkingConfig struct {
+	MinDurationSec float64
+	MaxDurationSec float64
+	OverlapSec     float64
+}
+
+// ChunkLabel : 청크 파일명 및 로그에 사용할 라벨
+func (c AudioChunk) ChunkLabel() string {
+	if c.Label != "" {
+		return c.Label
+	}
+	return fmt.Sprintf("%04d", c.Index)
+}
+
+// CreateAudioChunks : VAD 세그먼트 경계에서 MaxDurationSec 을 넘지 않도록 청크 분할
+// 청크끼리 겹치지 않고 파일 처음부터 끝까지 이어지며, MinDurationSec 보다 짧은 청크는 이전 청크와 병합
+func CreateAudioChunks(vadSegments []vad.Segment, config ChunkingConfig, totalDurationSec float64) []AudioChunk {
+	if len(vadSegments) == 0 {
+		return nil
+	}
+
+	chunks := make([]AudioChunk, 0)
+	currentChunk := AudioChunk{
+		StartSec:    0, // 파일 처음부터 시작
+		VADSegments: []vad.Segment{},
+		Index:       0,
+	}
+
+	for i, seg := range vadSegments {
+		// 현재 청크에 이 세그먼트를 추가했을 때의 duration
+		potentialDuration := seg.SpeechEndAt - currentChunk.StartSec
+
+		// MaxDuration을 초과하면 청크 분할
+		if potentialDuration > config.MaxDurationSec && len(currentChunk.VADSegments) > 0 {
+			// 현재 청크 마무리: 마지막 VAD 세그먼트가 끝나는 지점까지
+			lastSegEnd := currentChunk.VADSegments[len(currentChunk.VADSegments)-1].SpeechEndAt
+			currentChunk.EndSec = lastSegEnd
+			currentChunk.Duration = currentChunk.EndSec - currentChunk.StartSec
+			chunks = append(chunks, currentChunk)
+
+			// 새 청크 시작: 이전 청크가 끝난 바로 다음부터 (겹침 없음)
+			currentChunk = AudioChunk{
+				StartSec:    lastSegEnd,
+				VADSegments: []vad.Segment{seg},
+				Index:       len(chunks),
+			}
+		} else {
+			currentChunk.VADSegments = append(currentChunk.VADSegments, seg)
+		}
+
+		// 마지막 세그먼트면 청크 저장
+		if i == len(vadSegments)-1 {
+			currentChunk.EndSec = totalDurationSec // 파일 끝까지
+			currentChunk.Duration = currentChunk.EndSec - currentChunk.StartSec
+			chunks = append(chunks, currentChunk)
+		}
+	}
+
+	// MinDuration 체크: 너무 짧은 청크는 이전 청크와 병합
+	if len(chunks) > 1 {
+		chunks = mergeShortChunks(chunks, config.MinDurationSec)
+	}
+
+	return chunks
+}
+
+// mergeShortChunks : MinDuration보다 짧은 청크를 이전 청크와 병합
+func mergeShortChunks(chunks []AudioChunk, minDuration float64) []AudioChunk {
+	merged := make([]AudioChunk, 0, len(chunks))
+
+	for i, chunk := range chunks {
+		if chunk.Duration < minDuration && i > 0 {
+			prev := &merged[len(merged)-1]
+			prev.EndSec = chunk.EndSec
+			prev.Duration = prev.EndSec - prev.StartSec
+			prev.VADSegments = append(prev.VADSegments, chunk.VADSegments...)
+		} else {
+			chunk.Index = len(merged)
+			merged = append(merged, chunk)
+		}
+	}
+
+	return merged
+}
+
+// ExtractChunkAudio : 오디오에서 청크 구간 추출, outputDir/chunk_<label>.wav 경로 반환
+func ExtractChunkAudio(ctx context.Context, audioPath string, chunk AudioChunk, outputDir string) (string, error) {
+	outputPath := filepath.Join(outputDir, fmt.Sprintf("chunk_%s.wav", chunk.ChunkLabel()))
+
+	if err := ffmpeg.ExtractSegment(ctx, audioPath, outputPath, chunk.StartSec, chunk.EndSec); err != nil {
+		return "", err
+	}
+
+	log.Printf("Extracted chunk #%s: %.2fs - %.2fs (duration: %.2fs = %s) -> %s",
+		chunk.ChunkLabel(),
+		chunk.StartSec,
+		chunk.EndSec,
+		chunk.Duration,
+		FormatDuration(chunk.Duration),
+		outputPath,
+	)
+
+	return outputPath, nil
+}
+
+// SaveChunkInfo : 청크 정보를 텍스트 파일로 저장
+func SaveChunkInfo(chunks []AudioChunk, outputDir string) error {
+	infoPath := filepath.Join(outputDir, "chunks_info.txt")
+	f, err := os.Create(infoPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Fprintf(f, "Total Chunks: %d\n", len(chunks))
+	fmt.Fprintf(f, "=====================================\n\n")
+
+	for _, chunk := range chunks {
+		fmt.Fprintf(f, "Chunk #%d:\n", chunk.Index)
+		fmt.Fprintf(f, "  Time Range: %.3fs - %.3fs\n", chunk.StartSec, chunk.EndSec)
+		fmt.Fprintf(f, "  Duration: %.2fs = %s\n", chunk.Duration, FormatDuration(chunk.Duration))
+		fmt.Fprintf(f, "  Overlap: %.2fs\n", chunk.OverlapSec)
+		fmt.Fprintf(f, "  VAD Segments: %d\n", len(chunk.VADSegments))
+
+		if len(chunk.VADSegments) > 0 {
+			fmt.Fprintf(f, "  Speech Segments:\n")
+			for i, seg := range chunk.VADSegments {
+				fmt.Fprintf(f, "    [%d] %.3fs - %.3fs (%.2fs)\n",
+					i,
+					seg.SpeechStartAt,
+					seg.SpeechEndAt,
+					seg.Duration(),
+				)
+			}
+		}
+		fmt.Fprintf(f, "\n")
+	}
+
+	log.Printf("Chunk info saved to: %s\n", infoPath)
+	return nil
+}
+
+// FormatDuration : 초 -> "m분 ss초"
+func FormatDuration(seconds float64) string {
+	minutes := int(seconds) / 60
+	secs := int(seconds) % 60
+	return fmt.Sprintf("%d분 %02d초", minutes, secs)
+}
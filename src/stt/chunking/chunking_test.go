@@ -0,0 +1,146 @@
+package chunking
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/stt/vad"
+)
+
+func TestCreateAudioChunks(t *testing.T) {
+	config := ChunkingConfig{MinDurationSec: 10, MaxDurationSec: 60}
+
+	t.Run("세그먼트가 없으면 nil", func(t *testing.T) {
+		assert.Nil(t, CreateAudioChunks(nil, config, 100))
+	})
+
+	t.Run("MaxDuration 경계에서 분할, 겹침 없이 파일 끝까지", func(t *testing.T) {
+		segs := []vad.Segment{
+			{SpeechStartAt: 1, SpeechEndAt: 20},
+			{SpeechStartAt: 25, SpeechEndAt: 50},
+			{SpeechStartAt: 55, SpeechEndAt: 80},
+			{SpeechStartAt: 85, SpeechEndAt: 100},
+		}
+
+		chunks := CreateAudioChunks(segs, config, 120)
+		require.Len(t, chunks, 2)
+
+		assert.Equal(t, 0.0, chunks[0].StartSec)
+		assert.Equal(t, 50.0, chunks[0].EndSec)
+		assert.Len(t, chunks[0].VADSegments, 2)
+
+		assert.Equal(t, 50.0, chunks[1].StartSec)
+		assert.Equal(t, 120.0, chunks[1].EndSec)
+		assert.Equal(t, 1, chunks[1].Index)
+	})
+
+	t.Run("MinDuration 보다 짧은 마지막 청크는 이전 청크와 병합", func(t *testing.T) {
+		segs := []vad.Segment{
+			{SpeechStartAt: 1, SpeechEndAt: 55},
+			{SpeechStartAt: 56, SpeechEndAt: 58},
+		}
+
+		chunks := CreateAudioChunks(segs, ChunkingConfig{MinDurationSec: 10, MaxDurationSec: 56}, 60)
+		require.Len(t, chunks, 1)
+		assert.Equal(t, 60.0, chunks[0].EndSec)
+		assert.Len(t, chunks[0].VADSegments, 2)
+	})
+}
+
+func TestSplitFailedChunk(t *testing.T) {
+	chunk := AudioChunk{
+		StartSec: 0,
+		EndSec:   40,
+		Index:    3,
+		VADSegments: []vad.Segment{
+			{SpeechStartAt: 0, SpeechEndAt: 17},
+			{SpeechStartAt: 19, SpeechEndAt: 40},
+		},
+	}
+
+	subs := SplitFailedChunk(chunk, 2, 5)
+	require.Len(t, subs, 2)
+
+	// 무음 구간 중간(18초)으로 스냅
+	assert.Equal(t, 18.0, subs[0].EndSec)
+	assert.Equal(t, 18.0, subs[1].StartSec)
+	assert.Equal(t, "0003-0", subs[0].Label)
+	assert.Equal(t, "0003-1", subs[1].Label)
+	assert.Equal(t, 1, subs[1].Depth)
+	assert.Len(t, subs[0].VADSegments, 1)
+	assert.Len(t, subs[1].VADSegments, 1)
+
+	// 최소 길이보다 짧아지면 나누지 않음
+	assert.Nil(t, SplitFailedChunk(AudioChunk{StartSec: 0, EndSec: 8}, 2, 5))
+}
+
+func TestRetryFailedChunks(t *testing.T) {
+	failed := []AudioChunk{{StartSec: 0, EndSec: 40, Index: 0}}
+	config := RetryConfig{MaxSplitDepth: 2, SplitParts: 2, MinSubChunkSec: 5}
+
+	// 첫번째 서브 청크만 계속 실패
+	calls := 0
+	recovered, unrecovered := RetryFailedChunks(failed, config, func(c AudioChunk) error {
+		calls++
+		if c.StartSec == 0 {
+			return errors.New("whisper fail")
+		}
+		return nil
+	})
+
+	assert.Equal(t, 4, calls)
+	require.Len(t, recovered, 2)
+	assert.Equal(t, "0000-1", recovered[0].Label)
+	assert.Equal(t, "0000-0-1", recovered[1].Label)
+	require.Len(t, unrecovered, 1)
+	assert.Equal(t, "0000-0-0", unrecovered[0].Label)
+	assert.Equal(t, 10.0, unrecovered[0].EndSec)
+}
+
+func TestEvaluateCompletion(t *testing.T) {
+	success := []AudioChunk{{StartSec: 0, EndSec: 30}}
+	failed := []AudioChunk{{StartSec: 30, EndSec: 40}}
+
+	ratio, err := EvaluateCompletion(RetryConfig{Policy: CompletionPolicyAll}, success, failed)
+	assert.Error(t, err)
+	assert.InDelta(t, 0.75, ratio, 1e-9)
+
+	_, err = EvaluateCompletion(RetryConfig{Policy: CompletionPolicyQuorum, QuorumRatio: 0.7}, success, failed)
+	assert.NoError(t, err)
+
+	_, err = EvaluateCompletion(RetryConfig{Policy: CompletionPolicyQuorum, QuorumRatio: 0.8}, success, failed)
+	assert.Error(t, err)
+
+	_, err = EvaluateCompletion(RetryConfig{Policy: CompletionPolicyBestEffort}, success, failed)
+	assert.NoError(t, err)
+
+	_, err = EvaluateCompletion(RetryConfig{Policy: CompletionPolicyBestEffort}, nil, failed)
+	assert.Error(t, err)
+
+	_, err = EvaluateCompletion(RetryConfig{Policy: "unknown"}, success, nil)
+	assert.Error(t, err)
+}
+
+func TestBuildFailedPlaceholders(t *testing.T) {
+	out := BuildFailedPlaceholders([]AudioChunk{{StartSec: 1.234, EndSec: 5.678}})
+	require.Len(t, out, 1)
+	assert.True(t, out[0].Failed)
+	assert.Equal(t, 1.23, out[0].StartTime)
+	assert.Equal(t, 5.68, out[0].EndTime)
+}
+
+func TestSaveChunkInfo(t *testing.T) {
+	dir := t.TempDir()
+	chunks := []AudioChunk{{StartSec: 0, EndSec: 75, Duration: 75, VADSegments: []vad.Segment{{SpeechStartAt: 1, SpeechEndAt: 2}}}}
+	require.NoError(t, SaveChunkInfo(chunks, dir))
+
+	data, err := os.ReadFile(filepath.Join(dir, "chunks_info.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "1분 15초")
+	assert.Contains(t, string(data), "[0] 1.000s - 2.000s")
+}
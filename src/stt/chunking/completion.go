@@ -0,0 +1,201 @@
+package chunking
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+
+	"example/stt/subtitle"
+	"example/stt/vad"
+)
+
+// CompletionPolicy : 청크 일부가 실패했을 때 작업을 계속 진행할지 결정하는 정책
+type CompletionPolicy string
+
+const (
+	CompletionPolicyAll        CompletionPolicy = "all"         // 모든 구간이 성공해야 진행
+	CompletionPolicyQuorum     CompletionPolicy = "quorum"      // QuorumRatio 이상 성공하면 진행
+	CompletionPolicyBestEffort CompletionPolicy = "best-effort" // 하나라도 성공하면 진행
+)
+
+// RetryConfig : 실패 청크 재시도 및 완료 정책 설정
+type RetryConfig struct {
+	Policy         CompletionPolicy
+	QuorumRatio    float64 // 성공 구간 비율 (시간 기준, 0~1). quorum 일때만 사용
+	MaxSplitDepth  int     // 실패 청크 재분할 최대 깊이 (0 이면 재시도 안함)
+	SplitParts     int     // 한번에 몇 개의 서브 청크로 나눌지
+	MinSubChunkSec float64 // 서브 청크 최소 길이, 이보다 짧아지면 더이상 나누지 않음
+}
+
+// SplitFailedChunk : 실패한 청크를 parts 개의 서브 청크로 분할
+// 가능하면 VAD 세그먼트 사이 무음 구간에서 자르고, 무음 구간이 없으면 균등 분할 지점을 그대로 사용함
+func SplitFailedChunk(chunk AudioChunk, parts int, minSubChunkSec float64) []AudioChunk {
+	duration := chunk.EndSec - chunk.StartSec
+	if parts < 2 || duration <= 0 {
+		return nil
+	}
+
+	// 최소 길이를 지키지 못하면 나누는 개수를 줄임
+	if minSubChunkSec > 0 && duration/float64(parts) < minSubChunkSec {
+		parts = int(duration / minSubChunkSec)
+	}
+	if parts < 2 {
+		return nil
+	}
+
+	// 후보 절단 지점 : 인접 VAD 세그먼트 사이 무음 구간의 중간
+	gaps := make([]float64, 0, len(chunk.VADSegments))
+	for i := 1; i < len(chunk.VADSegments); i++ {
+		prevEnd := chunk.VADSegments[i-1].SpeechEndAt
+		nextStart := chunk.VADSegments[i].SpeechStartAt
+		if nextStart > prevEnd {
+			gaps = append(gaps, (prevEnd+nextStart)/2)
+		}
+	}
+
+	// 분할 지점 당 허용하는 스냅 거리 (균등 분할 길이의 절반 미만)
+	step := duration / float64(parts)
+	maxSnap := step / 2
+
+	bounds := []float64{chunk.StartSec}
+	for k := 1; k < parts; k++ {
+		target := chunk.StartSec + step*float64(k)
+		cut := target
+
+		best := math.MaxFloat64
+		for _, g := range gaps {
+			if d := math.Abs(g - target); d < best && d < maxSnap {
+				best = d
+				cut = g
+			}
+		}
+
+		// 이전 지점보다 앞서거나 같으면 스킵 (스냅 결과가 겹치는 경우)
+		if cut <= bounds[len(bounds)-1] || cut >= chunk.EndSec {
+			continue
+		}
+		bounds = append(bounds, cut)
+	}
+	bounds = append(bounds, chunk.EndSec)
+
+	if len(bounds) < 3 {
+		return nil
+	}
+
+	parentLabel := chunk.ChunkLabel()
+	subChunks := make([]AudioChunk, 0, len(bounds)-1)
+	for k := 0; k < len(bounds)-1; k++ {
+		sub := AudioChunk{
+			StartSec:    bounds[k],
+			EndSec:      bounds[k+1],
+			VADSegments: []vad.Segment{},
+			Index:       chunk.Index,
+			Duration:    bounds[k+1] - bounds[k],
+			Depth:       chunk.Depth + 1,
+			Label:       parentLabel + "-" + strconv.Itoa(k),
+		}
+
+		// 시작 지점 기준으로 VAD 세그먼트 배분
+		for _, seg := range chunk.VADSegments {
+			if seg.SpeechStartAt >= sub.StartSec && seg.SpeechStartAt < sub.EndSec {
+				sub.VADSegments = append(sub.VADSegments, seg)
+			}
+		}
+
+		subChunks = append(subChunks, sub)
+	}
+
+	return subChunks
+}
+
+// RetryFailedChunks : 실패한 청크를 더 작은 서브 청크로 쪼개서 재시도
+// process 가 에러를 돌려주면 실패로 보고, MaxSplitDepth 까지 다시 쪼갬. 더 쪼갤 수 없는 구간은 unrecovered 로 반환
+// 성공한 서브 청크의 결과는 process 쪽에서 보관해야 함
+func RetryFailedChunks(failed []AudioChunk, config RetryConfig, process func(AudioChunk) error) ([]AudioChunk, []AudioChunk) {
+	recovered := make([]AudioChunk, 0)
+	unrecovered := make([]AudioChunk, 0)
+
+	queue := failed
+	for depth := 1; depth <= config.MaxSplitDepth && len(queue) > 0; depth++ {
+		next := make([]AudioChunk, 0)
+
+		for _, chunk := range queue {
+			subChunks := SplitFailedChunk(chunk, config.SplitParts, config.MinSubChunkSec)
+			if len(subChunks) == 0 {
+				log.Printf("Chunk %s (%.2fs - %.2fs) is too short to split, giving up\n",
+					chunk.ChunkLabel(), chunk.StartSec, chunk.EndSec)
+				unrecovered = append(unrecovered, chunk)
+				continue
+			}
+
+			log.Printf("Retrying chunk %s as %d sub-chunks (depth %d)\n", chunk.ChunkLabel(), len(subChunks), depth)
+
+			for _, sub := range subChunks {
+				if err := process(sub); err != nil {
+					log.Printf("  ✗ Sub-chunk %s failed: %v\n", sub.ChunkLabel(), err)
+					next = append(next, sub)
+				} else {
+					log.Printf("  ✓ Sub-chunk %s recovered (%.2fs - %.2fs)\n", sub.ChunkLabel(), sub.StartSec, sub.EndSec)
+					recovered = append(recovered, sub)
+				}
+			}
+		}
+
+		queue = next
+	}
+
+	unrecovered = append(unrecovered, queue...)
+
+	return recovered, unrecovered
+}
+
+// EvaluateCompletion : 완료 정책에 따라 병합 단계로 진행 가능한지 판단, 성공 구간 비율(시간 기준)을 함께 반환
+func EvaluateCompletion(config RetryConfig, success, failed []AudioChunk) (float64, error) {
+	var successSec, failedSec float64
+	for _, c := range success {
+		successSec += c.EndSec - c.StartSec
+	}
+	for _, c := range failed {
+		failedSec += c.EndSec - c.StartSec
+	}
+
+	totalSec := successSec + failedSec
+	if totalSec <= 0 || len(success) == 0 {
+		return 0, fmt.Errorf("no successful chunks")
+	}
+	ratio := successSec / totalSec
+
+	switch config.Policy {
+	case CompletionPolicyAll:
+		if len(failed) > 0 {
+			return ratio, fmt.Errorf("policy %q: %d chunk range(s) failed", config.Policy, len(failed))
+		}
+	case CompletionPolicyQuorum:
+		if ratio < config.QuorumRatio {
+			return ratio, fmt.Errorf("policy %q: success ratio %.2f is below quorum %.2f", config.Policy, ratio, config.QuorumRatio)
+		}
+	case CompletionPolicyBestEffort:
+		// 성공한 구간이 하나라도 있으면 진행
+	default:
+		return ratio, fmt.Errorf("unknown completion policy: %q", config.Policy)
+	}
+
+	return ratio, nil
+}
+
+// BuildFailedPlaceholders : 최종 실패한 구간을 자막 데이터에 명시적으로 표시하기 위한 placeholder 생성
+func BuildFailedPlaceholders(failed []AudioChunk) []subtitle.SubtitleSegment {
+	placeholders := make([]subtitle.SubtitleSegment, 0, len(failed))
+
+	for _, c := range failed {
+		placeholders = append(placeholders, subtitle.SubtitleSegment{
+			StartTime:      subtitle.RoundSeconds(c.StartSec),
+			EndTime:        subtitle.RoundSeconds(c.EndSec),
+			SentenceFrames: []subtitle.SentenceFrames{},
+			Failed:         true,
+		})
+	}
+
+	return placeholders
+}
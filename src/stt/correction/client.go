@@ -0,0 +1,216 @@
+package correction
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/genai"
+)
+
+// Client : 교정용 LLM (Gemini / OpenAI / 로컬 stub)
+type Client interface {
+	// CountTokens : 지시문 + 프롬프트를 보냈을 때 입력 토큰 수
+	CountTokens(ctx context.Context, instruction, prompt string) (int, error)
+	// Generate : 응답 텍스트 (JSON 을 기대하지만 코드펜스가 붙어올 수 있음)
+	Generate(ctx context.Context, instruction, prompt string) (string, error)
+}
+
+const (
+	DefaultGeminiModel = "gemini-2.5-flash"
+	DefaultOpenAIModel = "gpt-4o-mini"
+
+	DefaultOpenAIEndpoint = "https://api.openai.com/v1/chat/completions"
+)
+
+// GeminiClient : google.golang.org/genai (20251106_gemini 스크립트와 같은 모델)
+type GeminiClient struct {
+	Model  string
+	client *genai.Client
+}
+
+func NewGeminiClient(ctx context.Context, apiKey string) (*GeminiClient, error) {
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+		APIKey:  apiKey,
+		Backend: genai.BackendGeminiAPI,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gemini client fail: %w", err)
+	}
+	return &GeminiClient{Model: DefaultGeminiModel, client: client}, nil
+}
+
+func (c *GeminiClient) contents(instruction, prompt string) []*genai.Content {
+	return []*genai.Content{
+		{
+			Role: genai.RoleUser,
+			Parts: []*genai.Part{
+				{Text: instruction},
+				{Text: prompt},
+			},
+		},
+	}
+}
+
+func (c *GeminiClient) CountTokens(ctx context.Context, instruction, prompt string) (int, error) {
+	info, err := c.client.Models.CountTokens(ctx, c.Model, c.contents(instruction, prompt), nil)
+	if err != nil {
+		return 0, fmt.Errorf("gemini count tokens fail: %w", err)
+	}
+	return int(info.TotalTokens), nil
+}
+
+func (c *GeminiClient) Generate(ctx context.Context, instruction, prompt string) (string, error) {
+	result, err := c.client.Models.GenerateContent(ctx, c.Model, c.contents(instruction, prompt), &genai.GenerateContentConfig{
+		Temperature:      genai.Ptr[float32](0),
+		ResponseMIMEType: "application/json",
+	})
+	if err != nil {
+		return "", fmt.Errorf("gemini generate fail: %w", err)
+	}
+	return result.Text(), nil
+}
+
+// OpenAIClient : Chat Completions API (SDK 없이 whisper.Client 처럼 직접 호출)
+// 토큰 카운트 API 가 없어서 CountTokens 는 글자 수 기반 추정치
+type OpenAIClient struct {
+	APIKey   string
+	Endpoint string
+	Model    string
+	Timeout  time.Duration
+
+	HTTPClient *http.Client
+}
+
+func NewOpenAIClient(apiKey string) *OpenAIClient {
+	return &OpenAIClient{
+		APIKey:     apiKey,
+		Endpoint:   DefaultOpenAIEndpoint,
+		Model:      DefaultOpenAIModel,
+		Timeout:    2 * time.Minute,
+		HTTPClient: &http.Client{},
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model          string            `json:"model"`
+	Messages       []chatMessage     `json:"messages"`
+	Temperature    float64           `json:"temperature"`
+	ResponseFormat map[string]string `json:"response_format,omitempty"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func (c *OpenAIClient) CountTokens(ctx context.Context, instruction, prompt string) (int, error) {
+	return EstimateTokens(instruction + prompt), nil
+}
+
+func (c *OpenAIClient) Generate(ctx context.Context, instruction, prompt string) (string, error) {
+	body, err := json.Marshal(chatRequest{
+		Model: c.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: instruction},
+			{Role: "user", Content: prompt},
+		},
+		ResponseFormat: map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("JSON marshal failed: %w", err)
+	}
+
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("http request fail: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("http request fail: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("response read fail: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("openai api error: status=%s body=%s", resp.Status, string(responseBody))
+	}
+
+	ret := &chatResponse{}
+	if err = json.Unmarshal(responseBody, ret); err != nil {
+		return "", fmt.Errorf("json parsing error: %w", err)
+	}
+	if len(ret.Choices) == 0 {
+		return "", fmt.Errorf("openai api error: empty choices")
+	}
+	return ret.Choices[0].Message.Content, nil
+}
+
+// StubClient : 네트워크 없이 테스트/로컬 실행용, Fn 으로 문장을 고침 (nil 이면 그대로 돌려줌)
+type StubClient struct {
+	Fn func(text string) string
+}
+
+func (c *StubClient) CountTokens(ctx context.Context, instruction, prompt string) (int, error) {
+	return EstimateTokens(instruction + prompt), nil
+}
+
+func (c *StubClient) Generate(ctx context.Context, instruction, prompt string) (string, error) {
+	req := batchPayload{}
+	if err := json.Unmarshal([]byte(prompt), &req); err != nil {
+		return "", fmt.Errorf("json parsing error: %w", err)
+	}
+
+	for i := range req.Segments {
+		if c.Fn != nil {
+			req.Segments[i].Text = c.Fn(req.Segments[i].Text)
+		}
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("JSON marshal failed: %w", err)
+	}
+	return string(data), nil
+}
+
+// EstimateTokens : 토큰 API 가 없는 클라이언트용 대략치 (영어 4글자, 한글/CJK 1글자 = 1토큰 정도)
+func EstimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return ascii/4 + other + 1
+}
@@ -0,0 +1,238 @@
+package correction
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"example/stt/subtitle"
+)
+
+// DefaultInstruction : 20251106_gemini 스크립트 프롬프트 기준 (SRT 통째로 보내던걸 ID 붙은 JSON 으로 바꿈)
+const DefaultInstruction = `
+1. 작업 목표: 자막 문장의 텍스트를 교정합니다.
+2. 교정 원칙:
+글자만 수정: 잘못 인식된 단어, 고유명사, 맞춤법만 수정합니다. 의미를 바꾸거나 문장을 다시 쓰지 않습니다.
+분할 단위 유지: 문장을 합치거나 나누지 않습니다. 입력 segments 의 개수, 순서, id 를 그대로 유지합니다.
+3. 입출력 형식: 입력과 같은 JSON 형식 {"segments":[{"id":"...","text":"..."}]} 으로만 응답합니다.
+`
+
+var (
+	ErrInvalidResponse = errors.New("invalid llm response")
+)
+
+// Config : 교정 설정
+type Config struct {
+	Instruction    string  // 시스템 지시문 (고객사 규칙 추가시 DefaultInstruction 뒤에 붙여서 사용)
+	MaxBatchTokens int     // 배치 1개 입력 토큰 상한 (CountTokens 기준)
+	MaxEditRatio   float64 // 문장별 허용 편집 비율, 넘으면 의미가 바뀐걸로 보고 버림
+	MaxRetry       int     // 응답 검증 실패시 재요청 횟수
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Instruction:    DefaultInstruction,
+		MaxBatchTokens: 4000,
+		MaxEditRatio:   0.3,
+		MaxRetry:       1,
+	}
+}
+
+// Corrector : SubtitleSegment.Sentence 를 배치로 LLM 에 보내서 LLMCorrectSentence 를 채움
+// 타임스탬프, SentenceFrames 는 건드리지 않음
+type Corrector struct {
+	Client Client
+	Config Config
+}
+
+func NewCorrector(client Client, config Config) *Corrector {
+	return &Corrector{Client: client, Config: config}
+}
+
+// Rejection : 편집 예산을 넘어서 버린 교정
+type Rejection struct {
+	ID        string  `json:"id"`
+	Original  string  `json:"original"`
+	Corrected string  `json:"corrected"`
+	EditRatio float64 `json:"edit_ratio"`
+}
+
+// Report : 교정 결과 요약 (작업 리포트용)
+type Report struct {
+	Batches    int         `json:"batches"`
+	Corrected  int         `json:"corrected"` // 바뀐 문장
+	Unchanged  int         `json:"unchanged"`
+	Rejected   []Rejection `json:"rejected"`
+	FailedIDs  []string    `json:"failed_ids"` // 응답 검증 실패로 교정하지 못한 문장
+	TokenCount int         `json:"token_count"`
+}
+
+// batchItem : LLM 에 보내는 문장 하나 (id 는 segments 의 index 기반이라 배치를 나눠도 유지됨)
+type batchItem struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+type batchPayload struct {
+	Segments []batchItem `json:"segments"`
+}
+
+func segmentID(i int) string {
+	return fmt.Sprintf("s%04d", i)
+}
+
+// Correct : segments 의 LLMCorrectSentence, LLMCorrectDiff 를 채움 (실패/거부된 문장은 비워둠)
+// 배치 하나가 실패해도 나머지는 계속 진행하고, 실패 내역은 Report 와 error 로 같이 알려줌
+func (c *Corrector) Correct(ctx context.Context, segments []subtitle.SubtitleSegment) (*Report, error) {
+	items := make([]batchItem, 0, len(segments))
+	index := make(map[string]int, len(segments))
+	for i, seg := range segments {
+		text := subtitle.NormalizeWhitespace(seg.Sentence)
+		if seg.Failed || text == "" {
+			continue
+		}
+		id := segmentID(i)
+		items = append(items, batchItem{ID: id, Text: text})
+		index[id] = i
+	}
+
+	report := &Report{Rejected: make([]Rejection, 0), FailedIDs: make([]string, 0)}
+	batches, err := c.batches(ctx, items, report)
+	if err != nil {
+		return report, err
+	}
+	report.Batches = len(batches)
+
+	var errs []error
+	for n, batch := range batches {
+		corrected, err := c.correctBatch(ctx, batch)
+		if err != nil {
+			log.Printf("[correction] batch %d/%d failed: %v\n", n+1, len(batches), err)
+			errs = append(errs, fmt.Errorf("batch %d: %w", n+1, err))
+			for _, item := range batch {
+				report.FailedIDs = append(report.FailedIDs, item.ID)
+			}
+			continue
+		}
+
+		for _, item := range batch {
+			seg := &segments[index[item.ID]]
+			text := subtitle.NormalizeWhitespace(corrected[item.ID])
+
+			ratio := EditRatio(item.Text, text)
+			if ratio > c.Config.MaxEditRatio {
+				report.Rejected = append(report.Rejected, Rejection{ID: item.ID, Original: item.Text, Corrected: text, EditRatio: ratio})
+				continue
+			}
+
+			seg.LLMCorrectSentence = text
+			seg.LLMCorrectDiff = WordDiff(item.Text, text)
+			if text == item.Text {
+				report.Unchanged++
+			} else {
+				report.Corrected++
+			}
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// batches : 전체를 한번에 세어보고 MaxBatchTokens 를 넘으면 반으로 나눠서 다시 셈
+func (c *Corrector) batches(ctx context.Context, items []batchItem, report *Report) ([][]batchItem, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	prompt, err := encodeBatch(items)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := c.Client.CountTokens(ctx, c.Config.Instruction, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokens <= c.Config.MaxBatchTokens || len(items) == 1 {
+		report.TokenCount += tokens
+		return [][]batchItem{items}, nil
+	}
+
+	half := len(items) / 2
+	left, err := c.batches(ctx, items[:half], report)
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.batches(ctx, items[half:], report)
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+// correctBatch : 요청 + 응답 검증, 검증 실패시 MaxRetry 만큼 재요청. id -> 교정 문장
+func (c *Corrector) correctBatch(ctx context.Context, batch []batchItem) (map[string]string, error) {
+	prompt, err := encodeBatch(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		raw, err := c.Client.Generate(ctx, c.Config.Instruction, prompt)
+		if err == nil {
+			var corrected map[string]string
+			if corrected, err = validateResponse(batch, raw); err == nil {
+				return corrected, nil
+			}
+		}
+
+		if attempt >= c.Config.MaxRetry || ctx.Err() != nil {
+			return nil, err
+		}
+		log.Printf("[correction] retry %d: %v\n", attempt+1, err)
+	}
+}
+
+func encodeBatch(items []batchItem) (string, error) {
+	data, err := json.Marshal(batchPayload{Segments: items})
+	if err != nil {
+		return "", fmt.Errorf("JSON marshal failed: %w", err)
+	}
+	return string(data), nil
+}
+
+var codeFence = regexp.MustCompile("(?s)```[^\\n]*\\n(.*?)\\n?```")
+
+// validateResponse : 개수, id 집합이 요청과 같은지 확인 (순서는 id 로 맞춤)
+func validateResponse(batch []batchItem, raw string) (map[string]string, error) {
+	s := strings.TrimSpace(raw)
+	if m := codeFence.FindStringSubmatch(s); m != nil {
+		s = m[1]
+	}
+
+	resp := batchPayload{}
+	if err := json.Unmarshal([]byte(s), &resp); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if len(resp.Segments) != len(batch) {
+		return nil, fmt.Errorf("%w: segment count %d, expected %d", ErrInvalidResponse, len(resp.Segments), len(batch))
+	}
+
+	corrected := make(map[string]string, len(batch))
+	for _, item := range resp.Segments {
+		if _, dup := corrected[item.ID]; dup {
+			return nil, fmt.Errorf("%w: duplicated id %s", ErrInvalidResponse, item.ID)
+		}
+		corrected[item.ID] = item.Text
+	}
+	for _, item := range batch {
+		if _, ok := corrected[item.ID]; !ok {
+			return nil, fmt.Errorf("%w: missing id %s", ErrInvalidResponse, item.ID)
+		}
+	}
+	return corrected, nil
+}
@@ -0,0 +1,97 @@
+package correction
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/stt/subtitle"
+)
+
+func TestCorrect(t *testing.T) {
+	segments := []subtitle.SubtitleSegment{
+		{Sentence: "welcome to culture wave", StartTime: 0, EndTime: 2},
+		{Sentence: "hello  world"},
+		{Sentence: "", Failed: true},
+		{Sentence: "this sentence is fine"},
+	}
+
+	client := &StubClient{Fn: func(text string) string {
+		text = strings.ReplaceAll(text, "culture wave", "Kulture Wave")
+		if text == "this sentence is fine" {
+			return "completely different meaning here" // 편집 예산 초과
+		}
+		return text
+	}}
+	config := DefaultConfig()
+	config.MaxBatchTokens = 20 // 배치 나누기
+
+	report, err := NewCorrector(client, config).Correct(context.Background(), segments)
+	require.NoError(t, err)
+
+	assert.Greater(t, report.Batches, 1)
+	assert.Equal(t, 1, report.Corrected)
+	assert.Equal(t, 1, report.Unchanged)
+	require.Len(t, report.Rejected, 1)
+	assert.Equal(t, "s0003", report.Rejected[0].ID)
+
+	assert.Equal(t, "welcome to Kulture Wave", segments[0].LLMCorrectSentence)
+	assert.Equal(t, "welcome to [-culture wave-] {+Kulture Wave+}", segments[0].LLMCorrectDiff)
+	assert.Equal(t, 2.0, segments[0].EndTime)
+	assert.Equal(t, "hello world", segments[1].LLMCorrectSentence)
+	assert.Empty(t, segments[1].LLMCorrectDiff)
+	assert.Empty(t, segments[2].LLMCorrectSentence)
+	assert.Empty(t, segments[3].LLMCorrectSentence)
+}
+
+// droppingClient : 첫 응답은 마지막 문장을 빼먹음
+type droppingClient struct {
+	StubClient
+	calls int
+}
+
+func (c *droppingClient) Generate(ctx context.Context, instruction, prompt string) (string, error) {
+	c.calls++
+	raw, err := c.StubClient.Generate(ctx, instruction, prompt)
+	if err != nil || c.calls > 1 {
+		return "```json\n" + raw + "\n```", err
+	}
+
+	resp := batchPayload{}
+	json.Unmarshal([]byte(raw), &resp)
+	resp.Segments = resp.Segments[:len(resp.Segments)-1]
+	data, _ := json.Marshal(resp)
+	return string(data), nil
+}
+
+func TestCorrectValidatesResponse(t *testing.T) {
+	segments := []subtitle.SubtitleSegment{{Sentence: "a b"}, {Sentence: "c d"}}
+
+	client := &droppingClient{}
+	report, err := NewCorrector(client, DefaultConfig()).Correct(context.Background(), segments)
+	require.NoError(t, err) // 재요청으로 성공
+	assert.Equal(t, 2, client.calls)
+	assert.Equal(t, 2, report.Unchanged)
+
+	config := DefaultConfig()
+	config.MaxRetry = 0
+	segments[0].LLMCorrectSentence, segments[1].LLMCorrectSentence = "", ""
+	report, err = NewCorrector(&droppingClient{}, config).Correct(context.Background(), segments)
+	assert.ErrorIs(t, err, ErrInvalidResponse)
+	assert.Equal(t, []string{"s0000", "s0001"}, report.FailedIDs)
+	assert.Empty(t, segments[0].LLMCorrectSentence)
+}
+
+func TestDiff(t *testing.T) {
+	assert.Equal(t, 1, EditDistance("wrld", "world"))
+	assert.Equal(t, 2, EditDistance("컬처", "쿨쳐"))
+	assert.InDelta(t, 0.2, EditRatio("wrld", "world"), 1e-9)
+
+	assert.Equal(t, "I [-wrld-] {+world+} ok", WordDiff("I wrld ok", "I world ok"))
+	assert.Equal(t, "a {+b+} c", WordDiff("a c", "a b c"))
+	assert.Empty(t, WordDiff("same text", "same  text"))
+}
@@ -0,0 +1,95 @@
+package correction
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// EditDistance : 글자(rune) 단위 Levenshtein 거리
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// EditRatio : 원문 길이 대비 편집 거리 (0 = 그대로, 1 = 전부 바뀜)
+func EditRatio(original, corrected string) float64 {
+	n := max(utf8.RuneCountInString(original), utf8.RuneCountInString(corrected))
+	if n == 0 {
+		return 0
+	}
+	return float64(EditDistance(original, corrected)) / float64(n)
+}
+
+// WordDiff : 단어 단위 diff, git --word-diff 형식 ("I [-wrld-]{+world+} ok")
+// 바뀐게 없으면 빈 문자열
+func WordDiff(original, corrected string) string {
+	a, b := strings.Fields(original), strings.Fields(corrected)
+
+	// LCS 테이블 (lcs[i][j] = a[i:], b[j:] 의 LCS 길이)
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	out := make([]string, 0, len(a)+len(b))
+	var removed, added []string
+	changed := false
+	flush := func() {
+		if len(removed) > 0 {
+			out = append(out, "[-"+strings.Join(removed, " ")+"-]")
+		}
+		if len(added) > 0 {
+			out = append(out, "{+"+strings.Join(added, " ")+"+}")
+		}
+		changed = changed || len(removed) > 0 || len(added) > 0
+		removed, added = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			flush()
+			out = append(out, a[i])
+			i, j = i+1, j+1
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			added = append(added, b[j])
+			j++
+		default:
+			removed = append(removed, a[i])
+			i++
+		}
+	}
+	flush()
+
+	if !changed {
+		return ""
+	}
+	return strings.Join(out, " ")
+}
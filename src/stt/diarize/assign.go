@@ -0,0 +1,75 @@
+package diarize
+
+import (
+	"math"
+
+	"example/stt/subtitle"
+)
+
+// AssignSpeakers : 화자 구간을 자막에 반영
+// 단어는 가장 많이 겹치는 화자, 문장은 단어 길이 기준 다수결 (단어가 없으면 문장 구간 자체로 판단)
+func AssignSpeakers(segments []subtitle.SubtitleSegment, turns []SpeakerSegment) {
+	if len(turns) == 0 {
+		return
+	}
+
+	for i := range segments {
+		seg := &segments[i]
+		if seg.Failed {
+			continue
+		}
+
+		if len(seg.SentenceFrames) == 0 {
+			seg.Speaker = speakerAt(turns, seg.StartTime, seg.EndTime)
+			continue
+		}
+
+		votes := make(map[string]float64)
+		for j := range seg.SentenceFrames {
+			frame := &seg.SentenceFrames[j]
+			frame.Speaker = speakerAt(turns, frame.WordStartTime, frame.WordEndTime)
+			if frame.Speaker != "" {
+				votes[frame.Speaker] += math.Max(frame.WordEndTime-frame.WordStartTime, 0.01)
+			}
+		}
+
+		seg.Speaker = ""
+		best := 0.0
+		for speaker, weight := range votes {
+			if weight > best || (weight == best && speaker < seg.Speaker) {
+				seg.Speaker, best = speaker, weight
+			}
+		}
+	}
+}
+
+// speakerAt : start~end 와 가장 많이 겹치는 화자. 겹치는게 없으면 가장 가까운 구간 화자
+func speakerAt(turns []SpeakerSegment, start, end float64) string {
+	overlap := make(map[string]float64)
+	for _, t := range turns {
+		o := math.Min(end, t.SpeechEndAt) - math.Max(start, t.SpeechStartAt)
+		if o > 0 {
+			overlap[t.Speaker] += o
+		}
+	}
+
+	speaker, best := "", 0.0
+	for s, o := range overlap {
+		if o > best || (o == best && s < speaker) {
+			speaker, best = s, o
+		}
+	}
+	if speaker != "" {
+		return speaker
+	}
+
+	// VAD 패딩 밖으로 나간 단어 : 가장 가까운 구간
+	bestDist := math.Inf(1)
+	for _, t := range turns {
+		dist := math.Max(t.SpeechStartAt-end, start-t.SpeechEndAt)
+		if dist < bestDist {
+			speaker, bestDist = t.Speaker, dist
+		}
+	}
+	return speaker
+}
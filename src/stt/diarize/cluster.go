@@ -0,0 +1,121 @@
+package diarize
+
+import "math"
+
+// Cluster : 임베딩을 코사인 유사도로 군집화해서 라벨 반환 (첫 등장 순서대로 0, 1, 2...)
+// 1시간 분량이면 윈도우가 수천개라 전체 쌍 비교 대신
+// 1. 순서대로 가장 가까운 중심에 붙이거나 새 군집 생성 (threshold 기준)
+// 2. 가장 가까운 군집 중심끼리 병합 (threshold 또는 numSpeakers 까지)
+// 3. 최종 중심 기준으로 다시 배정
+func Cluster(embeddings [][]float32, threshold float64, numSpeakers int) []int {
+	if len(embeddings) == 0 {
+		return []int{}
+	}
+
+	vectors := make([][]float64, len(embeddings))
+	for i, e := range embeddings {
+		vectors[i] = normalize(e)
+	}
+
+	// 1. online leader 군집화
+	centroids := make([][]float64, 0)
+	counts := make([]int, 0)
+	for _, v := range vectors {
+		best, bestSim := nearest(centroids, v)
+		if best >= 0 && bestSim >= threshold {
+			addTo(centroids[best], v)
+			counts[best]++
+			continue
+		}
+		c := make([]float64, len(v))
+		copy(c, v)
+		centroids = append(centroids, c)
+		counts = append(counts, 1)
+	}
+
+	// 2. 중심끼리 병합
+	for len(centroids) > 1 {
+		bi, bj, bestSim := -1, -1, math.Inf(-1)
+		for i := 0; i < len(centroids); i++ {
+			for j := i + 1; j < len(centroids); j++ {
+				sim := cosine(centroids[i], centroids[j])
+				if sim > bestSim {
+					bi, bj, bestSim = i, j, sim
+				}
+			}
+		}
+
+		if numSpeakers > 0 {
+			if len(centroids) <= numSpeakers {
+				break
+			}
+		} else if bestSim < threshold {
+			break
+		}
+
+		addTo(centroids[bi], centroids[bj])
+		counts[bi] += counts[bj]
+		centroids = append(centroids[:bj], centroids[bj+1:]...)
+		counts = append(counts[:bj], counts[bj+1:]...)
+	}
+
+	// 3. 재배정 + 첫 등장 순서로 라벨 재정렬
+	labels := make([]int, len(vectors))
+	remap := make(map[int]int)
+	for i, v := range vectors {
+		c, _ := nearest(centroids, v)
+		if _, ok := remap[c]; !ok {
+			remap[c] = len(remap)
+		}
+		labels[i] = remap[c]
+	}
+	return labels
+}
+
+// nearest : 코사인 유사도가 가장 높은 중심 인덱스 (없으면 -1)
+func nearest(centroids [][]float64, v []float64) (int, float64) {
+	best, bestSim := -1, math.Inf(-1)
+	for i, c := range centroids {
+		if sim := cosine(c, v); sim > bestSim {
+			best, bestSim = i, sim
+		}
+	}
+	return best, bestSim
+}
+
+// addTo : 중심은 합으로 들고 있음 (코사인 비교라 평균으로 나눌 필요 없음)
+func addTo(dst, src []float64) {
+	for i := range dst {
+		dst[i] += src[i]
+	}
+}
+
+func normalize(e []float32) []float64 {
+	v := make([]float64, len(e))
+	var norm float64
+	for i, x := range e {
+		v[i] = float64(x)
+		norm += v[i] * v[i]
+	}
+	norm = math.Sqrt(norm)
+	if norm == 0 {
+		return v
+	}
+	for i := range v {
+		v[i] /= norm
+	}
+	return v
+}
+
+func cosine(a, b []float64) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
@@ -0,0 +1,222 @@
+package diarize
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"example/stt/vad"
+)
+
+// SampleRate : 화자 임베딩 모델 입력 샘플레이트
+const SampleRate = 16000
+
+// SpeakerSegment : 화자 라벨이 붙은 발화 구간
+type SpeakerSegment struct {
+	vad.Segment
+	Speaker string `json:"speaker"`
+}
+
+// Diarizer : VAD 구간을 화자별로 나누는 단계. 구현체를 바꿔 끼울수 있도록 인터페이스로 둠
+type Diarizer interface {
+	Diarize(pcm []float32, sampleRate int, segments []vad.Segment) ([]SpeakerSegment, error)
+}
+
+// Embedder : 오디오 조각 -> 화자 임베딩 벡터
+type Embedder interface {
+	Embed(pcm []float32, sampleRate int) ([]float32, error)
+}
+
+// Config : 임베딩 + 군집화 파라미터
+type Config struct {
+	WindowSec   float64 // 긴 구간을 자를때 윈도우 길이
+	HopSec      float64 // 윈도우 이동 간격
+	MinEmbedSec float64 // 이보다 짧은 구간은 임베딩하지 않고 가까운 구간 화자를 따라감
+	Threshold   float64 // 코사인 유사도가 이 이상이면 같은 화자
+	NumSpeakers int     // 0 이면 Threshold 로 자동 결정, 지정하면 그 수까지 병합
+}
+
+// DefaultConfig : 강의/인터뷰(2~3명) 기준 값
+func DefaultConfig() Config {
+	return Config{
+		WindowSec:   2.0,
+		HopSec:      1.0,
+		MinEmbedSec: 0.5,
+		Threshold:   0.5,
+	}
+}
+
+// ClusterDiarizer : 윈도우별 임베딩을 뽑아서 코사인 유사도로 군집화하는 기본 Diarizer
+type ClusterDiarizer struct {
+	Embedder Embedder
+	Config   Config
+}
+
+// NewClusterDiarizer : embedder 는 onnx 패키지 구현체 등을 넘김
+func NewClusterDiarizer(embedder Embedder, config Config) *ClusterDiarizer {
+	return &ClusterDiarizer{Embedder: embedder, Config: config}
+}
+
+// window : 임베딩 단위 구간. segIdx 는 원래 VAD 구간 인덱스
+type window struct {
+	segIdx int
+	start  float64
+	end    float64
+	label  int // -1 : 임베딩 없음
+}
+
+// Diarize : VAD 구간을 윈도우로 자르고 -> 임베딩 -> 군집화 -> 같은 화자가 이어지는 윈도우를 다시 합침
+func (d *ClusterDiarizer) Diarize(pcm []float32, sampleRate int, segments []vad.Segment) ([]SpeakerSegment, error) {
+	if d.Embedder == nil {
+		return nil, fmt.Errorf("diarizer embedder is nil")
+	}
+	if len(segments) == 0 {
+		return []SpeakerSegment{}, nil
+	}
+
+	windows := splitWindows(segments, d.Config)
+
+	embeddings := make([][]float32, 0, len(windows))
+	embedded := make([]int, 0, len(windows)) // embeddings[i] 에 해당하는 windows 인덱스
+	for i, w := range windows {
+		if w.end-w.start < d.Config.MinEmbedSec {
+			continue
+		}
+
+		emb, err := d.Embedder.Embed(slicePCM(pcm, sampleRate, w.start, w.end), sampleRate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed %.2fs-%.2fs: %w", w.start, w.end, err)
+		}
+		embeddings = append(embeddings, emb)
+		embedded = append(embedded, i)
+	}
+
+	labels := Cluster(embeddings, d.Config.Threshold, d.Config.NumSpeakers)
+	for i, label := range labels {
+		windows[embedded[i]].label = label
+	}
+	fillUnlabeled(windows)
+
+	out := mergeWindows(windows)
+
+	log.Printf("[DEBUG] Diarization: segments=%d, windows=%d, embedded=%d, speakers=%d, turns=%d\n",
+		len(segments), len(windows), len(embeddings), countSpeakers(labels), len(out))
+
+	return out, nil
+}
+
+// splitWindows : WindowSec 의 1.5배 이하 구간은 통째로, 그 이상은 HopSec 간격으로 자름
+func splitWindows(segments []vad.Segment, config Config) []window {
+	windows := make([]window, 0, len(segments))
+	for i, seg := range segments {
+		if config.WindowSec <= 0 || config.HopSec <= 0 || seg.Duration() <= config.WindowSec*1.5 {
+			windows = append(windows, window{segIdx: i, start: seg.SpeechStartAt, end: seg.SpeechEndAt, label: -1})
+			continue
+		}
+
+		for st := seg.SpeechStartAt; st < seg.SpeechEndAt; st += config.HopSec {
+			en := math.Min(st+config.WindowSec, seg.SpeechEndAt)
+			// 마지막 조각이 너무 짧으면 앞 윈도우에 맡김
+			if en-st < config.WindowSec/2 && st > seg.SpeechStartAt {
+				break
+			}
+			windows = append(windows, window{segIdx: i, start: st, end: en, label: -1})
+			if en >= seg.SpeechEndAt {
+				break
+			}
+		}
+	}
+	return windows
+}
+
+// fillUnlabeled : 임베딩 못 뽑은 짧은 윈도우는 시간상 가장 가까운 라벨을 따라감
+func fillUnlabeled(windows []window) {
+	for i := range windows {
+		if windows[i].label >= 0 {
+			continue
+		}
+
+		best, bestDist := -1, math.Inf(1)
+		for j := range windows {
+			if windows[j].label < 0 {
+				continue
+			}
+			dist := math.Max(windows[j].start-windows[i].end, windows[i].start-windows[j].end)
+			if dist < bestDist {
+				best, bestDist = j, dist
+			}
+		}
+		if best >= 0 {
+			windows[i].label = windows[best].label
+		} else {
+			windows[i].label = 0 // 전부 짧으면 한명으로 봄
+		}
+	}
+}
+
+// mergeWindows : 같은 VAD 구간 안에서 같은 화자가 이어지면 합치고, 화자가 바뀌는 겹침 구간은 중간에서 자름
+func mergeWindows(windows []window) []SpeakerSegment {
+	out := make([]SpeakerSegment, 0, len(windows))
+	prevSeg, prevLabel := -1, -1
+
+	for _, w := range windows {
+		if w.segIdx == prevSeg && w.label == prevLabel {
+			last := &out[len(out)-1]
+			last.SpeechEndAt = math.Max(last.SpeechEndAt, w.end)
+			continue
+		}
+
+		start := w.start
+		if w.segIdx == prevSeg {
+			last := &out[len(out)-1]
+			mid := (w.start + last.SpeechEndAt) / 2
+			last.SpeechEndAt = mid
+			start = mid
+		}
+
+		out = append(out, SpeakerSegment{
+			Segment: vad.Segment{SpeechStartAt: start, SpeechEndAt: w.end},
+			Speaker: SpeakerLabel(w.label),
+		})
+		prevSeg, prevLabel = w.segIdx, w.label
+	}
+	return out
+}
+
+// SpeakerLabel : 군집 번호 -> SPEAKER_00 형태 라벨
+func SpeakerLabel(label int) string {
+	return fmt.Sprintf("SPEAKER_%02d", label)
+}
+
+// slicePCM : start~end(초) 구간 샘플
+func slicePCM(pcm []float32, sampleRate int, start, end float64) []float32 {
+	st := int(start * float64(sampleRate))
+	en := int(end * float64(sampleRate))
+	if st < 0 {
+		st = 0
+	}
+	if en > len(pcm) {
+		en = len(pcm)
+	}
+	if st >= en {
+		return []float32{}
+	}
+	return pcm[st:en]
+}
+
+func countSpeakers(labels []int) int {
+	seen := make(map[int]struct{})
+	for _, l := range labels {
+		seen[l] = struct{}{}
+	}
+	return len(seen)
+}
+
+// DiarizeFile : wav 파일을 16kHz 모노로 읽어서 Diarize 호출
+func DiarizeFile(d Diarizer, wavPath string, segments []vad.Segment) ([]SpeakerSegment, error) {
+	pcm, err := vad.ReadWavAs(wavPath, SampleRate, vad.DownMixConfig{})
+	if err != nil {
+		return nil, err
+	}
+	return d.Diarize(pcm, SampleRate, segments)
+}
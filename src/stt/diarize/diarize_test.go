@@ -0,0 +1,146 @@
+package diarize
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/stt/subtitle"
+	"example/stt/vad"
+)
+
+// signEmbedder : 평균이 양수면 A, 음수면 B 화자로 보는 가짜 임베딩
+type signEmbedder struct {
+	calls int
+}
+
+func (e *signEmbedder) Embed(pcm []float32, sampleRate int) ([]float32, error) {
+	e.calls++
+	var sum float32
+	for _, v := range pcm {
+		sum += v
+	}
+	if sum >= 0 {
+		return []float32{1, 0.1}, nil
+	}
+	return []float32{0.1, 1}, nil
+}
+
+// speakers : 구간별로 +0.1(A), -0.1(B) 를 채운 PCM
+func speakers(sr int, total float64, turns []SpeakerSegment) []float32 {
+	pcm := make([]float32, int(total*float64(sr)))
+	for _, t := range turns {
+		v := float32(0.1)
+		if t.Speaker == "B" {
+			v = -0.1
+		}
+		for i := int(t.SpeechStartAt * float64(sr)); i < int(t.SpeechEndAt*float64(sr)); i++ {
+			pcm[i] = v
+		}
+	}
+	return pcm
+}
+
+func TestClusterDiarizer(t *testing.T) {
+	sr := SampleRate
+	pcm := speakers(sr, 20, []SpeakerSegment{
+		{Segment: vad.Segment{SpeechStartAt: 0, SpeechEndAt: 2}, Speaker: "A"},
+		{Segment: vad.Segment{SpeechStartAt: 3, SpeechEndAt: 5}, Speaker: "B"},
+		{Segment: vad.Segment{SpeechStartAt: 6, SpeechEndAt: 10}, Speaker: "A"}, // 한 VAD 구간 안에서 화자 전환
+		{Segment: vad.Segment{SpeechStartAt: 10, SpeechEndAt: 14}, Speaker: "B"},
+		{Segment: vad.Segment{SpeechStartAt: 15, SpeechEndAt: 15.2}, Speaker: "B"}, // 임베딩 안하는 짧은 구간
+	})
+
+	segments := []vad.Segment{
+		{SpeechStartAt: 0, SpeechEndAt: 2},
+		{SpeechStartAt: 3, SpeechEndAt: 5},
+		{SpeechStartAt: 6, SpeechEndAt: 14},
+		{SpeechStartAt: 15, SpeechEndAt: 15.2},
+	}
+
+	embedder := &signEmbedder{}
+	out, err := NewClusterDiarizer(embedder, DefaultConfig()).Diarize(pcm, sr, segments)
+	require.NoError(t, err)
+
+	labels := make([]string, len(out))
+	for i, s := range out {
+		labels[i] = s.Speaker
+	}
+	assert.Equal(t, []string{"SPEAKER_00", "SPEAKER_01", "SPEAKER_00", "SPEAKER_01", "SPEAKER_01"}, labels)
+
+	// 구간 안 화자 전환 지점은 실제 전환(10초) 근처
+	assert.InDelta(t, 10.0, out[2].SpeechEndAt, 1.0)
+	assert.Equal(t, out[2].SpeechEndAt, out[3].SpeechStartAt)
+	assert.Equal(t, 14.0, out[3].SpeechEndAt)
+
+	_, err = (&ClusterDiarizer{}).Diarize(pcm, sr, segments)
+	assert.Error(t, err)
+}
+
+func TestCluster(t *testing.T) {
+	embs := [][]float32{{1, 0}, {0.9, 0.1}, {0, 1}, {0.1, 0.9}, {0.7, 0.7}}
+
+	assert.Equal(t, []int{0, 0, 1, 1, 2}, Cluster(embs, 0.95, 0))
+
+	labels := Cluster(embs, 0.95, 2)
+	assert.Equal(t, labels[0], labels[1])
+	assert.Equal(t, labels[2], labels[3])
+	assert.NotEqual(t, labels[0], labels[2])
+
+	assert.Empty(t, Cluster(nil, 0.5, 0))
+}
+
+func TestAssignSpeakers(t *testing.T) {
+	turns := []SpeakerSegment{
+		{Segment: vad.Segment{SpeechStartAt: 0, SpeechEndAt: 5}, Speaker: "SPEAKER_00"},
+		{Segment: vad.Segment{SpeechStartAt: 5, SpeechEndAt: 10}, Speaker: "SPEAKER_01"},
+	}
+
+	segs := []subtitle.SubtitleSegment{
+		{StartTime: 3, EndTime: 8, SentenceFrames: []subtitle.SentenceFrames{
+			{Word: "a", WordStartTime: 3, WordEndTime: 4.9},
+			{Word: "b", WordStartTime: 5.1, WordEndTime: 5.5},
+		}},
+		{StartTime: 8, EndTime: 9},
+		{StartTime: 11, EndTime: 12}, // 구간 밖 : 가장 가까운 화자
+		{StartTime: 0, EndTime: 10, Failed: true},
+	}
+
+	AssignSpeakers(segs, turns)
+	assert.Equal(t, "SPEAKER_00", segs[0].Speaker)
+	assert.Equal(t, "SPEAKER_00", segs[0].SentenceFrames[0].Speaker)
+	assert.Equal(t, "SPEAKER_01", segs[0].SentenceFrames[1].Speaker)
+	assert.Equal(t, "SPEAKER_01", segs[1].Speaker)
+	assert.Equal(t, "SPEAKER_01", segs[2].Speaker)
+	assert.Empty(t, segs[3].Speaker)
+}
+
+func TestFbank(t *testing.T) {
+	sr := SampleRate
+	pcm := make([]float32, sr)
+	for i := range pcm {
+		pcm[i] = float32(0.3 * math.Sin(2*math.Pi*1000*float64(i)/float64(sr)))
+	}
+
+	config := DefaultFbankConfig()
+	config.SubtractMean = false
+	feats := Fbank(pcm, sr, config)
+	require.Len(t, feats, 98) // 1 + (16000-400)/160
+	require.Len(t, feats[0], 80)
+
+	// 1kHz 가 들어간 mel bin 이 가장 큼
+	peak := 0
+	for m, v := range feats[50] {
+		if v > feats[50][peak] {
+			peak = m
+		}
+	}
+	center := melScale(1000)
+	low, high := melScale(20), melScale(8000)
+	expected := int((center-low)/((high-low)/81)) - 1
+	assert.InDelta(t, expected, peak, 1)
+
+	assert.Empty(t, Fbank(pcm[:100], sr, config))
+}
@@ -0,0 +1,203 @@
+package diarize
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// FbankConfig : Kaldi 호환 log mel filterbank 파라미터 (WeSpeaker 계열 모델 입력)
+type FbankConfig struct {
+	NumMelBins   int
+	FrameMs      float64
+	ShiftMs      float64
+	PreEmphasis  float64
+	LowFreq      float64
+	Scale        float64 // 입력 스케일. Kaldi 는 int16 범위를 기대해서 32768
+	SubtractMean bool    // 발화 단위 CMN
+}
+
+// DefaultFbankConfig : 80 mel, 25ms/10ms
+func DefaultFbankConfig() FbankConfig {
+	return FbankConfig{
+		NumMelBins:   80,
+		FrameMs:      25,
+		ShiftMs:      10,
+		PreEmphasis:  0.97,
+		LowFreq:      20,
+		Scale:        32768,
+		SubtractMean: true,
+	}
+}
+
+// Fbank : [프레임][mel] 특징 계산. 한 프레임도 안나오면 빈 슬라이스
+func Fbank(pcm []float32, sampleRate int, config FbankConfig) [][]float32 {
+	frameLen := int(float64(sampleRate) * config.FrameMs / 1000.0)
+	shift := int(float64(sampleRate) * config.ShiftMs / 1000.0)
+	if frameLen <= 0 || shift <= 0 || len(pcm) < frameLen {
+		return [][]float32{}
+	}
+
+	fftSize := 1
+	for fftSize < frameLen {
+		fftSize <<= 1
+	}
+
+	window := poveyWindow(frameLen)
+	filters := melFilterBank(config.NumMelBins, fftSize, sampleRate, config.LowFreq)
+
+	numFrames := 1 + (len(pcm)-frameLen)/shift
+	feats := make([][]float32, numFrames)
+	frame := make([]float64, frameLen)
+	buf := make([]complex128, fftSize)
+
+	for f := 0; f < numFrames; f++ {
+		offset := f * shift
+
+		// DC 제거
+		var mean float64
+		for i := 0; i < frameLen; i++ {
+			frame[i] = float64(pcm[offset+i]) * config.Scale
+			mean += frame[i]
+		}
+		mean /= float64(frameLen)
+
+		for i := 0; i < frameLen; i++ {
+			frame[i] -= mean
+		}
+
+		// pre-emphasis (뒤에서부터, 첫 샘플은 자기 자신 기준)
+		for i := frameLen - 1; i > 0; i-- {
+			frame[i] -= config.PreEmphasis * frame[i-1]
+		}
+		frame[0] -= config.PreEmphasis * frame[0]
+
+		for i := range buf {
+			if i < frameLen {
+				buf[i] = complex(frame[i]*window[i], 0)
+			} else {
+				buf[i] = 0
+			}
+		}
+		fft(buf)
+
+		power := make([]float64, fftSize/2+1)
+		for i := range power {
+			a := cmplx.Abs(buf[i])
+			power[i] = a * a
+		}
+
+		feat := make([]float32, config.NumMelBins)
+		for m, filter := range filters {
+			var energy float64
+			for _, w := range filter {
+				energy += power[w.bin] * w.weight
+			}
+			feat[m] = float32(math.Log(math.Max(energy, math.SmallestNonzeroFloat32)))
+		}
+		feats[f] = feat
+	}
+
+	if config.SubtractMean {
+		subtractMean(feats)
+	}
+	return feats
+}
+
+// poveyWindow : Kaldi 기본 윈도우 (hann^0.85)
+func poveyWindow(n int) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = math.Pow(0.5-0.5*math.Cos(2*math.Pi*float64(i)/float64(n-1)), 0.85)
+	}
+	return w
+}
+
+type melWeight struct {
+	bin    int
+	weight float64
+}
+
+func melScale(hz float64) float64 {
+	return 1127.0 * math.Log(1+hz/700.0)
+}
+
+// melFilterBank : 삼각 필터 (mel 축에서 등간격)
+func melFilterBank(numBins, fftSize, sampleRate int, lowFreq float64) [][]melWeight {
+	nyquist := float64(sampleRate) / 2
+	melLow := melScale(lowFreq)
+	melHigh := melScale(nyquist)
+	delta := (melHigh - melLow) / float64(numBins+1)
+
+	filters := make([][]melWeight, numBins)
+	for m := 0; m < numBins; m++ {
+		left := melLow + float64(m)*delta
+		center := left + delta
+		right := center + delta
+
+		for k := 0; k <= fftSize/2; k++ {
+			mel := melScale(float64(k) * float64(sampleRate) / float64(fftSize))
+			if mel <= left || mel >= right {
+				continue
+			}
+			var weight float64
+			if mel <= center {
+				weight = (mel - left) / (center - left)
+			} else {
+				weight = (right - mel) / (right - center)
+			}
+			filters[m] = append(filters[m], melWeight{bin: k, weight: weight})
+		}
+	}
+	return filters
+}
+
+func subtractMean(feats [][]float32) {
+	if len(feats) == 0 {
+		return
+	}
+	dims := len(feats[0])
+	mean := make([]float64, dims)
+	for _, f := range feats {
+		for d, v := range f {
+			mean[d] += float64(v)
+		}
+	}
+	for d := range mean {
+		mean[d] /= float64(len(feats))
+	}
+	for _, f := range feats {
+		for d := range f {
+			f[d] -= float32(mean[d])
+		}
+	}
+}
+
+// fft : in-place radix-2 FFT (len 은 2의 거듭제곱)
+func fft(a []complex128) {
+	n := len(a)
+
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				u := a[start+k]
+				v := a[start+k+size/2] * w
+				a[start+k] = u + v
+				a[start+k+size/2] = u - v
+				w *= step
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package onnx
+
+import (
+	"fmt"
+	"sync"
+
+	ort "github.com/yalue/onnxruntime_go"
+
+	"example/stt/diarize"
+	"example/stt/vad"
+)
+
+// EmbedderConfig : 화자 임베딩 ONNX 모델 설정 (기본값은 WeSpeaker ResNet 계열 export 기준)
+type EmbedderConfig struct {
+	ModelPath   string
+	LibraryPath string // 비어있으면 OS 별 기본 경로
+	InputName   string // [1, frames, mel] fbank
+	OutputName  string // [1, dim] 임베딩
+	Fbank       diarize.FbankConfig
+}
+
+// DefaultEmbedderConfig : modelPath 만 지정하면 되도록
+func DefaultEmbedderConfig(modelPath string) EmbedderConfig {
+	return EmbedderConfig{
+		ModelPath:  modelPath,
+		InputName:  "feats",
+		OutputName: "embs",
+		Fbank:      diarize.DefaultFbankConfig(),
+	}
+}
+
+var (
+	initOnce sync.Once
+	initErr  error
+)
+
+// initEnvironment : onnxruntime 환경은 프로세스에 한번만 생성
+func initEnvironment(libraryPath string) error {
+	initOnce.Do(func() {
+		if libraryPath == "" {
+			libraryPath = defaultLibraryPath
+		}
+		ort.SetSharedLibraryPath(libraryPath)
+		if err := ort.InitializeEnvironment(); err != nil {
+			initErr = fmt.Errorf("failed to initialize onnxruntime: %v", err)
+		}
+	})
+	return initErr
+}
+
+// Embedder : diarize.Embedder 의 ONNX 구현체. 세션은 동시 호출에 안전하지 않아서 mutex 로 막음
+type Embedder struct {
+	mu      sync.Mutex
+	session *ort.DynamicAdvancedSession
+	config  EmbedderConfig
+}
+
+// NewEmbedder : 사용 후 Destroy 필요
+func NewEmbedder(config EmbedderConfig) (*Embedder, error) {
+	if config.ModelPath == "" {
+		return nil, fmt.Errorf("embedding model path is empty")
+	}
+
+	if err := initEnvironment(config.LibraryPath); err != nil {
+		return nil, err
+	}
+
+	session, err := ort.NewDynamicAdvancedSession(config.ModelPath,
+		[]string{config.InputName}, []string{config.OutputName}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedding session: %v", err)
+	}
+
+	return &Embedder{session: session, config: config}, nil
+}
+
+// Embed : PCM -> fbank -> 모델 추론. 16kHz 가 아니면 리샘플링 후 처리
+func (e *Embedder) Embed(pcm []float32, sampleRate int) ([]float32, error) {
+	if sampleRate != diarize.SampleRate {
+		resampled, err := vad.Resample(pcm, sampleRate, diarize.SampleRate)
+		if err != nil {
+			return nil, err
+		}
+		pcm = resampled
+	}
+
+	feats := diarize.Fbank(pcm, diarize.SampleRate, e.config.Fbank)
+	if len(feats) == 0 {
+		return nil, fmt.Errorf("audio too short for embedding: %d samples", len(pcm))
+	}
+
+	numMel := len(feats[0])
+	flat := make([]float32, 0, len(feats)*numMel)
+	for _, f := range feats {
+		flat = append(flat, f...)
+	}
+
+	input, err := ort.NewTensor(ort.NewShape(1, int64(len(feats)), int64(numMel)), flat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create input tensor: %v", err)
+	}
+	defer input.Destroy()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	outputs := []ort.ArbitraryTensor{nil}
+	if err = e.session.Run([]ort.ArbitraryTensor{input}, outputs); err != nil {
+		return nil, fmt.Errorf("failed to run embedding model: %v", err)
+	}
+	defer outputs[0].Destroy()
+
+	output, ok := outputs[0].(*ort.Tensor[float32])
+	if !ok {
+		return nil, fmt.Errorf("unexpected embedding output type: %T", outputs[0])
+	}
+
+	embedding := make([]float32, len(output.GetData()))
+	copy(embedding, output.GetData())
+	return embedding, nil
+}
+
+func (e *Embedder) Destroy() error {
+	return e.session.Destroy()
+}
@@ -0,0 +1,6 @@
+//go:build darwin
+
+package onnx
+
+// defaultLibraryPath : silero 와 같은 onnxruntime 설치 경로 사용
+const defaultLibraryPath = "/usr/local/onnxruntime-osx-arm64-1.18.1/lib/libonnxruntime.dylib"
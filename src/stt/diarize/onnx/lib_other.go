@@ -0,0 +1,6 @@
+//go:build !darwin
+
+package onnx
+
+// defaultLibraryPath : 시스템 라이브러리 경로(LD_LIBRARY_PATH)에서 찾음
+const defaultLibraryPath = "libonnxruntime.so"
@@ -0,0 +1,297 @@
+package dictionary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"example/stt/subtitle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func build(t *testing.T, layers ...*Dict) *WordReplacementTrie {
+	t.Helper()
+	trie := NewWordReplacementTrie()
+	require.NoError(t, trie.BuildTrie(layers...))
+	return trie
+}
+
+func TestReplaceWords(t *testing.T) {
+	trie := build(t, &Dict{
+		Version: "v1",
+		Entries: []Entry{
+			{Word: "Kulture Wave", Patterns: []Pattern{{Value: "Culture Wave"}}},
+			{Word: "world", Patterns: []Pattern{{Value: "wrld"}}},
+			{Word: "Go", Patterns: []Pattern{{Value: "golang"}}},
+			{Word: "IT", Patterns: []Pattern{{Value: "IT", Flags: "c"}}},
+			{Word: "컬처웨이브", Patterns: []Pattern{{Value: "컬처 웨이브"}}},
+			{Word: "don't", Patterns: []Pattern{{Value: "dont"}}},
+			{Word: "$1 kg", Patterns: []Pattern{{Type: PatternTypeRegex, Value: `\b(\d+)\s*kilograms?\b`, Flags: "i"}}},
+		},
+	})
+
+	cases := map[string]string{
+		"welcome to culture wave!":   "welcome to Kulture Wave!",
+		"CULTURE   WAVE":             "Kulture Wave",
+		"culture, wave":              "culture, wave", // 구분자가 다르면 다른 표현
+		"the wrld":                   "the world",
+		"WRLD and Wrld":              "WORLD and World", // 대소문자 유지
+		"golang":                     "Go",
+		"it is IT":                   "it is IT",    // 대소문자 구분 패턴
+		"오늘 컬처 웨이브에서":                "오늘 컬처 웨이브에서", // 토큰이 달라서 매칭 안됨
+		"오늘 컬처 웨이브 공연":               "오늘 컬처웨이브 공연",
+		"I dont know":                "I don't know",
+		"lost 5 Kilograms in a week": "lost 5 kg in a week",
+		"goodwrld":                   "goodwrld", // 단어 중간은 치환 안함
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, trie.ReplaceWords(input), input)
+	}
+
+	replacement, ok := trie.Search("culture  wave")
+	assert.True(t, ok)
+	assert.Equal(t, "Kulture Wave", replacement)
+	_, ok = trie.Search("culture")
+	assert.False(t, ok)
+}
+
+func TestAhoCorasickOverlap(t *testing.T) {
+	trie := build(t, &Dict{Entries: []Entry{
+		{Word: "X", Patterns: []Pattern{{Value: "a b c d"}}},
+		{Word: "Y", Patterns: []Pattern{{Value: "b c"}}},
+		{Word: "Z", Patterns: []Pattern{{Value: "c x"}}},
+	}})
+
+	// "a b c" 까지 가다가 실패 -> fail 링크로 "b c" 출력
+	assert.Equal(t, "a Y x", trie.ReplaceWords("a b c x"))
+	// 긴 패턴 우선
+	assert.Equal(t, "X", trie.ReplaceWords("a b c d"))
+
+	found := trie.FindReplacements("z b c")
+	require.Len(t, found, 1)
+	assert.Equal(t, Replacement{Start: 2, End: 5, Original: "b c", Replacement: "Y"}, found[0])
+}
+
+func TestLayeredDictionaries(t *testing.T) {
+	global := &Dict{Version: "global-3", Entries: []Entry{
+		{Word: "Culture Wave", Patterns: []Pattern{{Value: "culture wav"}}},
+		{Word: "hello", Patterns: []Pattern{{Value: "helo"}}},
+	}}
+	customer := &Dict{Version: "arirang-7", Entries: []Entry{
+		{Word: "Kulture Wave", Patterns: []Pattern{{Value: "culture wav"}, {Value: "culture wave"}}},
+	}}
+
+	trie := build(t, global, customer)
+	assert.Equal(t, []string{"global-3", "arirang-7"}, trie.Versions)
+	assert.Equal(t, "Kulture Wave, hello", trie.ReplaceWords("culture wav, helo"))
+
+	// 고객사 사전 없으면 전역만
+	assert.Equal(t, "Culture Wave", build(t, global).ReplaceWords("culture wav"))
+}
+
+func TestDictValidate(t *testing.T) {
+	dict := &Dict{Entries: []Entry{
+		{Word: "ok", Patterns: []Pattern{{Value: "fine"}, {Type: PatternTypeRegex, Value: `fi+ne`, Flags: "i"}}},
+		{Word: "", Patterns: []Pattern{{Value: "x"}}},
+		{Word: "bad", Patterns: []Pattern{{Type: "glob", Value: "*"}}},
+		{Word: "bad", Patterns: []Pattern{{Type: PatternTypeRegex, Value: `(`}}},
+		{Word: "bad", Patterns: []Pattern{{Type: PatternTypeRegex, Value: `a*`}}},
+		{Word: "bad", Patterns: []Pattern{{Value: "x", Flags: "i"}}},
+	}}
+
+	err := dict.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "entries[1]: empty word")
+	assert.Contains(t, err.Error(), "required type glob not supported")
+	assert.Contains(t, err.Error(), "invalid regex")
+	assert.Contains(t, err.Error(), "matches empty string")
+	assert.Contains(t, err.Error(), `flag 'i' not supported for literal pattern`)
+	assert.NotContains(t, err.Error(), "entries[0]")
+
+	assert.Error(t, NewWordReplacementTrie().BuildTrie(dict))
+}
+
+func TestRegistryHotReload(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, store.Save(ctx, GlobalDictionary, &Dict{Version: "g1", Entries: []Entry{
+		{Word: "world", Patterns: []Pattern{{Value: "wrld"}}},
+	}}))
+	require.NoError(t, store.Save(ctx, "arirang", &Dict{Version: "a1", Entries: []Entry{
+		{Word: "Kulture Wave", Patterns: []Pattern{{Value: "culture wave"}}},
+	}}))
+
+	registry := NewRegistry(store)
+	changed, err := registry.Reload(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{GlobalDictionary, "arirang"}, changed)
+
+	before := registry.Get("arirang")
+	assert.Equal(t, "Kulture Wave world", before.ReplaceWords("culture wave wrld"))
+	assert.Equal(t, "culture wave world", registry.Get("unknown").ReplaceWords("culture wave wrld")) // 전역으로 fallback
+
+	// 버전 안바뀌면 그대로
+	changed, err = registry.Reload(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+	assert.Same(t, before, registry.Get("arirang"))
+
+	// 같은 버전으로 저장 불가, 잘못된 사전 저장 불가
+	err = registry.Save(ctx, "arirang", &Dict{Version: "a1", Entries: []Entry{{Word: "x", Patterns: []Pattern{{Value: "y"}}}}})
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	err = registry.Save(ctx, "arirang", &Dict{Entries: []Entry{{Word: "x"}}})
+	assert.ErrorIs(t, err, ErrInvalidDict)
+
+	updated, err := registry.Update(ctx, "arirang", func(dict *Dict) error {
+		dict.UpsertEntry(Entry{Word: "KWave", Patterns: []Pattern{{Value: "culture wave"}}})
+		dict.RemoveEntry("Kulture Wave")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, "a1", updated.Version)
+
+	// 이전 트라이를 들고 있던 쪽은 영향 없음
+	assert.Equal(t, "Kulture Wave", before.ReplaceWords("culture wave"))
+	assert.Equal(t, "KWave", registry.Get("arirang").ReplaceWords("culture wave"))
+	assert.Same(t, registry.Get(GlobalDictionary), registry.Get(GlobalDictionary))
+}
+
+// 테스트 헬퍼: broken 이름만 Load 실패하는 저장소
+type flakyStore struct {
+	Store
+	broken string
+}
+
+func (s *flakyStore) Load(ctx context.Context, name string) (*Dict, error) {
+	if name == s.broken {
+		return nil, errors.New("temporary failure")
+	}
+	return s.Store.Load(ctx, name)
+}
+
+// 사전 하나를 못 읽어도 기존 트라이 유지, 다른 사전 저장은 성공
+func TestRegistryReloadFailure(t *testing.T) {
+	ctx := context.Background()
+	files, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, files.Save(ctx, "arirang", &Dict{Version: "a1", Entries: []Entry{
+		{Word: "Kulture Wave", Patterns: []Pattern{{Value: "culture wave"}}},
+	}}))
+
+	store := &flakyStore{Store: files}
+	registry := NewRegistry(store)
+	_, err = registry.Reload(ctx)
+	require.NoError(t, err)
+	before := registry.Get("arirang")
+
+	store.broken = "arirang"
+	_, err = registry.Reload(ctx)
+	assert.Error(t, err)
+	assert.Same(t, before, registry.Get("arirang"))
+
+	err = registry.Save(ctx, "other", &Dict{Entries: []Entry{{Word: "x", Patterns: []Pattern{{Value: "y"}}}}})
+	assert.NoError(t, err)
+	assert.Same(t, before, registry.Get("arirang"))
+	assert.Equal(t, "x", registry.Get("other").ReplaceWords("y"))
+}
+
+// 동시에 Update 해도 서로의 수정을 덮어쓰지 않음
+func TestRegistryConcurrentUpdate(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	registry := NewRegistry(store)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := registry.Update(ctx, "arirang", func(dict *Dict) error {
+				dict.UpsertEntry(Entry{Word: fmt.Sprintf("word%d", i), Patterns: []Pattern{{Value: fmt.Sprintf("pattern%d", i)}}})
+				return nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	dict, err := registry.Load(ctx, "arirang")
+	require.NoError(t, err)
+	assert.Len(t, dict.Entries, 10)
+}
+
+func TestRegistryDryRun(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	registry := NewRegistry(store)
+	require.NoError(t, registry.Save(ctx, "arirang", &Dict{Version: "a1", Entries: []Entry{
+		{Word: "world", Patterns: []Pattern{{Value: "wrld"}}},
+	}}))
+
+	proposed := &Dict{Version: "a2", Entries: []Entry{
+		{Word: "world", Patterns: []Pattern{{Value: "wrld"}}},
+		{Word: "Kulture Wave", Patterns: []Pattern{{Value: "culture wave"}}},
+	}}
+	results, err := registry.DryRun(ctx, "arirang", proposed, []string{"hello wrld", "culture wave wrld"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.False(t, results[0].Changed)
+	assert.True(t, results[1].Changed)
+	assert.Equal(t, "culture wave world", results[1].Current)
+	assert.Equal(t, "Kulture Wave world", results[1].Proposed)
+	assert.Len(t, results[1].Replacements, 2)
+
+	// dry-run 은 저장 안함
+	dict, err := store.Load(ctx, "arirang")
+	require.NoError(t, err)
+	assert.Equal(t, "a1", dict.Version)
+}
+
+func TestReplaceSegment(t *testing.T) {
+	trie := build(t, &Dict{Entries: []Entry{
+		{Word: "Kulture Wave", Patterns: []Pattern{{Value: "culture wave"}}},
+		{Word: "KWave", Patterns: []Pattern{{Value: "k wave"}}},
+		{Word: "New York", Patterns: []Pattern{{Value: "newyork"}}},
+	}})
+
+	frame := func(word string, start, end float64) subtitle.SentenceFrames {
+		return subtitle.SentenceFrames{Word: word, WordStartTime: start, WordEndTime: end, ConfidenceScore: 0.8}
+	}
+	seg := subtitle.SubtitleSegment{
+		Sentence: "culture wave, k wave in newyork!",
+		SentenceFrames: []subtitle.SentenceFrames{
+			frame(" culture", 0, 0.5), frame(" wave,", 0.5, 1),
+			frame(" k", 1, 1.2), frame(" wave", 1.2, 2),
+			frame(" in", 2, 2.2), frame(" newyork!", 2.2, 3),
+		},
+	}
+
+	replaced := trie.ReplaceSegment(seg)
+	assert.Equal(t, "Kulture Wave, KWave in New York!", replaced.Sentence)
+
+	words := make([]string, 0)
+	for i, f := range replaced.SentenceFrames {
+		assert.Equal(t, i, f.WordIdx)
+		words = append(words, f.Word)
+	}
+	assert.Equal(t, []string{" Kulture", " Wave,", " KWave", " in", " New", " York!"}, words)
+
+	frames := replaced.SentenceFrames
+	assert.Equal(t, 0.5, frames[1].WordStartTime) // 1:1 은 타이밍 유지
+	assert.Equal(t, 1.0, frames[2].WordStartTime) // 합침
+	assert.Equal(t, 2.0, frames[2].WordEndTime)
+	assert.InDelta(t, 2.2+0.8*3/8, frames[4].WordEndTime, 1e-9) // 나눔 (글자 수 비율)
+	assert.Equal(t, 3.0, frames[5].WordEndTime)
+	assert.Equal(t, 0.8, frames[4].ConfidenceScore)
+
+	// 원본은 그대로
+	assert.Equal(t, " culture", seg.SentenceFrames[0].Word)
+}
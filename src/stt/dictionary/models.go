@@ -0,0 +1,144 @@
+package dictionary
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type Dict struct {
+	Version string  `json:"version"`
+	Entries []Entry `json:"entries"`
+}
+
+type Entry struct {
+	Word     string    `json:"word"`
+	Patterns []Pattern `json:"patterns"`
+}
+
+// Pattern : 치환 대상 표현
+// literal : 공백으로 구분된 여러 단어 가능 ("Culture Wave"), 기본은 대소문자 무시 (Flags "c" 면 구분)
+// regex   : Value 를 RE2 정규식으로 컴파일, Flags 는 i(대소문자 무시), m(멀티라인), s(. 이 개행 포함), U(non-greedy)
+// Entry.Word 에 $1 같은 캡처 그룹 참조 가능
+type Pattern struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	Flags string `json:"flags,omitempty"` // e.g., "i"
+}
+
+const (
+	PatternTypeLiteral = "literal"
+	PatternTypeRegex   = "regex"
+)
+
+const (
+	literalFlags = "c"
+	regexFlags   = "imsU"
+)
+
+func (v *Pattern) RequiredType(patternType string) error {
+	if patternType != PatternTypeRegex && patternType != PatternTypeLiteral {
+		return fmt.Errorf("required type %s not supported", patternType)
+	}
+
+	return nil
+}
+
+// kind : Type 이 비어있으면 literal (기존 사전 데이터 호환)
+func (v *Pattern) kind() string {
+	if v.Type == "" {
+		return PatternTypeLiteral
+	}
+	return v.Type
+}
+
+// caseSensitive : literal 패턴의 대소문자 구분 여부
+func (v *Pattern) caseSensitive() bool {
+	return strings.Contains(v.Flags, "c")
+}
+
+// Validate : 타입, 플래그, 정규식 문법 체크
+func (v *Pattern) Validate() error {
+	if err := v.RequiredType(v.kind()); err != nil {
+		return err
+	}
+	if strings.TrimSpace(v.Value) == "" {
+		return fmt.Errorf("empty pattern value")
+	}
+
+	allowed := literalFlags
+	if v.kind() == PatternTypeRegex {
+		allowed = regexFlags
+	}
+	for _, f := range v.Flags {
+		if !strings.ContainsRune(allowed, f) {
+			return fmt.Errorf("flag %q not supported for %s pattern", f, v.kind())
+		}
+	}
+
+	if v.kind() == PatternTypeRegex {
+		if _, err := v.compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compile : regex 패턴 + 플래그 -> Regexp
+func (v *Pattern) compile() (*regexp.Regexp, error) {
+	expr := v.Value
+	if v.Flags != "" {
+		expr = "(?" + v.Flags + ")" + expr
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %q: %w", v.Value, err)
+	}
+	if re.MatchString("") {
+		return nil, fmt.Errorf("regex %q matches empty string", v.Value)
+	}
+	return re, nil
+}
+
+// Validate : 모든 엔트리 검사 (에러는 전부 모아서 반환)
+func (d *Dict) Validate() error {
+	var errs []error
+	for i, entry := range d.Entries {
+		if strings.TrimSpace(entry.Word) == "" {
+			errs = append(errs, fmt.Errorf("entries[%d]: empty word", i))
+		}
+		if len(entry.Patterns) == 0 {
+			errs = append(errs, fmt.Errorf("entries[%d] %q: no patterns", i, entry.Word))
+		}
+		for j, pattern := range entry.Patterns {
+			if err := pattern.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("entries[%d] %q patterns[%d]: %w", i, entry.Word, j, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// UpsertEntry : 같은 Word 엔트리가 있으면 교체, 없으면 추가
+func (d *Dict) UpsertEntry(entry Entry) {
+	for i := range d.Entries {
+		if d.Entries[i].Word == entry.Word {
+			d.Entries[i] = entry
+			return
+		}
+	}
+	d.Entries = append(d.Entries, entry)
+}
+
+// RemoveEntry : Word 엔트리 삭제, 없으면 false
+func (d *Dict) RemoveEntry(word string) bool {
+	for i := range d.Entries {
+		if d.Entries[i].Word == word {
+			d.Entries = append(d.Entries[:i], d.Entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,316 @@
+package dictionary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Registry : 고객사별로 빌드된 트라이 (전역 + 고객사 레이어)
+// 읽기는 lock 없이 스냅샷을 보고, 버전이 바뀌면 새 스냅샷을 만들어서 통째로 교체함
+type Registry struct {
+	store Store
+
+	mu       sync.Mutex // Reload / Save / Update 직렬화
+	snapshot atomic.Pointer[map[string]*WordReplacementTrie]
+}
+
+func NewRegistry(store Store) *Registry {
+	r := &Registry{store: store}
+	empty := make(map[string]*WordReplacementTrie)
+	r.snapshot.Store(&empty)
+	return r
+}
+
+// Get : 고객사 트라이. 고객사 사전이 없으면 전역, 전역도 없으면 빈 트라이
+func (r *Registry) Get(name string) *WordReplacementTrie {
+	tries := *r.snapshot.Load()
+	if trie, ok := tries[name]; ok {
+		return trie
+	}
+	if trie, ok := tries[GlobalDictionary]; ok {
+		return trie
+	}
+	return NewWordReplacementTrie()
+}
+
+// Versions : 고객사별 현재 적용중인 버전 (레이어 순서)
+func (r *Registry) Versions() map[string][]string {
+	tries := *r.snapshot.Load()
+	versions := make(map[string][]string, len(tries))
+	for name, trie := range tries {
+		versions[name] = trie.Versions
+	}
+	return versions
+}
+
+// Reload : 저장소에서 전부 읽어서 버전이 바뀐 사전만 다시 빌드 후 교체. 바뀐 이름 목록 반환
+// 빌드 실패한 사전은 기존 트라이를 유지하고 에러로 알려줌
+func (r *Registry) Reload(ctx context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed, failed, err := r.reload(ctx)
+	if err != nil {
+		return changed, err
+	}
+
+	errs := make([]error, 0, len(failed))
+	for _, name := range slices.Sorted(maps.Keys(failed)) {
+		errs = append(errs, failed[name])
+	}
+	return changed, errors.Join(errs...)
+}
+
+// reload : 사전별 실패는 failed 로 (그 사전은 기존 트라이 유지), 목록 / 전역 사전을 못 읽으면 err
+func (r *Registry) reload(ctx context.Context) ([]string, map[string]error, error) {
+	names, err := r.store.List(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list dictionaries failed: %w", err)
+	}
+
+	global, err := r.store.Load(ctx, GlobalDictionary)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, nil, err
+	}
+
+	current := *r.snapshot.Load()
+	next := make(map[string]*WordReplacementTrie, len(names))
+	changed := make([]string, 0)
+	failed := make(map[string]error)
+
+	for _, name := range names {
+		layers := []*Dict{global}
+		if name != GlobalDictionary {
+			dict, err := r.store.Load(ctx, name)
+			if err != nil {
+				// 일시적인 DB / 파싱 오류로 전역 사전으로 떨어지지 않게 기존 트라이 유지
+				failed[name] = err
+				if old, ok := current[name]; ok {
+					next[name] = old
+				}
+				continue
+			}
+			layers = append(layers, dict)
+		}
+
+		if old, ok := current[name]; ok && slices.Equal(old.Versions, layerVersions(layers)) {
+			next[name] = old
+			continue
+		}
+
+		trie := NewWordReplacementTrie()
+		if err := trie.BuildTrie(layers...); err != nil {
+			failed[name] = fmt.Errorf("build %s failed: %w", name, err)
+			if old, ok := current[name]; ok {
+				next[name] = old
+			}
+			continue
+		}
+		next[name] = trie
+		changed = append(changed, name)
+	}
+
+	for name := range current {
+		if _, ok := next[name]; !ok && !slices.Contains(names, name) {
+			changed = append(changed, name) // 삭제됨
+		}
+	}
+
+	r.snapshot.Store(&next)
+	return changed, failed, nil
+}
+
+// Watch : interval 마다 Reload (DB 를 다른 인스턴스가 바꿔도 반영되게). ctx 가 끝나면 종료, interval 이 0 이하면 바로 종료
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := r.Reload(ctx)
+			if err != nil {
+				log.Printf("[dictionary] reload failed: %v\n", err)
+			}
+			if len(changed) > 0 {
+				log.Printf("[dictionary] reloaded: %v\n", changed)
+			}
+		}
+	}
+}
+
+// Load : 저장된 사전 원본
+func (r *Registry) Load(ctx context.Context, name string) (*Dict, error) {
+	return r.store.Load(ctx, name)
+}
+
+// List : 저장된 사전 이름
+func (r *Registry) List(ctx context.Context) ([]string, error) {
+	return r.store.List(ctx)
+}
+
+// Save : 검증 후 저장하고 바로 교체. 버전이 비어있으면 시간으로 채우고, 기존과 같으면 ErrVersionConflict
+func (r *Registry) Save(ctx context.Context, name string, dict *Dict) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, err := r.load(ctx, name)
+	if err != nil {
+		return err
+	}
+	return r.save(ctx, name, dict, current.Version)
+}
+
+// Update : 현재 사전을 읽어서 fn 으로 수정 후 새 버전으로 저장 (없으면 빈 사전에서 시작)
+// 읽기부터 저장까지 r.mu 를 잡고 있어서 같은 인스턴스의 Update 끼리 덮어쓰지 않음
+func (r *Registry) Update(ctx context.Context, name string, fn func(dict *Dict) error) (*Dict, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	dict, err := r.load(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	previous := dict.Version
+
+	if err = fn(dict); err != nil {
+		return nil, err
+	}
+	dict.Version = nextVersion(previous)
+
+	if err = r.save(ctx, name, dict, previous); err != nil {
+		return nil, err
+	}
+	return dict, nil
+}
+
+// load : 저장된 사전, 없으면 빈 사전 (버전 "")
+func (r *Registry) load(ctx context.Context, name string) (*Dict, error) {
+	dict, err := r.store.Load(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		return &Dict{}, nil
+	}
+	return dict, err
+}
+
+// save : r.mu 를 잡은 상태에서 호출. previous 는 읽어둔 버전 (저장소가 VersionedStore 면 그 사이에 바뀌었을 때 ErrConcurrentUpdate)
+func (r *Registry) save(ctx context.Context, name string, dict *Dict, previous string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if err := dict.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDict, err)
+	}
+
+	if dict.Version == "" {
+		dict.Version = NewVersion()
+	}
+	if dict.Version == previous {
+		return fmt.Errorf("%w: %s", ErrVersionConflict, dict.Version)
+	}
+
+	var err error
+	if store, ok := r.store.(VersionedStore); ok {
+		err = store.SaveIfVersion(ctx, name, dict, previous)
+	} else {
+		err = r.store.Save(ctx, name, dict)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 저장은 이미 끝났으니 다른 사전의 reload 실패는 로그만 (저장한 사전 것만 에러로)
+	_, failed, err := r.reload(ctx)
+	if err != nil {
+		return err
+	}
+	for other, reloadErr := range failed {
+		if other != name {
+			log.Printf("[dictionary] reload %s failed: %v\n", other, reloadErr)
+		}
+	}
+	return failed[name]
+}
+
+// DryRunResult : 샘플 문장 하나에 대한 현재 버전 / 새 버전 적용 결과
+type DryRunResult struct {
+	Text         string        `json:"text"`
+	Current      string        `json:"current"`
+	Proposed     string        `json:"proposed"`
+	Replacements []Replacement `json:"replacements"` // 새 버전 기준, 원문 offset
+	Changed      bool          `json:"changed"`      // 현재 버전과 결과가 다름
+}
+
+// DryRun : proposed 를 저장하지 않고 samples 에 적용해봄 (고객사 사전이면 현재 전역 사전 위에 얹음)
+func (r *Registry) DryRun(ctx context.Context, name string, proposed *Dict, samples []string) ([]DryRunResult, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	layers := []*Dict{proposed}
+	if name != GlobalDictionary {
+		global, err := r.store.Load(ctx, GlobalDictionary)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		layers = []*Dict{global, proposed}
+	}
+
+	trie := NewWordReplacementTrie()
+	if err := trie.BuildTrie(layers...); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDict, err)
+	}
+
+	current := r.Get(name)
+	results := make([]DryRunResult, 0, len(samples))
+	for _, text := range samples {
+		replacements := trie.FindReplacements(text)
+		result := DryRunResult{
+			Text:         text,
+			Current:      current.ReplaceWords(text),
+			Proposed:     Apply(text, replacements),
+			Replacements: replacements,
+		}
+		result.Changed = result.Current != result.Proposed
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// NewVersion : 시간 기반 버전 (예제 사전의 "2025-09-17 14:30:00" 형식 + ms)
+func NewVersion() string {
+	return time.Now().Format("2006-01-02 15:04:05.000")
+}
+
+// nextVersion : Update 가 같은 ms 안에 연달아 와도 이전 버전과 겹치지 않게
+func nextVersion(previous string) string {
+	for {
+		if version := NewVersion(); version != previous {
+			return version
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func layerVersions(layers []*Dict) []string {
+	versions := make([]string, 0, len(layers))
+	for _, dict := range layers {
+		if dict != nil {
+			versions = append(versions, dict.Version)
+		}
+	}
+	return versions
+}
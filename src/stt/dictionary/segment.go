@@ -0,0 +1,156 @@
+package dictionary
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"example/stt/subtitle"
+)
+
+// ReplaceSegments : 자막 문장 + 단어(SentenceFrames) 를 같이 치환한 복사본
+// Sentence 만 바꾸면 나중에 splitSegment 가 frames 로 문장을 다시 만들면서 치환이 되돌아가니까 frames 도 같이 고침
+func (trie *WordReplacementTrie) ReplaceSegments(segments []subtitle.SubtitleSegment) []subtitle.SubtitleSegment {
+	replaced := make([]subtitle.SubtitleSegment, len(segments))
+	for i, seg := range segments {
+		replaced[i] = trie.ReplaceSegment(seg)
+	}
+	return replaced
+}
+
+// ReplaceSegment : 문장은 그대로 치환하고, frames 는 단어를 이어붙인 텍스트 기준으로 치환 후 타이밍 재분배
+// 여러 단어 -> 한 단어 : 타이밍 합침 ("culture" "wave" -> "Kulture Wave" 는 두 단어라 그대로 1:1)
+// 한 단어 -> 여러 단어 : 구간을 글자 수 비율로 나눔
+func (trie *WordReplacementTrie) ReplaceSegment(seg subtitle.SubtitleSegment) subtitle.SubtitleSegment {
+	seg.Sentence = trie.ReplaceWords(seg.Sentence)
+	if len(seg.SentenceFrames) > 0 {
+		seg.SentenceFrames = trie.replaceFrames(seg.SentenceFrames)
+	}
+	return seg
+}
+
+// frameSpan : 이어붙인 텍스트에서 frame 단어 위치
+type frameSpan struct {
+	start, end int
+}
+
+func (trie *WordReplacementTrie) replaceFrames(frames []subtitle.SentenceFrames) []subtitle.SentenceFrames {
+	// 1. 단어 이어붙이기 (공백 하나로)
+	var b strings.Builder
+	spans := make([]frameSpan, len(frames))
+	for i, f := range frames {
+		word := strings.TrimSpace(f.Word)
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		spans[i] = frameSpan{start: b.Len(), end: b.Len() + len(word)}
+		b.WriteString(word)
+	}
+	text := b.String()
+
+	replacements := trie.FindReplacements(text)
+	if len(replacements) == 0 {
+		return frames
+	}
+
+	// 2. 치환마다 걸치는 frame 범위를 찾고, 범위가 겹치는 치환끼리 묶어서 한번에 처리
+	result := make([]subtitle.SentenceFrames, 0, len(frames))
+	next := 0 // 아직 처리 안한 첫 frame
+	for i := 0; i < len(replacements); {
+		first, last := overlapping(spans, replacements[i])
+		if first < 0 {
+			i++ // 단어 사이 공백만 걸친 치환은 frames 에 영향 없음
+			continue
+		}
+
+		group := []Replacement{replacements[i]}
+		for i++; i < len(replacements); i++ {
+			f, l := overlapping(spans, replacements[i])
+			if f < 0 || f > last {
+				break
+			}
+			last = max(last, l)
+			group = append(group, replacements[i])
+		}
+
+		result = append(result, frames[next:first]...)
+		result = append(result, respan(frames[first:last+1], text, spans[first].start, spans[last].end, group)...)
+		next = last + 1
+	}
+	result = append(result, frames[next:]...)
+
+	for i := range result {
+		result[i].WordIdx = i
+	}
+	return result
+}
+
+// overlapping : 치환 구간과 겹치는 첫/마지막 frame (없으면 -1, -1)
+func overlapping(spans []frameSpan, r Replacement) (int, int) {
+	first, last := -1, -1
+	for i, s := range spans {
+		if s.start < r.End && r.Start < s.end {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	return first, last
+}
+
+// respan : frames 구간 텍스트에 치환을 적용하고 새 단어들에 타이밍 배정
+func respan(frames []subtitle.SentenceFrames, text string, from, to int, group []Replacement) []subtitle.SentenceFrames {
+	shifted := make([]Replacement, len(group))
+	for i, r := range group {
+		r.Start, r.End = max(r.Start, from)-from, min(r.End, to)-from
+		shifted[i] = r
+	}
+	words := strings.Fields(Apply(text[from:to], shifted))
+
+	// 단어 수가 같으면 원래 타이밍 그대로
+	if len(words) == len(frames) {
+		out := make([]subtitle.SentenceFrames, len(frames))
+		copy(out, frames)
+		for i := range out {
+			out[i].Word = leadingSpace(frames[i].Word) + words[i]
+		}
+		return out
+	}
+
+	start, end := frames[0].WordStartTime, frames[len(frames)-1].WordEndTime
+	confidence := 0.0
+	for _, f := range frames {
+		confidence += f.ConfidenceScore
+	}
+	confidence /= float64(len(frames))
+
+	total := 0
+	for _, w := range words {
+		total += utf8.RuneCountInString(w)
+	}
+
+	out := make([]subtitle.SentenceFrames, 0, len(words))
+	cursor, chars := start, 0
+	for i, w := range words {
+		chars += utf8.RuneCountInString(w)
+		wordEnd := start + (end-start)*float64(chars)/float64(total)
+		if i == len(words)-1 {
+			wordEnd = end
+		}
+		out = append(out, subtitle.SentenceFrames{
+			Word:            leadingSpace(frames[0].Word) + w,
+			WordStartTime:   cursor,
+			WordEndTime:     wordEnd,
+			ConfidenceScore: confidence,
+			Speaker:         frames[0].Speaker,
+		})
+		cursor = wordEnd
+	}
+	return out
+}
+
+// leadingSpace : whisper 단어 앞 공백 (" word") 유지용
+func leadingSpace(word string) string {
+	return word[:len(word)-len(strings.TrimLeftFunc(word, unicode.IsSpace))]
+}
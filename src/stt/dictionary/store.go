@@ -0,0 +1,132 @@
+package dictionary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// GlobalDictionary : 모든 고객사에 깔리는 전역 사전 이름
+const GlobalDictionary = "global"
+
+var (
+	ErrNotFound         = errors.New("dictionary not found")
+	ErrVersionConflict  = errors.New("dictionary version must change on update")
+	ErrInvalidName      = errors.New("invalid dictionary name")
+	ErrInvalidDict      = errors.New("invalid dictionary")
+	ErrConcurrentUpdate = errors.New("dictionary was updated concurrently")
+)
+
+// 사전 이름 = 고객사 코드 (파일명, DB 키로 그대로 쓰니까 제한함)
+var nameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ValidateName : 사전 이름 체크
+func ValidateName(name string) error {
+	if !nameRegex.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	return nil
+}
+
+// Store : 사전 저장소 (고객사별 최신 버전 1개씩)
+type Store interface {
+	Load(ctx context.Context, name string) (*Dict, error) // 없으면 ErrNotFound
+	Save(ctx context.Context, name string, dict *Dict) error
+	List(ctx context.Context) ([]string, error)
+}
+
+// VersionedStore : 여러 인스턴스가 같이 쓰는 저장소 (현재 버전이 previous 일 때만 저장, 아니면 ErrConcurrentUpdate)
+type VersionedStore interface {
+	Store
+	SaveIfVersion(ctx context.Context, name string, dict *Dict, previous string) error
+}
+
+// FileStore : Dir/<name>.json 으로 저장
+type FileStore struct {
+	Dir string
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create dictionary dir failed: %w", err)
+	}
+	return &FileStore{Dir: dir}, nil
+}
+
+func (s *FileStore) path(name string) string {
+	return filepath.Join(s.Dir, name+".json")
+}
+
+func (s *FileStore) Load(ctx context.Context, name string) (*Dict, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("file read failed: %w", err)
+	}
+
+	dict := &Dict{}
+	if err = json.Unmarshal(data, dict); err != nil {
+		return nil, fmt.Errorf("parse dictionary %s failed: %w", name, err)
+	}
+	return dict, nil
+}
+
+// Save : temp 파일에 쓰고 rename (읽는 쪽에서 반쯤 쓰인 파일을 보지 않게)
+func (s *FileStore) Save(ctx context.Context, name string, dict *Dict) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(dict, "", "  ")
+	if err != nil {
+		return fmt.Errorf("JSON marshal failed: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.Dir, name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file failed: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // rename 성공하면 no-op
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("file write failed: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("file write failed: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), s.path(name)); err != nil {
+		return fmt.Errorf("file rename failed: %w", err)
+	}
+	return nil
+}
+
+func (s *FileStore) List(ctx context.Context) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(s.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		name := strings.TrimSuffix(filepath.Base(m), ".json")
+		if ValidateName(name) == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
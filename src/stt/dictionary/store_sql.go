@@ -0,0 +1,125 @@
+package dictionary
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SQLSchema : MySQL 5.6 기준 (JSON 타입이 없어서 MEDIUMTEXT), 버전마다 row 추가해서 이력으로 남김
+const SQLSchema = `
+CREATE TABLE IF NOT EXISTS stt_dictionary (
+	id         BIGINT       NOT NULL AUTO_INCREMENT,
+	name       VARCHAR(64)  NOT NULL,
+	version    VARCHAR(64)  NOT NULL,
+	body       MEDIUMTEXT   NOT NULL,
+	created_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (id),
+	UNIQUE KEY uk_name_version (name, version),
+	KEY idx_name_id (name, id)
+) DEFAULT CHARSET=utf8mb4`
+
+// SQLStore : stt_dictionary 테이블 (name 별 가장 마지막 row 가 현재 버전)
+type SQLStore struct {
+	DB *sqlx.DB
+}
+
+func NewSQLStore(db *sqlx.DB) *SQLStore {
+	return &SQLStore{DB: db}
+}
+
+func (s *SQLStore) Load(ctx context.Context, name string) (*Dict, error) {
+	var body string
+	err := s.DB.GetContext(ctx, &body, `
+		select body
+		from stt_dictionary
+		where name = ?
+		order by id desc
+		limit 1
+	`, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select dictionary %s failed: %w", name, err)
+	}
+
+	dict := &Dict{}
+	if err = json.Unmarshal([]byte(body), dict); err != nil {
+		return nil, fmt.Errorf("parse dictionary %s failed: %w", name, err)
+	}
+	return dict, nil
+}
+
+func (s *SQLStore) Save(ctx context.Context, name string, dict *Dict) error {
+	return s.insert(ctx, s.DB, name, dict)
+}
+
+// SaveIfVersion : 마지막 row 를 잠그고 버전이 previous 그대로일 때만 추가 (다른 인스턴스의 Update 를 덮어쓰지 않게)
+// 처음 만드는 사전 (previous "") 끼리 동시에 들어오면 한쪽은 gap lock 에서 deadlock 으로 실패함
+func (s *SQLStore) SaveIfVersion(ctx context.Context, name string, dict *Dict, previous string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction failed: %w", err)
+	}
+	defer tx.Rollback() // commit 후에는 no-op
+
+	var current string
+	err = tx.GetContext(ctx, &current, `
+		select version
+		from stt_dictionary
+		where name = ?
+		order by id desc
+		limit 1
+		for update
+	`, name)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("select dictionary %s failed: %w", name, err)
+	}
+	if current != previous {
+		return fmt.Errorf("%w: %s (expected %q, current %q)", ErrConcurrentUpdate, name, previous, current)
+	}
+
+	if err = s.insert(ctx, tx, name, dict); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) insert(ctx context.Context, db sqlx.ExecerContext, name string, dict *Dict) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(dict)
+	if err != nil {
+		return fmt.Errorf("JSON marshal failed: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		insert into stt_dictionary (name, version, body)
+		values (?, ?, ?)
+	`, name, dict.Version, string(body))
+	if err != nil {
+		return fmt.Errorf("insert dictionary %s failed: %w", name, err)
+	}
+	return nil
+}
+
+func (s *SQLStore) List(ctx context.Context) ([]string, error) {
+	var names []string
+	err := s.DB.SelectContext(ctx, &names, `
+		select distinct name
+		from stt_dictionary
+		order by name asc
+	`)
+	return names, err
+}
@@ -0,0 +1,416 @@
+package dictionary
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// TrieNode : 토큰(소문자 단어) 단위 트라이 노드 + Aho-Corasick 링크
+type TrieNode struct {
+	Children map[string]*TrieNode
+	Phrases  []*phrase // 이 노드에서 끝나는 패턴
+
+	fail   *TrieNode // 매칭 실패시 이동할 노드 (가장 긴 suffix)
+	output *TrieNode // fail 경로 중 Phrases 가 있는 가장 가까운 노드
+}
+
+// WordReplacementTrie : 여러 단어 literal 패턴은 Aho-Corasick, regex 패턴은 정규식으로 한번에 매칭
+type WordReplacementTrie struct {
+	Root     *TrieNode
+	Versions []string // 빌드에 사용된 사전 버전 (레이어 순서)
+
+	rules []*regexRule
+	order int  // 나중에 들어온 패턴일수록 큼 (겹칠때 우선순위)
+	built bool // fail 링크 계산 여부
+}
+
+// phrase : literal 패턴 하나
+type phrase struct {
+	tokens        []string // 원래 대소문자
+	gaps          []string // 토큰 사이 구분자 (공백은 " " 로 정규화)
+	replacement   string
+	caseSensitive bool
+	order         int
+}
+
+type regexRule struct {
+	re          *regexp.Regexp
+	replacement string
+	order       int
+}
+
+// Replacement : 치환 하나 (Start, End 는 원문 byte offset)
+type Replacement struct {
+	Start       int    `json:"start"`
+	End         int    `json:"end"`
+	Original    string `json:"original"`
+	Replacement string `json:"replacement"`
+}
+
+func NewTrieNode() *TrieNode {
+	return &TrieNode{
+		Children: make(map[string]*TrieNode),
+	}
+}
+
+func NewWordReplacementTrie() *WordReplacementTrie {
+	return &WordReplacementTrie{
+		Root: NewTrieNode(),
+	}
+}
+
+// BuildTrie : JSON 사전으로 트라이 구성. 여러개면 앞이 전역, 뒤가 고객사 사전 (같은 패턴은 뒤 레이어가 덮어씀)
+func (trie *WordReplacementTrie) BuildTrie(layers ...*Dict) error {
+	for _, dict := range layers {
+		if dict == nil {
+			continue
+		}
+		if err := dict.Validate(); err != nil {
+			return err
+		}
+
+		for _, entry := range dict.Entries {
+			for _, pattern := range entry.Patterns {
+				if pattern.kind() == PatternTypeRegex {
+					re, _ := pattern.compile() // Validate 에서 확인함
+					trie.order++
+					trie.rules = append(trie.rules, &regexRule{re: re, replacement: entry.Word, order: trie.order})
+					continue
+				}
+				trie.insert(pattern.Value, entry.Word, pattern.caseSensitive())
+			}
+		}
+		trie.Versions = append(trie.Versions, dict.Version)
+	}
+
+	trie.build() // 이후로는 읽기만 하니까 여러 goroutine 에서 같이 써도 됨
+	return nil
+}
+
+// Insert : literal 패턴을 대소문자 무시로 삽입
+func (trie *WordReplacementTrie) Insert(pattern, replacementWord string) {
+	trie.insert(pattern, replacementWord, false)
+}
+
+func (trie *WordReplacementTrie) insert(pattern, replacementWord string, caseSensitive bool) {
+	tokens, gaps := splitPattern(pattern)
+	if len(tokens) == 0 {
+		return
+	}
+
+	current := trie.Root
+	for _, tok := range tokens {
+		key := strings.ToLower(tok)
+		if current.Children[key] == nil {
+			current.Children[key] = NewTrieNode()
+		}
+		current = current.Children[key]
+	}
+
+	trie.order++
+	p := &phrase{tokens: tokens, gaps: gaps, replacement: replacementWord, caseSensitive: caseSensitive, order: trie.order}
+
+	// 같은 패턴이 이미 있으면 덮어씀 (레이어 override)
+	for i, existing := range current.Phrases {
+		if existing.caseSensitive == caseSensitive && equalFold(existing, p) {
+			current.Phrases[i] = p
+			trie.built = false
+			return
+		}
+	}
+	current.Phrases = append(current.Phrases, p)
+	trie.built = false
+}
+
+// Search : 패턴 전체가 트라이에 있는지 확인하고 치환 단어 반환
+func (trie *WordReplacementTrie) Search(word string) (string, bool) {
+	tokens, gaps := splitPattern(word)
+	if len(tokens) == 0 {
+		return "", false
+	}
+
+	current := trie.Root
+	for _, tok := range tokens {
+		current = current.Children[strings.ToLower(tok)]
+		if current == nil {
+			return "", false // 패턴이 존재하지 않음
+		}
+	}
+
+	candidate := &phrase{tokens: tokens, gaps: gaps}
+	for i := len(current.Phrases) - 1; i >= 0; i-- {
+		if current.Phrases[i].matches(candidate) {
+			return current.Phrases[i].replacement, true
+		}
+	}
+	return "", false
+}
+
+// ReplaceWords : 텍스트에서 단어들을 치환
+func (trie *WordReplacementTrie) ReplaceWords(text string) string {
+	return Apply(text, trie.FindReplacements(text))
+}
+
+// FindReplacements : 원문 기준 치환 목록 (겹치면 왼쪽 -> 긴 것 -> 나중 레이어 순으로 하나만)
+func (trie *WordReplacementTrie) FindReplacements(text string) []Replacement {
+	type candidate struct {
+		Replacement
+		order int
+	}
+	candidates := make([]candidate, 0)
+
+	// 1. literal : 토큰 단위 Aho-Corasick
+	trie.build()
+	tokens := tokenize(text)
+	node := trie.Root
+	for i, tok := range tokens {
+		key := strings.ToLower(tok.text)
+		for node != trie.Root && node.Children[key] == nil {
+			node = node.fail
+		}
+		if child := node.Children[key]; child != nil {
+			node = child
+		}
+
+		for out := node; out != nil; out = out.output {
+			for _, p := range out.Phrases {
+				start := i - len(p.tokens) + 1
+				if start < 0 || !p.matchesAt(text, tokens[start:i+1]) {
+					continue
+				}
+				original := text[tokens[start].start:tok.end]
+				candidates = append(candidates, candidate{
+					Replacement: Replacement{
+						Start:       tokens[start].start,
+						End:         tok.end,
+						Original:    original,
+						Replacement: matchCase(original, p.replacement),
+					},
+					order: p.order,
+				})
+			}
+		}
+	}
+
+	// 2. regex
+	for _, rule := range trie.rules {
+		for _, loc := range rule.re.FindAllStringSubmatchIndex(text, -1) {
+			original := text[loc[0]:loc[1]]
+			expanded := string(rule.re.ExpandString(nil, rule.replacement, text, loc))
+			candidates = append(candidates, candidate{
+				Replacement: Replacement{Start: loc[0], End: loc[1], Original: original, Replacement: matchCase(original, expanded)},
+				order:       rule.order,
+			})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		if a.End != b.End {
+			return a.End > b.End
+		}
+		return a.order > b.order
+	})
+
+	result := make([]Replacement, 0, len(candidates))
+	lastEnd := -1
+	for _, c := range candidates {
+		if c.Start < lastEnd {
+			continue
+		}
+		if c.Original != c.Replacement.Replacement {
+			result = append(result, c.Replacement)
+		}
+		lastEnd = c.End
+	}
+	return result
+}
+
+// Apply : 원문에 치환 목록 적용 (replacements 는 정렬되어 있고 겹치지 않아야 함)
+func Apply(text string, replacements []Replacement) string {
+	if len(replacements) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	prev := 0
+	for _, r := range replacements {
+		b.WriteString(text[prev:r.Start])
+		b.WriteString(r.Replacement)
+		prev = r.End
+	}
+	b.WriteString(text[prev:])
+	return b.String()
+}
+
+// build : BFS 로 fail / output 링크 계산 (삽입 후 첫 매칭때 한번)
+func (trie *WordReplacementTrie) build() {
+	if trie.built {
+		return
+	}
+
+	queue := make([]*TrieNode, 0)
+	trie.Root.fail, trie.Root.output = nil, nil
+	for _, child := range trie.Root.Children {
+		child.fail, child.output = trie.Root, nil
+		queue = append(queue, child)
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for key, child := range current.Children {
+			fail := current.fail
+			for fail != trie.Root && fail.Children[key] == nil {
+				fail = fail.fail
+			}
+			if next := fail.Children[key]; next != nil && next != child {
+				fail = next
+			}
+			child.fail = fail
+
+			if len(fail.Phrases) > 0 {
+				child.output = fail
+			} else {
+				child.output = fail.output
+			}
+			queue = append(queue, child)
+		}
+	}
+
+	trie.built = true
+}
+
+// matchesAt : 대소문자, 토큰 사이 구분자까지 패턴과 같은지 ("culture, wave" 는 "Culture Wave" 아님)
+func (p *phrase) matchesAt(text string, tokens []token) bool {
+	for i, tok := range tokens {
+		if p.caseSensitive && tok.text != p.tokens[i] {
+			return false
+		}
+		if i > 0 && normalizeGap(text[tokens[i-1].end:tok.start]) != p.gaps[i-1] {
+			return false
+		}
+	}
+	return true
+}
+
+// matches : Search 용 (패턴끼리 비교)
+func (p *phrase) matches(other *phrase) bool {
+	if p.caseSensitive {
+		return strings.Join(p.tokens, "\x00") == strings.Join(other.tokens, "\x00") && equalGaps(p, other)
+	}
+	return equalFold(p, other)
+}
+
+func equalFold(a, b *phrase) bool {
+	return strings.EqualFold(strings.Join(a.tokens, "\x00"), strings.Join(b.tokens, "\x00")) && equalGaps(a, b)
+}
+
+func equalGaps(a, b *phrase) bool {
+	return strings.Join(a.gaps, "\x00") == strings.Join(b.gaps, "\x00")
+}
+
+type token struct {
+	text       string
+	start, end int
+}
+
+// tokenize : 단어(글자, 숫자, _) 단위로 자름. \w 는 ASCII 만 잡아서 한글이 빠지므로 unicode 기준
+func tokenize(text string) []token {
+	tokens := make([]token, 0)
+	start := -1
+	for i, r := range text {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, token{text: text[start:i], start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{text: text[start:], start: start, end: len(text)})
+	}
+	return tokens
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || r == '_'
+}
+
+// splitPattern : 패턴 -> 토큰 + 토큰 사이 구분자
+func splitPattern(pattern string) ([]string, []string) {
+	toks := tokenize(pattern)
+	tokens := make([]string, len(toks))
+	gaps := make([]string, 0, len(toks))
+	for i, tok := range toks {
+		tokens[i] = tok.text
+		if i > 0 {
+			gaps = append(gaps, normalizeGap(pattern[toks[i-1].end:tok.start]))
+		}
+	}
+	return tokens, gaps
+}
+
+// normalizeGap : 연속 공백은 종류, 개수 상관없이 " " 하나로
+func normalizeGap(gap string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range gap {
+		if unicode.IsSpace(r) {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// matchCase : 치환 단어가 전부 소문자면 원문 대소문자 모양을 따름 (HELLO -> WORLD, Hello -> World)
+// 치환 단어에 대문자가 있으면 표기 자체가 의도된 것이라 그대로 씀 (golang -> Go)
+func matchCase(original, replacement string) string {
+	if strings.ToLower(replacement) != replacement {
+		return replacement
+	}
+
+	upper, letters := 0, 0
+	first := []rune(original + " ")[0] // 첫 글자가 대문자일때만 Title 로 봄 ("5 Kilograms" 는 아님)
+	for _, r := range original {
+		if !unicode.IsLetter(r) || !(unicode.IsUpper(r) || unicode.IsLower(r)) {
+			continue
+		}
+		letters++
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+
+	switch {
+	case letters > 1 && upper == letters:
+		return strings.ToUpper(replacement)
+	case unicode.IsUpper(first):
+		runes := []rune(replacement)
+		for i, r := range runes {
+			if unicode.IsLetter(r) {
+				runes[i] = unicode.ToUpper(r)
+				break
+			}
+		}
+		return string(runes)
+	default:
+		return replacement
+	}
+}
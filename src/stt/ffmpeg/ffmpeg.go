@@ -0,0 +1,140 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Binary : 실행할 ffmpeg 경로 (PATH 에 없으면 교체)
+var Binary = "ffmpeg"
+
+// ExtractAudio : whisper 업로드용 webm(opus 12k, 모노) 생성
+func ExtractAudio(ctx context.Context, inputFile, outputFile string) error {
+	// outputFile 확장자가 webm 이면서, input 파일과 output 파일 경로가 같다면 이미 실행했다고 간주
+	if filepath.Ext(outputFile) == ".webm" && inputFile == outputFile {
+		return fmt.Errorf("audio extractor is already running")
+	}
+
+	if _, err := os.Stat(inputFile); os.IsNotExist(err) {
+		return fmt.Errorf("input file not exist: %s", inputFile)
+	}
+
+	return run(ctx, webmArgs(inputFile, outputFile))
+}
+
+// ExtractAudioToWav : VAD 용 16kHz 16-bit 모노 wav 추출, 결과 경로는 입력 파일 확장자만 .wav 로 바꾼 경로
+func ExtractAudioToWav(ctx context.Context, videoPath string) (string, error) {
+	if _, err := os.Stat(videoPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("비디오 파일을 찾을 수 없습니다: %s", videoPath)
+	}
+
+	wavPath := WavPath(videoPath)
+	if wavPath == videoPath {
+		return "", fmt.Errorf("input is already wav: %s", videoPath)
+	}
+
+	log.Printf("오디오 추출 중: %s -> %s\n", videoPath, wavPath)
+
+	if err := run(ctx, wavArgs(videoPath, wavPath)); err != nil {
+		return "", err
+	}
+
+	return wavPath, nil
+}
+
+// ExtractSegment : 입력 파일의 startSec ~ endSec 구간을 재인코딩 없이 잘라냄
+func ExtractSegment(ctx context.Context, inputPath, outputPath string, startSec, endSec float64) error {
+	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
+		return fmt.Errorf("input file not exist: %s", inputPath)
+	}
+
+	if endSec <= startSec {
+		return fmt.Errorf("invalid segment range: %.3f - %.3f", startSec, endSec)
+	}
+
+	return run(ctx, segmentArgs(inputPath, outputPath, startSec, endSec))
+}
+
+// RemuxMP4 : 재인코딩 없이 컨테이너만 mp4 로 (녹화 FLV -> 웹 재생용), moov 를 앞으로
+func RemuxMP4(ctx context.Context, inputPath, outputPath string) error {
+	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
+		return fmt.Errorf("input file not exist: %s", inputPath)
+	}
+
+	return run(ctx, remuxArgs(inputPath, outputPath))
+}
+
+// WavPath : 입력 파일과 같은 위치의 .wav 경로
+func WavPath(inputPath string) string {
+	return strings.TrimSuffix(inputPath, filepath.Ext(inputPath)) + ".wav"
+}
+
+func webmArgs(inputFile, outputFile string) []string {
+	return []string{
+		"-i", inputFile,
+		"-vn",
+		"-map_metadata", "-1",
+		"-ac", "1",
+		"-c:a", "libopus",
+		"-b:a", "12k",
+		"-application", "voip", // 음성 최적화
+		"-f", "webm",
+		"-y",
+		outputFile,
+	}
+}
+
+func wavArgs(inputFile, outputFile string) []string {
+	return []string{
+		"-i", inputFile,
+		"-vn",
+		"-c:a", "pcm_s16le", // VAD용 16-bit PCM
+		"-ar", "16000", // VAD용 16kHz
+		"-ac", "1", // VAD는 모노가 더 정확
+		"-map_metadata", "-1",
+		"-f", "wav",
+		"-y",
+		outputFile,
+	}
+}
+
+func segmentArgs(inputFile, outputFile string, startSec, endSec float64) []string {
+	return []string{
+		"-i", inputFile,
+		"-ss", fmt.Sprintf("%.3f", startSec),
+		"-to", fmt.Sprintf("%.3f", endSec),
+		"-c", "copy",
+		"-y", // 덮어쓰기
+		outputFile,
+	}
+}
+
+func remuxArgs(inputFile, outputFile string) []string {
+	return []string{
+		"-i", inputFile,
+		"-c", "copy",
+		"-movflags", "+faststart",
+		"-f", "mp4", // 확장자가 .mp4 가 아닌 임시 파일에 써도 되게
+		"-y",
+		outputFile,
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	cmd := exec.CommandContext(ctx, Binary, args...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("run ffmpeg command: %v, output: %s", err, stderr.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package ffmpeg
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWavPath(t *testing.T) {
+	assert.Equal(t, "sample/e1.wav", WavPath("sample/e1.mp4"))
+	assert.Equal(t, "sample/e1.wav", WavPath("sample/e1"))
+}
+
+func TestSegmentArgs(t *testing.T) {
+	args := segmentArgs("in.wav", "out.wav", 1.5, 12.25)
+	assert.Equal(t, []string{"-i", "in.wav", "-ss", "1.500", "-to", "12.250", "-c", "copy", "-y", "out.wav"}, args)
+}
+
+func TestRemuxArgs(t *testing.T) {
+	args := remuxArgs("in.flv", "out.mp4")
+	assert.Equal(t, []string{"-i", "in.flv", "-c", "copy", "-movflags", "+faststart", "-f", "mp4", "-y", "out.mp4"}, args)
+}
+
+func TestExtractAudio_Validation(t *testing.T) {
+	ctx := context.Background()
+
+	err := ExtractAudio(ctx, "same.webm", "same.webm")
+	assert.Error(t, err)
+
+	err = ExtractAudio(ctx, filepath.Join(t.TempDir(), "missing.wav"), "out.webm")
+	assert.Error(t, err)
+
+	_, err = ExtractAudioToWav(ctx, filepath.Join(t.TempDir(), "missing.mp4"))
+	assert.Error(t, err)
+
+	err = ExtractSegment(ctx, filepath.Join(t.TempDir(), "missing.wav"), "out.wav", 0, 1)
+	assert.Error(t, err)
+
+	err = RemuxMP4(ctx, filepath.Join(t.TempDir(), "missing.flv"), "out.mp4")
+	assert.Error(t, err)
+}
+
+func TestExtractAudioToWav(t *testing.T) {
+	if _, err := exec.LookPath(Binary); err != nil {
+		t.Skip("ffmpeg not installed")
+	}
+
+	ctx := context.Background()
+	src := filepath.Join(t.TempDir(), "tone.mp3")
+	require.NoError(t, exec.Command(Binary, "-f", "lavfi", "-i", "sine=frequency=440:duration=2", "-y", src).Run())
+
+	wavPath, err := ExtractAudioToWav(ctx, src)
+	require.NoError(t, err)
+	assert.FileExists(t, wavPath)
+
+	out := filepath.Join(t.TempDir(), "part.wav")
+	require.NoError(t, ExtractSegment(ctx, wavPath, out, 0.5, 1.5))
+	assert.FileExists(t, out)
+}
+
+func TestPCMDecoder(t *testing.T) {
+	// 인자를 무시하고 stdin 을 그대로 내보내는 가짜 ffmpeg (s16le 입력 = 출력)
+	fake := filepath.Join(t.TempDir(), "ffmpeg")
+	require.NoError(t, os.WriteFile(fake, []byte("#!/bin/sh\nexec cat\n"), 0755))
+	defer func(binary string) { Binary = binary }(Binary)
+	Binary = fake
+
+	d, err := StartPCMDecoder(context.Background(), "aac", 16000)
+	require.NoError(t, err)
+
+	raw := make([]byte, 0)
+	for _, v := range []int16{0, 16384, -32768, 32767} {
+		raw = binary.LittleEndian.AppendUint16(raw, uint16(v))
+	}
+	_, err = d.Write(raw)
+	require.NoError(t, err)
+	require.NoError(t, d.CloseInput())
+
+	pcm := make([]float32, 0)
+	buf := make([]float32, 3)
+	for {
+		n, err := d.Read(buf)
+		pcm = append(pcm, buf[:n]...)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+	require.NoError(t, d.Wait())
+
+	assert.Equal(t, []float32{0, 0.5, -1, 32767.0 / 32768}, pcm)
+}
@@ -0,0 +1,113 @@
+package ffmpeg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"sync"
+)
+
+// PCMDecoder : stdin 으로 들어오는 압축 오디오(ADTS AAC 등)를 16-bit 모노 PCM 으로 바로 디코딩하는 ffmpeg 프로세스
+// 라이브 입력용이라 probe / 버퍼링을 최소로 잡음
+type PCMDecoder struct {
+	SampleRate int
+
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	stderr bytes.Buffer
+
+	waitOnce sync.Once
+	raw      []byte
+}
+
+// StartPCMDecoder : inputFormat 은 ffmpeg -f 값 (e.g. "aac" = ADTS)
+func StartPCMDecoder(ctx context.Context, inputFormat string, sampleRate int) (*PCMDecoder, error) {
+	d := &PCMDecoder{SampleRate: sampleRate}
+	d.cmd = exec.CommandContext(ctx, Binary, pcmStreamArgs(inputFormat, sampleRate)...)
+	d.cmd.Stderr = &d.stderr
+
+	stdin, err := d.cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg stdin pipe: %w", err)
+	}
+	stdout, err := d.cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg stdout pipe: %w", err)
+	}
+	if err := d.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start ffmpeg: %w", err)
+	}
+
+	d.stdin = stdin
+	d.stdout = bufio.NewReader(stdout)
+	return d, nil
+}
+
+// Write : 압축 오디오 프레임을 그대로 넣음
+func (d *PCMDecoder) Write(p []byte) (int, error) {
+	return d.stdin.Write(p)
+}
+
+// Read : 디코딩된 샘플을 [-1, 1] float32 로 읽음, 디코더가 끝나면 io.EOF
+func (d *PCMDecoder) Read(pcm []float32) (int, error) {
+	if cap(d.raw) < len(pcm)*2 {
+		d.raw = make([]byte, len(pcm)*2)
+	}
+	raw := d.raw[:len(pcm)*2]
+
+	// 샘플 중간에서 끊기지 않게 짝수 bytes 까지 채움
+	n, err := io.ReadAtLeast(d.stdout, raw, 2)
+	if n%2 == 1 && err == nil {
+		var m int
+		m, err = io.ReadFull(d.stdout, raw[n:n+1])
+		n += m
+	}
+	n -= n % 2
+	for i := 0; i < n/2; i++ {
+		pcm[i] = float32(int16(binary.LittleEndian.Uint16(raw[i*2:]))) / 32768
+	}
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n / 2, err
+}
+
+// CloseInput : 입력 끝, ffmpeg 가 남은 프레임을 내보내고 stdout 을 닫음 (Read 가 io.EOF 를 받음)
+func (d *PCMDecoder) CloseInput() error {
+	return d.stdin.Close()
+}
+
+// Wait : 프로세스 종료 대기, stdout 을 끝까지 읽은 다음에 호출해야 함 (exec.Cmd.Wait 가 pipe 를 닫음)
+func (d *PCMDecoder) Wait() error {
+	var err error
+	d.waitOnce.Do(func() {
+		if waitErr := d.cmd.Wait(); waitErr != nil {
+			err = fmt.Errorf("run ffmpeg command: %v, output: %s", waitErr, d.stderr.String())
+		}
+	})
+	return err
+}
+
+func pcmStreamArgs(inputFormat string, sampleRate int) []string {
+	return []string{
+		"-hide_banner",
+		"-loglevel", "error",
+		"-fflags", "nobuffer",
+		"-probesize", "32",
+		"-analyzeduration", "0",
+		"-f", inputFormat,
+		"-i", "pipe:0",
+		"-vn",
+		"-c:a", "pcm_s16le",
+		"-ar", strconv.Itoa(sampleRate),
+		"-ac", "1",
+		"-f", "s16le",
+		"pipe:1",
+	}
+}
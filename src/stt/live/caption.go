@@ -0,0 +1,269 @@
+package live
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"example/stt/subtitle"
+	"example/stt/vad"
+)
+
+// 라이브 자막 : PCM -> 스트리밍 VAD -> 발화 구간마다 transcriber -> 스트림 기준 타임스탬프 자막
+// 파일 단위 파이프라인(20251218_audio_chunks)과 달리 청크를 모아두지 않고 발화가 닫히는 즉시 보냄
+
+const (
+	defaultSampleRate    = 16000
+	defaultLatencyBudget = 5000 // ms
+	defaultMaxSpeech     = 8000 // ms
+	defaultPreRoll       = 300  // ms
+	queueSize            = 32
+)
+
+// Detector : silero.StreamingDetector 와 같은 모양 (테스트에서 교체 가능하게 인터페이스로 받음)
+type Detector interface {
+	Write(pcm []float32) error
+	Flush()
+	Speaking() (float64, bool)
+	Split()
+}
+
+// DetectorFactory : onSegment 는 발화가 닫힐때마다 Write / Flush / Split 안에서 호출됨
+type DetectorFactory func(onSegment func(vad.Segment)) (Detector, error)
+
+// Transcriber : 발화 구간 PCM 하나를 자막으로 (타임스탬프는 pcm 시작 기준)
+type Transcriber interface {
+	Transcribe(ctx context.Context, pcm []float32, sampleRate int) ([]subtitle.SubtitleSegment, error)
+}
+
+// Config : 최악의 자막 지연은 대략 MaxSpeechMs + LatencyBudgetMs
+type Config struct {
+	SampleRate      int `json:"sample_rate"`       // 기본 16000 (silero)
+	LatencyBudgetMs int `json:"latency_budget_ms"` // 발화가 닫힌 뒤 자막이 나올때까지 허용 시간, 넘으면 그 발화는 버림
+	MaxSpeechMs     int `json:"max_speech_ms"`     // 발화가 이것보다 길어지면 강제로 잘라서 먼저 보냄
+	PreRollMs       int `json:"pre_roll_ms"`       // 발화 앞에 붙여서 보낼 오디오 (첫 음절 잘림 방지), 음수면 안 붙임
+}
+
+func (c Config) withDefaults() Config {
+	if c.SampleRate <= 0 {
+		c.SampleRate = defaultSampleRate
+	}
+	if c.LatencyBudgetMs <= 0 {
+		c.LatencyBudgetMs = defaultLatencyBudget
+	}
+	if c.MaxSpeechMs <= 0 {
+		c.MaxSpeechMs = defaultMaxSpeech
+	}
+	if c.PreRollMs < 0 {
+		c.PreRollMs = 0
+	} else if c.PreRollMs == 0 {
+		c.PreRollMs = defaultPreRoll
+	}
+	return c
+}
+
+// Event : 자막 하나 (SSE / WebSocket 으로 그대로 나감)
+type Event struct {
+	Stream    string                   `json:"stream"`
+	Segment   subtitle.SubtitleSegment `json:"segment"`
+	LatencyMs int64                    `json:"latency_ms"` // 발화가 닫힌 뒤 자막이 나올때까지
+}
+
+type speechJob struct {
+	segment  vad.Segment
+	pcm      []float32
+	closedAt time.Time
+}
+
+// Stats : 처리 현황
+type Stats struct {
+	Segments int `json:"segments"` // VAD 가 닫은 발화 수
+	Captions int `json:"captions"` // 내보낸 자막 수
+	Late     int `json:"late"`     // 지연 한도를 넘어서 버린 발화 수
+	Failed   int `json:"failed"`   // transcriber 에러
+}
+
+// Captioner : 스트림 하나당 하나, Write 는 한 goroutine 에서만 호출
+type Captioner struct {
+	Config Config
+	Stream string
+	Offset float64 // 첫 PCM 샘플의 스트림 기준 시각 (초)
+
+	detector    Detector
+	transcriber Transcriber
+	onCaption   func(Event)
+
+	buf      []float32
+	bufStart int // buf[0] 의 절대 샘플 위치
+	written  int
+	lastEnd  float64 // 직전 발화 끝, pre-roll 이 앞 발화와 겹치지 않게 (Split 직후 등)
+
+	jobs chan speechJob
+	done chan struct{}
+
+	mu    sync.Mutex
+	stats Stats
+	idx   int
+}
+
+func NewCaptioner(config Config, stream string, newDetector DetectorFactory, transcriber Transcriber, onCaption func(Event)) (*Captioner, error) {
+	c := &Captioner{
+		Config:      config.withDefaults(),
+		Stream:      stream,
+		transcriber: transcriber,
+		onCaption:   onCaption,
+		jobs:        make(chan speechJob, queueSize),
+		done:        make(chan struct{}),
+	}
+
+	detector, err := newDetector(c.onSpeech)
+	if err != nil {
+		return nil, fmt.Errorf("create vad detector: %w", err)
+	}
+	c.detector = detector
+
+	go c.run()
+	return c, nil
+}
+
+// Write : PCM(Config.SampleRate, 모노) 를 이어서 넣음, transcriber 를 기다리지 않음
+func (c *Captioner) Write(pcm []float32) error {
+	c.buf = append(c.buf, pcm...)
+	c.written += len(pcm)
+
+	if err := c.detector.Write(pcm); err != nil {
+		return err
+	}
+
+	// 발화가 너무 길면 지금까지를 먼저 보냄
+	if start, ok := c.detector.Speaking(); ok && c.seconds(c.written)-start >= float64(c.Config.MaxSpeechMs)/1000 {
+		c.detector.Split()
+	}
+
+	c.trim()
+	return nil
+}
+
+// Close : 진행중인 발화를 닫고, 남은 발화를 다 처리할때까지 기다림
+func (c *Captioner) Close() Stats {
+	c.detector.Flush()
+	close(c.jobs)
+	<-c.done
+
+	if d, ok := c.detector.(interface{ Destroy() error }); ok {
+		if err := d.Destroy(); err != nil {
+			log.Printf("[live] %s: detector destroy 실패: %v\n", c.Stream, err)
+		}
+	}
+
+	return c.Stats()
+}
+
+func (c *Captioner) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func (c *Captioner) seconds(samples int) float64 {
+	return float64(samples) / float64(c.Config.SampleRate)
+}
+
+// onSpeech : detector 콜백, 버퍼에서 발화 구간(+ pre-roll)을 잘라서 큐에 넣음
+func (c *Captioner) onSpeech(seg vad.Segment) {
+	preRoll := float64(c.Config.PreRollMs) / 1000
+	seg.SpeechStartAt = max(min(seg.SpeechStartAt, max(seg.SpeechStartAt-preRoll, c.lastEnd)), c.seconds(c.bufStart))
+
+	from := int(seg.SpeechStartAt*float64(c.Config.SampleRate)) - c.bufStart
+	to := min(int(seg.SpeechEndAt*float64(c.Config.SampleRate)), c.written) - c.bufStart
+	if from < 0 || to <= from {
+		return
+	}
+	seg.SpeechEndAt = c.seconds(c.bufStart + to)
+	c.lastEnd = seg.SpeechEndAt
+
+	c.mu.Lock()
+	c.stats.Segments++
+	c.mu.Unlock()
+
+	job := speechJob{segment: seg, pcm: append([]float32(nil), c.buf[from:to]...), closedAt: time.Now()}
+	select {
+	case c.jobs <- job:
+	default:
+		// 큐가 꽉 찼으면 어차피 지연 한도를 넘을 발화라 버림
+		c.mu.Lock()
+		c.stats.Late++
+		c.mu.Unlock()
+	}
+}
+
+// trim : 진행중인 발화 시작(또는 pre-roll)보다 앞은 더 필요 없음
+func (c *Captioner) trim() {
+	keep := c.written - c.Config.PreRollMs*c.Config.SampleRate/1000
+	if start, ok := c.detector.Speaking(); ok {
+		keep = min(keep, int(start*float64(c.Config.SampleRate))-c.Config.PreRollMs*c.Config.SampleRate/1000)
+	}
+
+	drop := keep - c.bufStart
+	if drop <= 0 {
+		return
+	}
+	n := copy(c.buf, c.buf[drop:])
+	c.buf = c.buf[:n]
+	c.bufStart = keep
+}
+
+// run : 발화 순서대로 하나씩 처리 (자막 순서가 뒤집히지 않게)
+func (c *Captioner) run() {
+	defer close(c.done)
+
+	budget := time.Duration(c.Config.LatencyBudgetMs) * time.Millisecond
+	for job := range c.jobs {
+		deadline := job.closedAt.Add(budget)
+		if time.Now().After(deadline) {
+			log.Printf("[live] %s: 지연 한도 초과, 발화 버림 %.3fs - %.3fs\n", c.Stream, job.segment.SpeechStartAt, job.segment.SpeechEndAt)
+			c.mu.Lock()
+			c.stats.Late++
+			c.mu.Unlock()
+			continue
+		}
+
+		c.transcribe(job, deadline)
+	}
+}
+
+func (c *Captioner) transcribe(job speechJob, deadline time.Time) {
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	segments, err := c.transcriber.Transcribe(ctx, job.pcm, c.Config.SampleRate)
+	if err != nil {
+		log.Printf("[live] %s: transcribe 실패 %.3fs - %.3fs: %v\n", c.Stream, job.segment.SpeechStartAt, job.segment.SpeechEndAt, err)
+		c.mu.Lock()
+		c.stats.Failed++
+		c.mu.Unlock()
+		return
+	}
+
+	// 발화 기준 -> 스트림 기준
+	subtitle.ShiftTime(segments, c.Offset+job.segment.SpeechStartAt)
+
+	latency := time.Since(job.closedAt).Milliseconds()
+	for _, seg := range segments {
+		if seg.Sentence == "" {
+			continue
+		}
+
+		c.mu.Lock()
+		seg.Idx = c.idx
+		c.idx++
+		c.stats.Captions++
+		c.mu.Unlock()
+
+		if c.onCaption != nil {
+			c.onCaption(Event{Stream: c.Stream, Segment: seg, LatencyMs: latency})
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package live
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	subscriberBuffer = 64
+	historySize      = 20 // 늦게 붙은 클라이언트에게 먼저 보내줄 최근 자막 수
+	keepAlive        = 15 * time.Second
+)
+
+// Hub : 스트림별 자막 구독 (SSE, WebSocket), 느린 구독자는 자기 이벤트만 버림
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan Event]struct{}
+	history     map[string][]Event
+
+	upgrader websocket.Upgrader
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[string]map[chan Event]struct{}),
+		history:     make(map[string][]Event),
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true }, // 플레이어가 다른 origin 에서 붙음
+		},
+	}
+}
+
+// Publish : Captioner 의 onCaption 으로 사용
+func (h *Hub) Publish(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	history := append(h.history[e.Stream], e)
+	if len(history) > historySize {
+		history = history[len(history)-historySize:]
+	}
+	h.history[e.Stream] = history
+
+	for ch := range h.subscribers[e.Stream] {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// End : 스트림 종료, 구독자 연결을 끊고 기록을 지움
+func (h *Hub) End(stream string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers[stream] {
+		close(ch)
+	}
+	delete(h.subscribers, stream)
+	delete(h.history, stream)
+}
+
+// Subscribe : 최근 자막부터 채워진 채널, 다 쓰면 cancel 호출
+func (h *Hub) Subscribe(stream string) (<-chan Event, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan Event, subscriberBuffer+historySize)
+	for _, e := range h.history[stream] {
+		ch <- e
+	}
+
+	if h.subscribers[stream] == nil {
+		h.subscribers[stream] = make(map[chan Event]struct{})
+	}
+	h.subscribers[stream][ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subscribers[stream][ch]; ok {
+			delete(h.subscribers[stream], ch)
+			close(ch)
+		}
+	}
+}
+
+// ServeSSE : text/event-stream, 이벤트 이름은 caption
+func (h *Hub) ServeSSE(w http.ResponseWriter, r *http.Request, stream string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events, cancel := h.Subscribe(stream)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case e, ok := <-events:
+			if !ok {
+				fmt.Fprint(w, "event: end\ndata: {}\n\n")
+				flusher.Flush()
+				return
+			}
+			data, _ := json.Marshal(e)
+			fmt.Fprintf(w, "id: %d\nevent: caption\ndata: %s\n\n", e.Segment.Idx, data)
+		}
+		flusher.Flush()
+	}
+}
+
+// ServeWebSocket : 이벤트마다 JSON text 메세지 하나
+func (h *Hub) ServeWebSocket(w http.ResponseWriter, r *http.Request, stream string) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("[live] websocket upgrade 실패: %v\n", err)
+		return
+	}
+	defer conn.Close()
+
+	events, cancel := h.Subscribe(stream)
+	defer cancel()
+
+	// 클라이언트 메세지는 안 받지만, 끊김(close frame)을 알려면 읽어야 함
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+				return
+			}
+		case e, ok := <-events:
+			if !ok {
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "stream ended"))
+				return
+			}
+			if err := conn.WriteJSON(e); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package live
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/stt/subtitle"
+	"example/stt/vad"
+)
+
+// energyDetector : 샘플 크기만 보는 가짜 VAD (silero 모델 없이 테스트)
+type energyDetector struct {
+	sr        int
+	pos       int
+	open      bool
+	start     float64
+	onSegment func(vad.Segment)
+}
+
+func (d *energyDetector) Write(pcm []float32) error {
+	for _, v := range pcm {
+		at := float64(d.pos) / float64(d.sr)
+		if v > 0.1 && !d.open {
+			d.open, d.start = true, at
+		} else if v <= 0.1 && d.open {
+			d.close(at)
+		}
+		d.pos++
+	}
+	return nil
+}
+
+func (d *energyDetector) close(at float64) {
+	d.open = false
+	d.onSegment(vad.Segment{SpeechStartAt: d.start, SpeechEndAt: at})
+}
+
+func (d *energyDetector) Flush() {
+	if d.open {
+		d.close(float64(d.pos) / float64(d.sr))
+	}
+}
+
+func (d *energyDetector) Speaking() (float64, bool) { return d.start, d.open }
+
+func (d *energyDetector) Split() {
+	if d.open {
+		at := float64(d.pos) / float64(d.sr)
+		d.close(at)
+		d.open, d.start = true, at
+	}
+}
+
+type stubTranscriber struct {
+	delay time.Duration
+}
+
+// Transcribe : 받은 오디오 길이를 문장으로
+func (s *stubTranscriber) Transcribe(ctx context.Context, pcm []float32, sampleRate int) ([]subtitle.SubtitleSegment, error) {
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	duration := float64(len(pcm)) / float64(sampleRate)
+	return []subtitle.SubtitleSegment{{StartTime: 0, EndTime: duration, Sentence: fmt.Sprintf("%.1fs", duration)}}, nil
+}
+
+func newTestCaptioner(t *testing.T, config Config, transcriber Transcriber) (*Captioner, *[]Event) {
+	var mu sync.Mutex
+	events := make([]Event, 0)
+
+	c, err := NewCaptioner(config, "test", func(onSegment func(vad.Segment)) (Detector, error) {
+		return &energyDetector{sr: config.SampleRate, onSegment: onSegment}, nil
+	}, transcriber, func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	})
+	require.NoError(t, err)
+	return c, &events
+}
+
+func tone(sr int, seconds float64, value float32) []float32 {
+	pcm := make([]float32, int(seconds*float64(sr)))
+	for i := range pcm {
+		pcm[i] = value
+	}
+	return pcm
+}
+
+func TestCaptioner(t *testing.T) {
+	config := Config{SampleRate: 1000, PreRollMs: 200, MaxSpeechMs: 1000}
+	c, events := newTestCaptioner(t, config, &stubTranscriber{})
+	c.Offset = 10 // 스트림 시작 후 10초에 오디오가 시작됨
+
+	// 100ms 단위로 나눠서 넣음 (silence 1s, speech 0.5s, silence 1s, speech 2.5s)
+	pcm := append(tone(1000, 1, 0), tone(1000, 0.5, 1)...)
+	pcm = append(pcm, tone(1000, 1, 0)...)
+	pcm = append(pcm, tone(1000, 2.5, 1)...)
+	for i := 0; i < len(pcm); i += 100 {
+		require.NoError(t, c.Write(pcm[i:i+100]))
+	}
+	stats := c.Close()
+
+	sentences := make([]string, 0)
+	for _, e := range *events {
+		sentences = append(sentences, e.Segment.Sentence)
+	}
+	// pre-roll 200ms 포함, 긴 발화는 1초마다 잘림 (잘린 뒤에는 pre-roll 안 붙음)
+	assert.Equal(t, []string{"0.7s", "1.2s", "1.0s", "0.5s"}, sentences)
+	assert.InDelta(t, 10.8, (*events)[0].Segment.StartTime, 0.001)
+	assert.InDelta(t, 12.3, (*events)[1].Segment.StartTime, 0.001)
+	assert.InDelta(t, 13.5, (*events)[2].Segment.StartTime, 0.001)
+	assert.Equal(t, 3, (*events)[3].Segment.Idx)
+	assert.Equal(t, Stats{Segments: 4, Captions: 4}, stats)
+}
+
+func TestCaptionerLatencyBudget(t *testing.T) {
+	config := Config{SampleRate: 1000, LatencyBudgetMs: 50}
+	c, events := newTestCaptioner(t, config, &stubTranscriber{delay: 200 * time.Millisecond})
+
+	require.NoError(t, c.Write(append(tone(1000, 0.5, 1), tone(1000, 0.5, 0)...)))
+	stats := c.Close()
+
+	assert.Empty(t, *events)
+	assert.Equal(t, 1, stats.Failed) // deadline 초과
+}
+
+func TestVTTFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "live", "test.vtt")
+	f, err := CreateVTTFile(path)
+	require.NoError(t, err)
+
+	require.NoError(t, f.Append(subtitle.SubtitleSegment{StartTime: 1, EndTime: 2.5, Sentence: "hello <world>"}))
+	require.NoError(t, f.Append(subtitle.SubtitleSegment{StartTime: 3, EndTime: 4, Sentence: "bye"}))
+	require.NoError(t, f.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "WEBVTT\n\n00:00:01.000 --> 00:00:02.500\nhello &lt;world&gt;\n\n00:00:03.000 --> 00:00:04.000\nbye\n\n", string(data))
+
+	parsed, err := subtitle.ParseVTT(data)
+	require.NoError(t, err)
+	assert.Len(t, parsed, 2)
+}
+
+func TestHubSSE(t *testing.T) {
+	hub := NewHub()
+	hub.Publish(Event{Stream: "test", Segment: subtitle.SubtitleSegment{Idx: 0, Sentence: "before"}})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hub.ServeSSE(w, r, "test")
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		hub.Publish(Event{Stream: "test", Segment: subtitle.SubtitleSegment{Idx: 1, Sentence: "after"}})
+		hub.Publish(Event{Stream: "other", Segment: subtitle.SubtitleSegment{Idx: 0, Sentence: "other"}})
+		hub.End("test")
+	}()
+
+	body := make([]string, 0)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "data: ") || strings.HasPrefix(line, "event: ") {
+			body = append(body, line)
+		}
+	}
+
+	require.Len(t, body, 6)
+	assert.Equal(t, "event: caption", body[0])
+	assert.Contains(t, body[1], `"sentence":"before"`) // 늦게 붙어도 최근 자막부터 받음
+	assert.Contains(t, body[3], `"sentence":"after"`)
+	assert.Equal(t, "event: end", body[4])
+}
@@ -0,0 +1,59 @@
+package live
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"example/stt/subtitle"
+)
+
+// VTTFile : 자막이 나올때마다 cue 를 뒤에 붙이는 WebVTT (플레이어가 주기적으로 다시 읽음)
+type VTTFile struct {
+	Path string
+
+	mu     sync.Mutex
+	file   *os.File
+	writer subtitle.VTTWriter
+}
+
+func CreateVTTFile(path string) (*VTTFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("create vtt dir failed: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create vtt file failed: %w", err)
+	}
+	if _, err := f.WriteString("WEBVTT\n\n"); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return &VTTFile{Path: path, file: f}, nil
+}
+
+// Append : cue 하나 추가
+func (v *VTTFile) Append(seg subtitle.SubtitleSegment) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.file == nil {
+		return os.ErrClosed
+	}
+	return v.writer.AppendCue(v.file, seg)
+}
+
+func (v *VTTFile) Close() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.file == nil {
+		return nil
+	}
+	err := v.file.Close()
+	v.file = nil
+	return err
+}
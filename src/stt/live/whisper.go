@@ -0,0 +1,47 @@
+package live
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"example/stt/subtitle"
+	"example/stt/vad"
+	"example/stt/whisper"
+)
+
+// WhisperTranscriber : 발화 구간을 임시 wav 로 저장해서 whisper API 로 보냄
+type WhisperTranscriber struct {
+	Client *whisper.Client
+	Dir    string // 임시 파일 경로 (비어있으면 os.TempDir())
+
+	MaxNoSpeechProb float64 // 0 이면 0.6 (FilterNoSpeech 기준)
+}
+
+func (t *WhisperTranscriber) Transcribe(ctx context.Context, pcm []float32, sampleRate int) ([]subtitle.SubtitleSegment, error) {
+	f, err := os.CreateTemp(t.Dir, "live_*.wav")
+	if err != nil {
+		return nil, fmt.Errorf("create temp wav: %w", err)
+	}
+	path := f.Name()
+	_ = f.Close()
+	defer os.Remove(path)
+
+	if err := vad.WriteWavMono(path, pcm, sampleRate); err != nil {
+		return nil, err
+	}
+
+	response, err := t.Client.Transcribe(ctx, path, "speech.wav")
+	if err != nil {
+		return nil, err
+	}
+
+	maxNoSpeechProb := t.MaxNoSpeechProb
+	if maxNoSpeechProb <= 0 {
+		maxNoSpeechProb = 0.6
+	}
+	// 짧은 발화라 무음 위 환청이 잘 나옴
+	response.Segments = whisper.FilterNoSpeech(response.Segments, maxNoSpeechProb, 0)
+
+	return whisper.ConvertWhisperResponse(response), nil
+}
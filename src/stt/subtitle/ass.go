@@ -0,0 +1,166 @@
+package subtitle
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// ASSStyle : [V4+ Styles] 의 Default 스타일 (번인용)
+type ASSStyle struct {
+	FontName        string
+	FontSize        int
+	PrimaryColour   string // &HAABBGGRR
+	SecondaryColour string // 카라오케 진행 전 색
+	OutlineColour   string
+	BackColour      string
+	Bold            bool
+	Outline         float64
+	Shadow          float64
+	Alignment       int // numpad 기준 (2 = 하단 중앙)
+	MarginL         int
+	MarginR         int
+	MarginV         int
+}
+
+// DefaultASSStyle : 1080p 하단 중앙, 흰 글씨 + 검은 외곽선
+func DefaultASSStyle() ASSStyle {
+	return ASSStyle{
+		FontName:        "Noto Sans CJK KR",
+		FontSize:        56,
+		PrimaryColour:   "&H00FFFFFF",
+		SecondaryColour: "&H0000FFFF",
+		OutlineColour:   "&H00000000",
+		BackColour:      "&H80000000",
+		Outline:         2.5,
+		Shadow:          0,
+		Alignment:       2,
+		MarginL:         60,
+		MarginR:         60,
+		MarginV:         50,
+	}
+}
+
+// ASSWriter : Advanced SubStation Alpha
+// 화자 라벨은 Dialogue 의 Name 필드에 항상 기록하고, SpeakerPrefix 면 본문에도 붙임
+type ASSWriter struct {
+	Options  WriteOptions
+	Style    ASSStyle
+	PlayResX int // 0 이면 1920
+	PlayResY int // 0 이면 1080
+	Karaoke  bool
+}
+
+func (a *ASSWriter) Write(w io.Writer, segments []SubtitleSegment) error {
+	resX, resY := a.PlayResX, a.PlayResY
+	if resX <= 0 {
+		resX = 1920
+	}
+	if resY <= 0 {
+		resY = 1080
+	}
+
+	bold := 0
+	if a.Style.Bold {
+		bold = -1
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString("[Script Info]\n")
+	buffer.WriteString("ScriptType: v4.00+\n")
+	buffer.WriteString("WrapStyle: 0\n")
+	buffer.WriteString("ScaledBorderAndShadow: yes\n")
+	buffer.WriteString(fmt.Sprintf("PlayResX: %d\n", resX))
+	buffer.WriteString(fmt.Sprintf("PlayResY: %d\n\n", resY))
+
+	buffer.WriteString("[V4+ Styles]\n")
+	buffer.WriteString("Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, " +
+		"Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, " +
+		"Alignment, MarginL, MarginR, MarginV, Encoding\n")
+	buffer.WriteString(fmt.Sprintf("Style: Default,%s,%d,%s,%s,%s,%s,%d,0,0,0,100,100,0,0,1,%g,%g,%d,%d,%d,%d,1\n\n",
+		a.Style.FontName, a.Style.FontSize, a.Style.PrimaryColour, a.Style.SecondaryColour,
+		a.Style.OutlineColour, a.Style.BackColour, bold, a.Style.Outline, a.Style.Shadow,
+		a.Style.Alignment, a.Style.MarginL, a.Style.MarginR, a.Style.MarginV))
+
+	buffer.WriteString("[Events]\n")
+	buffer.WriteString("Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n")
+
+	segments = a.Options.withTiming(segments)
+	times := cueTimes(segments)
+	for idx, current := range segments {
+		name := ""
+		if current.Speaker != "" {
+			name = escapeASSField(a.Options.SpeakerName(current.Speaker))
+		}
+
+		text := a.dialogueText(current, times[idx][0])
+		if prefix := a.Options.speakerPrefix(current.Speaker); prefix != "" {
+			text = escapeASS(prefix) + text
+		}
+
+		buffer.WriteString(fmt.Sprintf("Dialogue: 0,%s,%s,Default,%s,0,0,0,,%s\n",
+			FormatASSTime(times[idx][0]), FormatASSTime(times[idx][1]), name, text))
+	}
+
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
+
+func (a *ASSWriter) Extension() string {
+	return ".ass"
+}
+
+// dialogueText : 카라오케는 단어마다 {\k센티초} 태그. 첫 단어 전 공백 구간도 \k 로 채워서 싱크 맞춤
+func (a *ASSWriter) dialogueText(seg SubtitleSegment, startMs int) string {
+	if !a.Karaoke || len(seg.SentenceFrames) == 0 {
+		return escapeASS(strings.Join(textLines(seg.Sentence), "\n"))
+	}
+
+	var text strings.Builder
+	cursor := startMs
+	for i, frame := range seg.SentenceFrames {
+		word := escapeASS(strings.TrimSpace(frame.Word))
+		if word == "" {
+			continue
+		}
+
+		wordStart := int(math.Round(frame.WordStartTime * 1000))
+		wordEnd := int(math.Round(frame.WordEndTime * 1000))
+		if gap := wordStart - cursor; gap >= 10 {
+			text.WriteString(fmt.Sprintf("{\\k%d}", gap/10))
+		}
+		if wordEnd < wordStart {
+			wordEnd = wordStart
+		}
+		if i > 0 {
+			text.WriteString(" ")
+		}
+		text.WriteString(fmt.Sprintf("{\\k%d}%s", (wordEnd-wordStart)/10, word))
+		cursor = wordEnd
+	}
+	return text.String()
+}
+
+// escapeASS : 줄바꿈은 \N, 중괄호는 override 블록으로 해석되니까 괄호로 바꿈
+func escapeASS(text string) string {
+	text = strings.ReplaceAll(text, "\n", "\\N")
+	text = strings.ReplaceAll(text, "{", "(")
+	text = strings.ReplaceAll(text, "}", ")")
+	return text
+}
+
+// escapeASSField : Name 필드는 콤마로 구분되니까 제거
+func escapeASSField(text string) string {
+	return strings.ReplaceAll(escapeASS(text), ",", " ")
+}
+
+// FormatASSTime : timestamp format 변경 (ex. 20150 -> 0:00:20.15, 센티초 단위)
+func FormatASSTime(ms int) string {
+	if ms < 0 {
+		ms = 0
+	}
+	cs := ms / 10
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, (cs/6000)%60, (cs/100)%60, cs%100)
+}
@@ -0,0 +1,73 @@
+package subtitle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+type SubtitleSegment struct {
+	Idx                     int              `json:"idx"`
+	StartTime               float64          `json:"start_time"`
+	EndTime                 float64          `json:"end_time"`
+	Sentence                string           `json:"sentence"`
+	SentenceConfidenceScore float64          `json:"sentence_confidence_score"`
+	LLMCorrectSentence      string           `json:"llm_correct_sentence"`
+	LLMCorrectDiff          string           `json:"llm_correct_diff,omitempty"` // Sentence -> LLMCorrectSentence 단어 diff
+	SentenceFrames          []SentenceFrames `json:"sentence_frames"`
+	NoSpeechProb            float64          `json:"no_speech_prob,omitempty"`
+	CompressionRatio        float64          `json:"compression_ratio,omitempty"`
+	Failed                  bool             `json:"failed,omitempty"`  // 청크 인식 실패 구간 placeholder
+	Speaker                 string           `json:"speaker,omitempty"` // 화자 분리 결과 라벨 (SPEAKER_00 ...)
+}
+
+type SentenceFrames struct {
+	WordIdx         int     `json:"word_idx"`
+	Word            string  `json:"word"`
+	WordStartTime   float64 `json:"word_start_time"`
+	WordEndTime     float64 `json:"word_end_time"`
+	ConfidenceScore float64 `json:"confidence_score"`
+	Speaker         string  `json:"speaker,omitempty"`
+}
+
+// SortSubtitleSegment : start_time 오름차순 정렬
+func SortSubtitleSegment(segments []SubtitleSegment) {
+	sort.SliceStable(segments, func(i, j int) bool {
+		return segments[i].StartTime < segments[j].StartTime
+	})
+}
+
+// Reindex : 정렬 후 Idx 를 0부터 다시 매김
+func Reindex(segments []SubtitleSegment) {
+	for i := range segments {
+		segments[i].Idx = i
+	}
+}
+
+// ShiftTime : 문장, 단어 타임스탬프에 offset(초) 을 더함 (청크 -> 원본 타임라인 보정)
+func ShiftTime(segments []SubtitleSegment, offset float64) {
+	for i := range segments {
+		segments[i].StartTime += offset
+		segments[i].EndTime += offset
+
+		for j := range segments[i].SentenceFrames {
+			segments[i].SentenceFrames[j].WordStartTime += offset
+			segments[i].SentenceFrames[j].WordEndTime += offset
+		}
+	}
+}
+
+// SaveJSON : 자막 데이터를 JSON 파일로 저장
+func SaveJSON(segments []SubtitleSegment, outputPath string) error {
+	data, err := json.MarshalIndent(segments, "", "  ")
+	if err != nil {
+		return fmt.Errorf("JSON marshal failed: %w", err)
+	}
+
+	if err = os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("file write failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,288 @@
+package subtitle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParseError : 파싱 실패 위치 (1부터 시작하는 줄 번호)
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// 시간 표기 (hh:)mm:ss(.,)mmm, 시간과 밀리초는 생략 가능
+var timestampRegex = regexp.MustCompile(`^(?:(\d+):)?(\d{1,2}):(\d{1,2})(?:[.,](\d{1,3}))?$`)
+
+// vtt 본문 태그 (<v 이름>, <c>, </c>, <00:00:01.200> ...)
+var vttTagRegex = regexp.MustCompile(`<[^>]*>`)
+
+// ParseFile : 확장자(.srt, .vtt)로 포맷을 골라 파싱
+func ParseFile(path string) ([]SubtitleSegment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("file read failed: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".srt":
+		return ParseSRT(data)
+	case ".vtt":
+		return ParseVTT(data)
+	default:
+		return nil, fmt.Errorf("unsupported subtitle format: %s", filepath.Ext(path))
+	}
+}
+
+// ParseSRT : SRT -> SubtitleSegment
+// BOM, CRLF, 블록 사이 빈 줄 누락, 밀리초 구분자 . 도 허용. Idx 는 0부터 다시 매김
+func ParseSRT(data []byte) ([]SubtitleSegment, error) {
+	cues, err := parseCues(normalizeLines(data), false)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := make([]SubtitleSegment, 0, len(cues))
+	for _, c := range cues {
+		segments = append(segments, SubtitleSegment{
+			Idx:       len(segments),
+			StartTime: c.start,
+			EndTime:   c.end,
+			Sentence:  strings.Join(c.lines, "\n"),
+		})
+	}
+	return segments, nil
+}
+
+// ParseVTT : WebVTT -> SubtitleSegment
+// NOTE/STYLE/REGION 블록과 cue 설정은 무시, <v 이름> 은 Speaker 로, 카라오케 타임스탬프 태그는 SentenceFrames 로 복원
+func ParseVTT(data []byte) ([]SubtitleSegment, error) {
+	lines := normalizeLines(data)
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], "WEBVTT") {
+		return nil, &ParseError{Line: 1, Msg: "missing WEBVTT header"}
+	}
+	lines[0] = "" // 헤더 줄은 빈 줄로 취급 (헤더 뒤 메타데이터 줄은 아래에서 블록째 건너뜀)
+
+	cues, err := parseCues(lines, true)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := make([]SubtitleSegment, 0, len(cues))
+	for _, c := range cues {
+		seg := SubtitleSegment{
+			Idx:       len(segments),
+			StartTime: c.start,
+			EndTime:   c.end,
+		}
+
+		text := strings.Join(c.lines, "\n")
+		if m := vttVoiceRegex.FindStringSubmatch(text); m != nil {
+			seg.Speaker = unescapeVTT(strings.TrimSpace(m[1]))
+		}
+
+		frames, err := parseKaraoke(vttVoiceRegex.ReplaceAllString(text, ""), c.start, c.end, c.line)
+		if err != nil {
+			return nil, err
+		}
+		seg.SentenceFrames = frames
+		seg.Sentence = unescapeVTT(vttTagRegex.ReplaceAllString(text, ""))
+
+		segments = append(segments, seg)
+	}
+	return segments, nil
+}
+
+var vttVoiceRegex = regexp.MustCompile(`<v(?:\.[^ >]*)?\s+([^>]+)>`)
+
+type cue struct {
+	line       int // 타임라인 줄 번호
+	start, end float64
+	lines      []string
+}
+
+// parseCues : 타임라인 줄을 기준으로 cue 를 나눔
+// 빈 줄이 빠져도 다음 타임라인(또는 다음 순번 + 타임라인)이 나오면 새 cue 로 봄
+func parseCues(lines []string, vtt bool) ([]cue, error) {
+	cues := make([]cue, 0)
+	var current *cue
+	number := 0 // 마지막 srt 번호
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		lineNo := i + 1
+
+		if line == "" {
+			current = nil
+			continue
+		}
+
+		// vtt 메타 블록, 헤더 뒤 메타데이터는 다음 빈 줄까지 건너뜀
+		if vtt && current == nil && isVTTMetaBlock(line, lines, i) {
+			for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+				i++
+			}
+			continue
+		}
+
+		if strings.Contains(line, "-->") {
+			start, end, err := parseTimeline(line)
+			if err != nil {
+				return nil, &ParseError{Line: lineNo, Msg: err.Error()}
+			}
+			cues = append(cues, cue{line: lineNo, start: start, end: end})
+			current = &cues[len(cues)-1]
+			continue
+		}
+
+		// 다음 줄이 타임라인이면 번호/cue 식별자 줄 (블록 시작일 때만)
+		// cue 가 열려있으면 (빈 줄 누락) 본문으로 봄, srt 는 마지막 번호 / cue 개수의 다음 순번만 번호로 봄 ("2024" 같은 본문이 사라지지 않게)
+		if i+1 < len(lines) && strings.Contains(lines[i+1], "-->") {
+			if current == nil && (vtt || isDigits(line)) {
+				number, _ = strconv.Atoi(line)
+				continue
+			}
+			if current != nil && !vtt && (line == strconv.Itoa(number+1) || line == strconv.Itoa(len(cues)+1)) {
+				number, _ = strconv.Atoi(line)
+				current = nil
+				continue
+			}
+		}
+
+		if current == nil {
+			return nil, &ParseError{Line: lineNo, Msg: fmt.Sprintf("text without timeline: %q", line)}
+		}
+		current.lines = append(current.lines, line)
+	}
+
+	return cues, nil
+}
+
+// isVTTMetaBlock : NOTE, STYLE, REGION 블록 또는 헤더 바로 뒤의 메타데이터 줄
+func isVTTMetaBlock(line string, lines []string, i int) bool {
+	for _, keyword := range []string{"NOTE", "STYLE", "REGION"} {
+		if line == keyword || strings.HasPrefix(line, keyword+" ") || strings.HasPrefix(line, keyword+"\t") {
+			return true
+		}
+	}
+	// 헤더(0번 줄) 바로 다음에 이어지는 줄
+	return i == 1 && !strings.Contains(line, "-->") && !(i+1 < len(lines) && strings.Contains(lines[i+1], "-->"))
+}
+
+// parseTimeline : "00:00:01,000 --> 00:00:02,500 line:85%" -> 1.0, 2.5 (cue 설정은 버림)
+func parseTimeline(line string) (float64, float64, error) {
+	parts := strings.SplitN(line, "-->", 2)
+	endFields := strings.Fields(parts[1])
+	if len(endFields) == 0 {
+		return 0, 0, fmt.Errorf("missing end time: %q", line)
+	}
+
+	start, err := ParseTimestamp(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := ParseTimestamp(endFields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	if end < start {
+		return 0, 0, fmt.Errorf("end time before start time: %q", line)
+	}
+	return start, end, nil
+}
+
+// ParseTimestamp : "00:00:20,150", "00:20.150", "0:00:20.15" -> 20.15 (초)
+func ParseTimestamp(value string) (float64, error) {
+	m := timestampRegex.FindStringSubmatch(value)
+	if m == nil {
+		return 0, fmt.Errorf("invalid timestamp: %q", value)
+	}
+
+	hours := 0
+	if m[1] != "" {
+		hours, _ = strconv.Atoi(m[1])
+	}
+	minutes, _ := strconv.Atoi(m[2])
+	seconds, _ := strconv.Atoi(m[3])
+	if minutes > 59 || seconds > 59 {
+		return 0, fmt.Errorf("invalid timestamp: %q", value)
+	}
+
+	// 밀리초 자리수가 모자라면 뒤를 0 으로 채움 (.5 -> 500ms, ASS 센티초 .15 -> 150ms)
+	ms := 0
+	if m[4] != "" {
+		ms, _ = strconv.Atoi(m[4] + strings.Repeat("0", 3-len(m[4])))
+	}
+
+	total := ((hours*60+minutes)*60+seconds)*1000 + ms
+	return float64(total) / 1000, nil
+}
+
+// parseKaraoke : "<c>one</c> <00:00:01.600><c>two</c>" -> 단어별 SentenceFrames (타임스탬프 태그가 없으면 nil)
+func parseKaraoke(text string, start, end float64, lineNo int) ([]SentenceFrames, error) {
+	if !karaokeTagRegex.MatchString(text) {
+		return nil, nil
+	}
+
+	words := make([]SentenceFrames, 0)
+	cursor := start
+	for _, token := range strings.Fields(strings.ReplaceAll(text, "\n", " ")) {
+		if m := karaokeTagRegex.FindStringSubmatch(token); m != nil {
+			t, err := ParseTimestamp(m[1])
+			if err != nil {
+				return nil, &ParseError{Line: lineNo, Msg: err.Error()}
+			}
+			cursor = t
+		}
+
+		word := unescapeVTT(vttTagRegex.ReplaceAllString(token, ""))
+		if word == "" {
+			continue
+		}
+		if n := len(words); n > 0 {
+			words[n-1].WordEndTime = cursor
+		}
+		words = append(words, SentenceFrames{
+			WordIdx:       len(words),
+			Word:          word,
+			WordStartTime: cursor,
+			WordEndTime:   end,
+		})
+	}
+	return words, nil
+}
+
+var karaokeTagRegex = regexp.MustCompile(`<(\d[\d:.]*)>`)
+
+func unescapeVTT(text string) string {
+	text = strings.ReplaceAll(text, "&lt;", "<")
+	text = strings.ReplaceAll(text, "&gt;", ">")
+	text = strings.ReplaceAll(text, "&nbsp;", " ")
+	text = strings.ReplaceAll(text, "&amp;", "&")
+	return text
+}
+
+// normalizeLines : BOM 제거 + CRLF/CR -> LF 후 줄 단위로 자름
+func normalizeLines(data []byte) []string {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	return strings.Split(text, "\n")
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
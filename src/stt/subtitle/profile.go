@@ -0,0 +1,151 @@
+package subtitle
+
+import "strings"
+
+// LineBreakProfile : 언어별 자막 분할/줄바꿈 기준
+// 글자 수는 DisplayWidth (rune 단위) 기준, 기본값은 넷플릭스 자막 가이드 참고
+type LineBreakProfile struct {
+	Language     string
+	MaxLineChars int     // 한 줄 최대 글자 수
+	MaxLines     int     // cue 당 최대 줄 수
+	MaxDuration  float64 // cue 최대 길이 (초)
+	MinDuration  float64 // 문장 끝에서 끊을때 최소 길이 (초)
+	MaxCPS       float64 // 초당 글자 수 (읽기 속도), 0 이면 체크 안함
+	NoSpace      bool    // 단어 사이 공백 없이 붙이는 언어 (ja, zh)
+
+	SentenceEnds []string // 문장 끝 (최우선 분할 지점)
+	ClauseBreaks []string // 절 구분 (쉼표 등)
+	BreakAfter   []string // 이 어미/조사로 끝나는 단어 뒤는 끊어도 자연스러움
+	NoLineStart  []string // 줄 맨 앞에 오면 안되는 문자 (닫는 괄호, 구두점)
+	NoLineEnd    []string // 줄 맨 끝에 오면 안되는 문자 (여는 괄호)
+}
+
+// EnglishProfile : 영어 (기존 SplitLongSegments 기준 84자 = 42자 x 2줄)
+func EnglishProfile() LineBreakProfile {
+	return LineBreakProfile{
+		Language:     "en",
+		MaxLineChars: 42,
+		MaxLines:     2,
+		MaxDuration:  4.0,
+		MinDuration:  1.5,
+		MaxCPS:       20,
+		SentenceEnds: []string{".", "!", "?", "…"},
+		ClauseBreaks: []string{",", ";", ":", "—"},
+		BreakAfter:   []string{},
+		NoLineStart:  []string{".", ",", "!", "?", ")", "]", "%", ":", ";"},
+		NoLineEnd:    []string{"(", "["},
+	}
+}
+
+// KoreanProfile : 한국어 (한 줄 16자, 초당 12자)
+// 조사/연결어미 뒤는 의미 단위가 끊기는 지점이라 분할 후보로 씀
+func KoreanProfile() LineBreakProfile {
+	return LineBreakProfile{
+		Language:     "ko",
+		MaxLineChars: 16,
+		MaxLines:     2,
+		MaxDuration:  4.0,
+		MinDuration:  1.2,
+		MaxCPS:       12,
+		SentenceEnds: []string{".", "!", "?", "…", "。"},
+		ClauseBreaks: []string{",", ";", ":", "·"},
+		BreakAfter: []string{
+			"은", "는", "이", "가", "을", "를", "에", "에서", "에게", "으로", "로", "와", "과", "도", "만",
+			"고", "며", "면", "서", "지만", "는데", "니까", "어서", "아서", "면서",
+		},
+		NoLineStart: []string{".", ",", "!", "?", ")", "]", "」", "』", "%", ":", ";", "·"},
+		NoLineEnd:   []string{"(", "[", "「", "『"},
+	}
+}
+
+// JapaneseProfile : 일본어 (한 줄 13자, 초당 4자, 공백 없이 붙임)
+// 금칙 처리 : 구두점/작은 가나는 줄 앞에 못오고, 여는 괄호는 줄 끝에 못옴
+func JapaneseProfile() LineBreakProfile {
+	return LineBreakProfile{
+		Language:     "ja",
+		MaxLineChars: 13,
+		MaxLines:     2,
+		MaxDuration:  4.0,
+		MinDuration:  1.2,
+		MaxCPS:       4,
+		NoSpace:      true,
+		SentenceEnds: []string{"。", "！", "？", "!", "?", "…"},
+		ClauseBreaks: []string{"、", "，", ","},
+		BreakAfter:   []string{"は", "が", "を", "に", "で", "と", "も", "へ", "から", "まで", "より", "て", "ので", "けど"},
+		NoLineStart: []string{
+			"、", "。", "，", "．", "・", "？", "！", "ー", "」", "』", "）", "〕", "］", "｝", "〉", "》", "…",
+			"ぁ", "ぃ", "ぅ", "ぇ", "ぉ", "っ", "ゃ", "ゅ", "ょ", "ァ", "ィ", "ゥ", "ェ", "ォ", "ッ", "ャ", "ュ", "ョ",
+		},
+		NoLineEnd: []string{"「", "『", "（", "〔", "［", "｛", "〈", "《"},
+	}
+}
+
+// ProfileForLanguage : whisper language 코드로 프로필 선택 (모르는 언어는 영어 기준)
+func ProfileForLanguage(lang string) LineBreakProfile {
+	switch strings.ToLower(lang) {
+	case "ko", "korean":
+		return KoreanProfile()
+	case "ja", "japanese":
+		return JapaneseProfile()
+	default:
+		profile := EnglishProfile()
+		if lang != "" {
+			profile.Language = strings.ToLower(lang)
+		}
+		return profile
+	}
+}
+
+// 분할 지점 점수 (높을수록 자연스러움)
+const (
+	breakNone = iota
+	breakParticle
+	breakClause
+	breakSentence
+)
+
+// breakScore : token 뒤에서 끊을때의 점수
+func (p LineBreakProfile) breakScore(token string) int {
+	token = strings.TrimSpace(token)
+	switch {
+	case hasAnySuffix(token, p.SentenceEnds):
+		return breakSentence
+	case hasAnySuffix(token, p.ClauseBreaks):
+		return breakClause
+	case hasAnySuffix(token, p.BreakAfter):
+		return breakParticle
+	default:
+		return breakNone
+	}
+}
+
+// allowBreak : prev 와 next 사이에서 줄을 바꿔도 되는지 (금칙 처리)
+func (p LineBreakProfile) allowBreak(prev, next string) bool {
+	return !hasAnyPrefix(strings.TrimSpace(next), p.NoLineStart) && !hasAnySuffix(strings.TrimSpace(prev), p.NoLineEnd)
+}
+
+// join : 언어에 맞게 토큰을 이어붙임
+func (p LineBreakProfile) join(tokens []string) string {
+	if p.NoSpace {
+		return strings.Join(tokens, "")
+	}
+	return strings.Join(tokens, " ")
+}
+
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if suffix != "" && strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package subtitle
+
+// WriteOptions : 자막 파일 출력 옵션
+type WriteOptions struct {
+	SpeakerPrefix bool              // 문장 앞에 화자 이름 표시
+	SpeakerNames  map[string]string // SPEAKER_00 -> "진행자" 같은 표시 이름, 없으면 라벨 그대로
+	Timing        *TimingConfig     // 타이밍 보정 규칙, nil 이면 겹치는 끝 시간만 자름
+}
+
+// SpeakerName : 라벨에 해당하는 표시 이름
+func (o WriteOptions) SpeakerName(label string) string {
+	if name, ok := o.SpeakerNames[label]; ok && name != "" {
+		return name
+	}
+	return label
+}
+
+// speakerPrefix : SRT 처럼 화자 표기법이 없는 포맷용 "이름: " prefix
+func (o WriteOptions) speakerPrefix(label string) string {
+	if !o.SpeakerPrefix || label == "" {
+		return ""
+	}
+	return o.SpeakerName(label) + ": "
+}
@@ -0,0 +1,309 @@
+package subtitle
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// LineBreaker : 프로필 기준으로 긴 자막을 cue 단위로 나누고 cue 안에서 줄바꿈
+type LineBreaker struct {
+	Profile LineBreakProfile
+}
+
+func NewLineBreaker(profile LineBreakProfile) *LineBreaker {
+	return &LineBreaker{Profile: profile}
+}
+
+// SplitLongSegments : 영어 기준(42자 x 2줄, 4초)으로 분할
+func SplitLongSegments(segments []SubtitleSegment) []SubtitleSegment {
+	return NewLineBreaker(EnglishProfile()).Split(segments)
+}
+
+// Split : 글자 수, 길이 기준을 넘는 자막을 단어 타임스탬프 기준으로 분할한 뒤
+// cue 마다 줄바꿈, 읽기 속도가 모자라면 다음 자막 전까지 표시 시간을 늘림
+func (b *LineBreaker) Split(segments []SubtitleSegment) []SubtitleSegment {
+	if len(segments) == 0 {
+		return segments
+	}
+
+	newSegments := make([]SubtitleSegment, 0, len(segments)*2) // 일단 2배로 잡고 봄
+	globalIdx := segments[0].Idx
+
+	for _, seg := range segments {
+		// 분할이 필요한지 체크 (단어 타임스탬프가 없으면 나눌 기준이 없음)
+		splits := []SubtitleSegment{seg}
+		if b.shouldSplit(seg) && len(seg.SentenceFrames) > 0 {
+			splits = b.splitSegment(seg)
+		}
+
+		for _, split := range splits {
+			split.Idx = globalIdx
+			if !split.Failed {
+				split.Sentence = b.BreakLines(split.Sentence)
+			}
+			newSegments = append(newSegments, split)
+			globalIdx++
+		}
+	}
+
+	b.enforceReadingSpeed(newSegments)
+	return newSegments
+}
+
+func (b *LineBreaker) shouldSplit(seg SubtitleSegment) bool {
+	duration := seg.EndTime - seg.StartTime
+	return DisplayWidth(seg.Sentence) > b.maxCueChars() || duration > b.Profile.MaxDuration
+}
+
+func (b *LineBreaker) maxCueChars() int {
+	lines := b.Profile.MaxLines
+	if lines < 1 {
+		lines = 1
+	}
+	return b.Profile.MaxLineChars * lines
+}
+
+func (b *LineBreaker) splitSegment(segment SubtitleSegment) []SubtitleSegment {
+	frames := segment.SentenceFrames
+	splits := make([]SubtitleSegment, 0, len(frames)/4+1)
+
+	start := 0
+	for idx := range frames {
+		// 1. 글자 수 또는 시간 초과 : start~idx-1 중 가장 자연스러운 지점에서 끊음
+		for idx > start && b.overLimit(frames[start:idx+1]) {
+			cut := b.bestBreak(frames[start:idx])
+			splits = append(splits, newSplit(segment, frames[start:start+cut+1], b.Profile))
+			start += cut + 1
+		}
+
+		// 2. 끝문장임 + 최소 시간 보다 넘었음
+		duration := frames[idx].WordEndTime - frames[start].WordStartTime
+		if idx < len(frames)-1 && b.Profile.breakScore(frames[idx].Word) == breakSentence && duration >= b.Profile.MinDuration {
+			splits = append(splits, newSplit(segment, frames[start:idx+1], b.Profile))
+			start = idx + 1
+		}
+	}
+
+	// 마지막 남은 단어들
+	if start < len(frames) {
+		splits = append(splits, newSplit(segment, frames[start:], b.Profile))
+	}
+
+	return splits
+}
+
+// overLimit : frames 를 한 cue 로 묶으면 글자 수나 시간 기준을 넘는지
+func (b *LineBreaker) overLimit(frames []SentenceFrames) bool {
+	duration := frames[len(frames)-1].WordEndTime - frames[0].WordStartTime
+	return DisplayWidth(b.Profile.join(frameWords(frames))) > b.maxCueChars() || duration > b.Profile.MaxDuration
+}
+
+// bestBreak : frames 중 어느 단어 뒤에서 끊을지 (반환값은 cue 의 마지막 단어 index)
+// 문장 끝 > 쉼표 > 조사/어미 순으로 우선하고, 같은 점수면 뒤쪽(긴 cue)을 고름
+// 최소 길이보다 짧아지거나 금칙 처리에 걸리는 지점은 감점
+func (b *LineBreaker) bestBreak(frames []SentenceFrames) int {
+	best, bestScore := len(frames)-1, math.Inf(-1)
+	for cut := range frames {
+		score := float64(b.Profile.breakScore(frames[cut].Word))*10 + float64(cut+1)/float64(len(frames))*5
+
+		if frames[cut].WordEndTime-frames[0].WordStartTime < b.Profile.MinDuration {
+			score -= 15
+		}
+		if cut+1 < len(frames) && !b.Profile.allowBreak(frames[cut].Word, frames[cut+1].Word) {
+			score -= 30
+		}
+
+		if score >= bestScore {
+			best, bestScore = cut, score
+		}
+	}
+	return best
+}
+
+func newSplit(segment SubtitleSegment, frames []SentenceFrames, profile LineBreakProfile) SubtitleSegment {
+	words := make([]SentenceFrames, len(frames))
+	copy(words, frames)
+
+	return SubtitleSegment{
+		StartTime:               frames[0].WordStartTime,
+		EndTime:                 frames[len(frames)-1].WordEndTime,
+		Sentence:                profile.join(frameWords(frames)),
+		SentenceConfidenceScore: segment.SentenceConfidenceScore,
+		SentenceFrames:          words,
+		Speaker:                 segment.Speaker,
+	}
+}
+
+func frameWords(frames []SentenceFrames) []string {
+	words := make([]string, 0, len(frames))
+	for _, f := range frames {
+		if w := strings.TrimSpace(f.Word); w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// BreakLines : cue 텍스트를 MaxLines 이하의 줄로 나눔 (줄 길이가 고르게, 자연스러운 지점 우선)
+// 한 줄에 들어가면 그대로 반환
+func (b *LineBreaker) BreakLines(text string) string {
+	p := b.Profile
+	tokens := b.tokenize(text)
+	joined := strings.TrimSpace(p.join(tokens))
+
+	total := DisplayWidth(joined)
+	if p.MaxLines <= 1 || p.MaxLineChars <= 0 || total <= p.MaxLineChars {
+		return joined
+	}
+
+	lines := (total + p.MaxLineChars - 1) / p.MaxLineChars
+	lines = min(lines, p.MaxLines, len(tokens))
+
+	breaks := b.balancedBreaks(tokens, lines, float64(total)/float64(lines))
+
+	out := make([]string, 0, lines)
+	prev := 0
+	for _, br := range append(breaks, len(tokens)) {
+		out = append(out, strings.TrimSpace(p.join(tokens[prev:br])))
+		prev = br
+	}
+	return strings.Join(out, "\n")
+}
+
+// balancedBreaks : tokens 를 lines 줄로 나누는 지점 (DP)
+// 비용 = Σ(줄 길이 - 평균)^2, 최대 글자 수 초과/금칙은 큰 감점, 문장 끝/쉼표/조사 뒤는 가산점
+func (b *LineBreaker) balancedBreaks(tokens []string, lines int, target float64) []int {
+	p := b.Profile
+	n := len(tokens)
+
+	lineCost := func(i, j int) float64 {
+		w := float64(DisplayWidth(strings.TrimSpace(p.join(tokens[i:j]))))
+		cost := (w - target) * (w - target)
+		if over := w - float64(p.MaxLineChars); over > 0 {
+			cost += over * 1000
+		}
+		return cost
+	}
+	breakCost := func(j int) float64 {
+		if !p.allowBreak(tokens[j-1], tokens[j]) {
+			return 10000
+		}
+		return -float64(p.breakScore(tokens[j-1])) * target
+	}
+
+	inf := math.Inf(1)
+	cost := make([][]float64, lines+1)
+	from := make([][]int, lines+1)
+	for k := range cost {
+		cost[k] = make([]float64, n+1)
+		from[k] = make([]int, n+1)
+		for j := range cost[k] {
+			cost[k][j] = inf
+		}
+	}
+	cost[0][0] = 0
+
+	for k := 1; k <= lines; k++ {
+		for j := k; j <= n; j++ {
+			for i := k - 1; i < j; i++ {
+				if math.IsInf(cost[k-1][i], 1) {
+					continue
+				}
+				c := cost[k-1][i] + lineCost(i, j)
+				if i > 0 {
+					c += breakCost(i)
+				}
+				if c < cost[k][j] {
+					cost[k][j], from[k][j] = c, i
+				}
+			}
+		}
+	}
+
+	breaks := make([]int, lines-1)
+	j := n
+	for k := lines; k > 1; k-- {
+		j = from[k][j]
+		breaks[k-2] = j
+	}
+	return breaks
+}
+
+// tokenize : 줄바꿈 후보 단위로 자름
+// 공백 언어는 단어, NoSpace 언어는 글자 단위 (영문/숫자 묶음과 공백은 한 토큰)
+func (b *LineBreaker) tokenize(text string) []string {
+	if !b.Profile.NoSpace {
+		return strings.Fields(text)
+	}
+
+	tokens := make([]string, 0, len(text))
+	var latin strings.Builder
+	flush := func() {
+		if latin.Len() > 0 {
+			tokens = append(tokens, latin.String())
+			latin.Reset()
+		}
+	}
+
+	for _, r := range strings.TrimSpace(text) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			latin.WriteRune(r)
+		case unicode.IsSpace(r):
+			flush()
+			if len(tokens) > 0 && tokens[len(tokens)-1] != " " {
+				tokens = append(tokens, " ")
+			}
+		default:
+			flush()
+			tokens = append(tokens, string(r))
+		}
+	}
+	flush()
+	return tokens
+}
+
+// enforceReadingSpeed : 초당 글자 수가 MaxCPS 를 넘으면 다음 자막 시작 전까지 끝 시간을 늘림 (줄이지는 않음)
+func (b *LineBreaker) enforceReadingSpeed(segments []SubtitleSegment) {
+	if b.Profile.MaxCPS <= 0 {
+		return
+	}
+
+	for i := range segments {
+		if segments[i].Failed {
+			continue
+		}
+
+		need := float64(DisplayWidth(segments[i].Sentence)) / b.Profile.MaxCPS
+		end := segments[i].StartTime + need
+		if i+1 < len(segments) {
+			end = math.Min(end, segments[i+1].StartTime)
+		}
+		if end > segments[i].EndTime {
+			segments[i].EndTime = end
+		}
+	}
+}
+
+// ReadingSpeed : 초당 글자 수 (길이가 0 이면 0)
+func ReadingSpeed(seg SubtitleSegment) float64 {
+	duration := seg.EndTime - seg.StartTime
+	if duration <= 0 {
+		return 0
+	}
+	return float64(DisplayWidth(seg.Sentence)) / duration
+}
+
+// DisplayWidth : 화면에 보이는 글자 수 (rune 단위, 줄바꿈과 결합 문자/zero-width 는 제외)
+// len() 은 바이트라서 한글이 3배로 세짐
+func DisplayWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		if r == '\n' || r == '\r' || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+			continue
+		}
+		width++
+	}
+	return width
+}
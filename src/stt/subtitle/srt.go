@@ -0,0 +1,68 @@
+package subtitle
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// SRTWriter : SubRip
+type SRTWriter struct {
+	Options WriteOptions
+}
+
+func (s *SRTWriter) Write(w io.Writer, segments []SubtitleSegment) error {
+	_, err := w.Write(ConvertSegmentToSrtFormatWithOptions(segments, s.Options))
+	return err
+}
+
+func (s *SRTWriter) Extension() string {
+	return ".srt"
+}
+
+// ConvertSegmentToSrtFormat : segments data SRT 포맷으로 변경
+// 2025.11.07 Canary 호출시 자막 타임라인이 겹치는 이슈가 있어서, 원 데이터 쓰고 겹치는 부분만 잘라냄
+func ConvertSegmentToSrtFormat(segments []SubtitleSegment) []byte {
+	return ConvertSegmentToSrtFormatWithOptions(segments, WriteOptions{})
+}
+
+// ConvertSegmentToSrtFormatWithOptions : 화자 이름 prefix 등 옵션 적용한 SRT
+func ConvertSegmentToSrtFormatWithOptions(segments []SubtitleSegment, opts WriteOptions) []byte {
+	var buffer bytes.Buffer
+	segments = opts.withTiming(segments)
+	times := cueTimes(segments)
+
+	for idx, current := range segments {
+		startTime, endTime := times[idx][0], times[idx][1]
+
+		buffer.WriteString(fmt.Sprintf("%d\n", idx+1))
+		buffer.WriteString(fmt.Sprintf("%s --> %s\n", FormatSRTTime(startTime), FormatSRTTime(endTime)))
+		buffer.WriteString(fmt.Sprintf("%s%s\n\n", opts.speakerPrefix(current.Speaker), strings.Join(textLines(current.Sentence), "\n")))
+	}
+
+	// 마지막 개행 제거함
+	result := buffer.Bytes()
+	if len(result) > 1 && result[len(result)-1] == '\n' {
+		return result[:len(result)-1]
+	}
+
+	return result
+}
+
+// FormatSRTTime : timestamp format 변경 (ex. 20150 -> 00:00:20,150)
+func FormatSRTTime(ms int) string {
+	if ms < 0 {
+		ms = 0
+	}
+
+	duration := time.Duration(ms) * time.Millisecond
+
+	hours := int(duration.Hours())
+	minutes := int(duration.Minutes()) % 60
+	seconds := int(duration.Seconds()) % 60
+	milliseconds := ms % 1000
+
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", hours, minutes, seconds, milliseconds)
+}
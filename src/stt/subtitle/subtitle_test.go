@@ -0,0 +1,544 @@
+package subtitle
+
+import (
+	"encoding/xml"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/stt/vad"
+)
+
+func frames(words []string, start, step float64) []SentenceFrames {
+	out := make([]SentenceFrames, len(words))
+	for i, w := range words {
+		out[i] = SentenceFrames{
+			WordIdx:       i,
+			Word:          w,
+			WordStartTime: start + step*float64(i),
+			WordEndTime:   start + step*float64(i+1),
+		}
+	}
+	return out
+}
+
+func TestSplitLongSegments(t *testing.T) {
+	t.Run("빈 입력", func(t *testing.T) {
+		assert.Empty(t, SplitLongSegments(nil))
+	})
+
+	t.Run("짧은 자막은 그대로", func(t *testing.T) {
+		segs := []SubtitleSegment{{Idx: 3, StartTime: 0, EndTime: 1, Sentence: "hello"}}
+		out := SplitLongSegments(segs)
+		require.Len(t, out, 1)
+		assert.Equal(t, 3, out[0].Idx)
+	})
+
+	t.Run("문장 끝 기준으로 분할", func(t *testing.T) {
+		words := []string{"one", "two", "three.", "four", "five", "six."}
+		segs := []SubtitleSegment{{
+			StartTime:      0,
+			EndTime:        6,
+			Sentence:       "one two three. four five six.",
+			SentenceFrames: frames(words, 0, 1),
+		}}
+
+		out := SplitLongSegments(segs)
+		require.Len(t, out, 2)
+		assert.Equal(t, "one two three.", out[0].Sentence)
+		assert.Equal(t, 0.0, out[0].StartTime)
+		assert.Equal(t, 3.0, out[0].EndTime)
+		assert.Equal(t, "four five six.", out[1].Sentence)
+		assert.Equal(t, 1, out[1].Idx)
+	})
+
+	t.Run("단어 타임스탬프가 없으면 분할하지 않음", func(t *testing.T) {
+		segs := []SubtitleSegment{{StartTime: 0, EndTime: 10, Sentence: "long"}}
+		assert.Len(t, SplitLongSegments(segs), 1)
+	})
+}
+
+func TestConvertSegmentToSrtFormat(t *testing.T) {
+	segs := []SubtitleSegment{
+		{StartTime: 0.5, EndTime: 2.2, Sentence: " hello "},
+		{StartTime: 2.0, EndTime: 3.0, Sentence: "world"},
+	}
+
+	expected := "1\n00:00:00,500 --> 00:00:01,999\nhello\n\n" +
+		"2\n00:00:02,000 --> 00:00:03,000\nworld\n"
+	assert.Equal(t, expected, string(ConvertSegmentToSrtFormat(segs)))
+}
+
+func TestSpeakerPrefix(t *testing.T) {
+	segs := []SubtitleSegment{
+		{StartTime: 0, EndTime: 1, Sentence: "안녕하세요", Speaker: "SPEAKER_00"},
+		{StartTime: 1, EndTime: 2, Sentence: "네 반갑습니다", Speaker: "SPEAKER_01"},
+	}
+	opts := WriteOptions{SpeakerPrefix: true, SpeakerNames: map[string]string{"SPEAKER_00": "진행자"}}
+
+	srt := string(ConvertSegmentToSrtFormatWithOptions(segs, opts))
+	assert.Contains(t, srt, "진행자: 안녕하세요\n")
+	assert.Contains(t, srt, "SPEAKER_01: 네 반갑습니다\n")
+
+	vtt := string(ConvertSegmentToVttFormat(segs, opts))
+	assert.Equal(t, "WEBVTT\n\n00:00:00.000 --> 00:00:01.000\n<v 진행자>안녕하세요\n\n"+
+		"00:00:01.000 --> 00:00:02.000\n<v SPEAKER_01>네 반갑습니다\n", vtt)
+
+	// 옵션 끄면 기존 출력 그대로
+	assert.Equal(t, string(ConvertSegmentToSrtFormat(segs)), string(ConvertSegmentToSrtFormatWithOptions(segs, WriteOptions{})))
+	assert.NotContains(t, string(ConvertSegmentToSrtFormat(segs)), "SPEAKER")
+}
+
+func TestNewWriter(t *testing.T) {
+	segs := []SubtitleSegment{
+		{StartTime: 0.5, EndTime: 2.2, Sentence: "hello", Speaker: "SPEAKER_00"},
+		{StartTime: 2.0, EndTime: 3.0, Sentence: "a < b & {c}\nnext line", Speaker: "SPEAKER_01"},
+	}
+
+	for format, ext := range map[string]string{"srt": ".srt", "VTT": ".vtt", ".ass": ".ass", "dfxp": ".ttml"} {
+		writer, err := NewWriter(format, WriteOptions{})
+		require.NoError(t, err, format)
+		assert.Equal(t, ext, writer.Extension())
+
+		data, err := Render(writer, segs)
+		require.NoError(t, err, format)
+		assert.NotEmpty(t, data, format)
+	}
+
+	_, err := NewWriter("txt", WriteOptions{})
+	assert.Error(t, err)
+
+	// 인식 실패 placeholder 는 빈 cue 로 쓰지 않음
+	withFailed := []SubtitleSegment{segs[0], {StartTime: 3, EndTime: 29, Failed: true}, {StartTime: 30, EndTime: 31, Sentence: "after"}}
+	for _, format := range []string{"srt", "vtt", "ass", "ttml"} {
+		writer, _ := NewWriter(format, WriteOptions{})
+		data, err := Render(writer, withFailed)
+		require.NoError(t, err, format)
+		assert.NotContains(t, string(data), "00:00:03", format)
+		assert.NotContains(t, string(data), "00:00:29", format)
+	}
+	assert.Equal(t, "1\n00:00:00,500 --> 00:00:02,200\nhello\n\n2\n00:00:30,000 --> 00:00:31,000\nafter\n",
+		string(ConvertSegmentToSrtFormat(withFailed)))
+
+	srt, err := Render(&SRTWriter{}, segs[:1])
+	require.NoError(t, err)
+	assert.Equal(t, string(ConvertSegmentToSrtFormat(segs[:1])), string(srt))
+
+	t.Run("ASS", func(t *testing.T) {
+		data, err := Render(&ASSWriter{Style: DefaultASSStyle()}, segs)
+		require.NoError(t, err)
+		out := string(data)
+		assert.Contains(t, out, "[Script Info]\n")
+		assert.Contains(t, out, "PlayResY: 1080\n")
+		assert.Contains(t, out, "Dialogue: 0,0:00:00.50,0:00:01.99,Default,SPEAKER_00,0,0,0,,hello\n")
+		assert.Contains(t, out, "Dialogue: 0,0:00:02.00,0:00:03.00,Default,SPEAKER_01,0,0,0,,a < b & (c)\\Nnext line\n")
+	})
+
+	t.Run("TTML 은 올바른 XML", func(t *testing.T) {
+		data, err := Render(&TTMLWriter{Options: WriteOptions{SpeakerNames: map[string]string{"SPEAKER_00": "진행자"}}}, segs)
+		require.NoError(t, err)
+
+		decoder := xml.NewDecoder(strings.NewReader(string(data)))
+		for {
+			_, err := decoder.Token()
+			if err != nil {
+				assert.Equal(t, "EOF", err.Error())
+				break
+			}
+		}
+
+		out := string(data)
+		assert.Contains(t, out, `<ttm:name type="full">진행자</ttm:name>`)
+		assert.Contains(t, out, `begin="00:00:00.500" end="00:00:01.999" ttm:agent="agent1">hello</p>`)
+		assert.Contains(t, out, `ttm:agent="agent2">a &lt; b &amp; {c}<br/>next line</p>`)
+	})
+}
+
+func TestVTTKaraoke(t *testing.T) {
+	segs := []SubtitleSegment{{
+		StartTime:      1,
+		EndTime:        3,
+		Sentence:       "one two three",
+		SentenceFrames: frames([]string{"one", "two", "three"}, 1, 0.6),
+	}}
+
+	data, err := Render(&VTTWriter{Karaoke: true, CueSettings: "line:85%"}, segs)
+	require.NoError(t, err)
+	assert.Equal(t, "WEBVTT\n\n00:00:01.000 --> 00:00:03.000 line:85%\n"+
+		"<c>one</c> <00:00:01.600><c>two</c> <00:00:02.200><c>three</c>\n", string(data))
+
+	ass, err := Render(&ASSWriter{Style: DefaultASSStyle(), Karaoke: true}, segs)
+	require.NoError(t, err)
+	assert.Contains(t, string(ass), ",,{\\k60}one {\\k60}two {\\k60}three\n")
+}
+
+func TestFormatASSTime(t *testing.T) {
+	assert.Equal(t, "0:00:20.15", FormatASSTime(20150))
+	assert.Equal(t, "1:01:01.00", FormatASSTime(3661001))
+	assert.Equal(t, "0:00:00.00", FormatASSTime(-5))
+}
+
+func TestFormatSRTTime(t *testing.T) {
+	assert.Equal(t, "00:00:20,150", FormatSRTTime(20150))
+	assert.Equal(t, "01:01:01,001", FormatSRTTime(3661001))
+	assert.Equal(t, "00:00:00,000", FormatSRTTime(-5))
+}
+
+func TestRemoveDuplicateTexts(t *testing.T) {
+	segs := []SubtitleSegment{
+		{Sentence: "thank you"},
+		{Sentence: "thank  you "},
+		{Sentence: "bye"},
+		{Sentence: "thank you"},
+	}
+	out := RemoveDuplicateTexts(segs)
+	require.Len(t, out, 3)
+	assert.Equal(t, "bye", out[1].Sentence)
+}
+
+func TestFilterBySpeech(t *testing.T) {
+	speech := []vad.Segment{{SpeechStartAt: 10, SpeechEndAt: 20}, {SpeechStartAt: 0, SpeechEndAt: 5}}
+	segs := []SubtitleSegment{
+		{StartTime: 1, EndTime: 2, Sentence: "in"},
+		{StartTime: 7, EndTime: 8, Sentence: "out"},
+		{StartTime: 19, EndTime: 25, Sentence: "partial"},
+		{StartTime: 5.3, EndTime: 6, Sentence: "tolerance"},
+	}
+
+	out := FilterBySpeech(segs, speech, 0.5)
+	texts := make([]string, 0, len(out))
+	for _, s := range out {
+		texts = append(texts, s.Sentence)
+	}
+	assert.Equal(t, []string{"in", "tolerance", "partial"}, texts)
+
+	assert.Len(t, FilterBySpeech(segs, nil, 0.5), 4)
+}
+
+func TestShiftTime(t *testing.T) {
+	segs := []SubtitleSegment{{StartTime: 1, EndTime: 2, SentenceFrames: frames([]string{"a"}, 1, 1)}}
+	ShiftTime(segs, 10)
+	assert.Equal(t, 11.0, segs[0].StartTime)
+	assert.Equal(t, 12.0, segs[0].EndTime)
+	assert.Equal(t, 11.0, segs[0].SentenceFrames[0].WordStartTime)
+}
+
+func TestParseSRT(t *testing.T) {
+	t.Run("라운드트립", func(t *testing.T) {
+		segs := []SubtitleSegment{
+			{StartTime: 0.5, EndTime: 1.999, Sentence: "hello"},
+			{StartTime: 2, EndTime: 3, Sentence: "two\nlines"},
+		}
+		out, err := ParseSRT(ConvertSegmentToSrtFormat(segs))
+		require.NoError(t, err)
+		require.Len(t, out, 2)
+		assert.Equal(t, segs[0].StartTime, out[0].StartTime)
+		assert.Equal(t, segs[0].EndTime, out[0].EndTime)
+		assert.Equal(t, "two\nlines", out[1].Sentence)
+		assert.Equal(t, 1, out[1].Idx)
+	})
+
+	t.Run("BOM, CRLF, 빈 줄 누락, 점 구분자", func(t *testing.T) {
+		input := "\ufeff1\r\n00:00:01.5 --> 00:00:02,000\r\nfirst\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\nsecond\r\n00:05,000 --> 00:06,250\r\nthird\r\n"
+		out, err := ParseSRT([]byte(input))
+		require.NoError(t, err)
+		require.Len(t, out, 3)
+		assert.Equal(t, 1.5, out[0].StartTime)
+		assert.Equal(t, "first", out[0].Sentence)
+		assert.Equal(t, "second", out[1].Sentence)
+		assert.Equal(t, 6.25, out[2].EndTime)
+	})
+
+	t.Run("숫자 본문", func(t *testing.T) {
+		input := "1\n00:00:01,000 --> 00:00:02,000\n2024\n00:00:02,000 --> 00:00:03,000\nyear\n2\n00:00:03,000 --> 00:00:04,000\n3\n"
+		out, err := ParseSRT([]byte(input))
+		require.NoError(t, err)
+		require.Len(t, out, 3)
+		assert.Equal(t, "2024", out[0].Sentence)
+		assert.Equal(t, "year", out[1].Sentence)
+		assert.Equal(t, "3", out[2].Sentence)
+	})
+
+	t.Run("줄 번호가 있는 에러", func(t *testing.T) {
+		_, err := ParseSRT([]byte("1\n00:00:01,000 --> 00:00:02,000\nok\n\n2\n00:00:61,000 --> 00:00:62,000\nbad\n"))
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, 6, parseErr.Line)
+
+		_, err = ParseSRT([]byte("orphan text\n"))
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, 1, parseErr.Line)
+
+		_, err = ParseSRT([]byte("1\n00:00:03,000 --> 00:00:02,000\nbackwards\n"))
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, 2, parseErr.Line)
+	})
+}
+
+func TestParseVTT(t *testing.T) {
+	t.Run("헤더, NOTE, cue 설정, 화자", func(t *testing.T) {
+		input := "WEBVTT - sample\nKind: captions\n\nNOTE 메모\n여러 줄\n\nintro\n00:01.000 --> 00:02.000 line:85%\n<v 진행자>a &lt; b\n\n00:00:02.000 --> 00:00:03.000\nplain\n"
+		out, err := ParseVTT([]byte(input))
+		require.NoError(t, err)
+		require.Len(t, out, 2)
+		assert.Equal(t, 1.0, out[0].StartTime)
+		assert.Equal(t, "a < b", out[0].Sentence)
+		assert.Equal(t, "진행자", out[0].Speaker)
+		assert.Equal(t, "plain", out[1].Sentence)
+		assert.Empty(t, out[1].Speaker)
+	})
+
+	t.Run("빈 줄 누락", func(t *testing.T) {
+		out, err := ParseVTT([]byte("WEBVTT\n\n00:01.000 --> 00:02.000\nHello\n00:02.000 --> 00:03.000\nWorld\n"))
+		require.NoError(t, err)
+		require.Len(t, out, 2)
+		assert.Equal(t, "Hello", out[0].Sentence)
+		assert.Equal(t, "World", out[1].Sentence)
+	})
+
+	t.Run("카라오케 라운드트립", func(t *testing.T) {
+		segs := []SubtitleSegment{{
+			StartTime:      1,
+			EndTime:        3,
+			Sentence:       "one two three",
+			Speaker:        "SPEAKER_00",
+			SentenceFrames: frames([]string{"one", "two", "three"}, 1, 0.6),
+		}}
+		data, err := Render(&VTTWriter{Karaoke: true, Options: WriteOptions{SpeakerPrefix: true}}, segs)
+		require.NoError(t, err)
+
+		out, err := ParseVTT(data)
+		require.NoError(t, err)
+		require.Len(t, out, 1)
+		assert.Equal(t, "one two three", out[0].Sentence)
+		assert.Equal(t, "SPEAKER_00", out[0].Speaker)
+		require.Len(t, out[0].SentenceFrames, 3)
+		assert.Equal(t, 1.6, out[0].SentenceFrames[1].WordStartTime)
+		assert.Equal(t, 2.2, out[0].SentenceFrames[1].WordEndTime)
+		assert.Equal(t, 3.0, out[0].SentenceFrames[2].WordEndTime)
+	})
+
+	t.Run("화자 이름 escape, 빈 첫 단어", func(t *testing.T) {
+		segs := []SubtitleSegment{{
+			StartTime:      1,
+			EndTime:        3,
+			Sentence:       "one two",
+			Speaker:        "A&B <host>",
+			SentenceFrames: frames([]string{" ", "one", "two"}, 1, 0.6),
+		}}
+		data, err := Render(&VTTWriter{Karaoke: true, Options: WriteOptions{SpeakerPrefix: true}}, segs)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "<v A&amp;B &lt;host&gt;><c>one</c> ")
+
+		out, err := ParseVTT(data)
+		require.NoError(t, err)
+		require.Len(t, out, 1)
+		assert.Equal(t, "A&B <host>", out[0].Speaker)
+		assert.Equal(t, "one two", out[0].Sentence)
+	})
+
+	_, err := ParseVTT([]byte("00:01.000 --> 00:02.000\nno header\n"))
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, 1, parseErr.Line)
+}
+
+func TestDisplayWidth(t *testing.T) {
+	assert.Equal(t, 5, DisplayWidth("안녕하세요"))
+	assert.Equal(t, 15, len("안녕하세요"))
+	assert.Equal(t, 3, DisplayWidth("a\nb\u200bc"))
+}
+
+func TestLineBreaker(t *testing.T) {
+	t.Run("한국어 두 줄 균형 + 조사 뒤 줄바꿈", func(t *testing.T) {
+		b := NewLineBreaker(KoreanProfile())
+		out := b.BreakLines("오늘 수업에서는 이차방정식의 근의 공식을 배워보겠습니다")
+		lines := strings.Split(out, "\n")
+		require.Len(t, lines, 2)
+		for _, line := range lines {
+			assert.LessOrEqual(t, DisplayWidth(line), 16, line)
+		}
+		assert.Equal(t, "오늘 수업에서는 이차방정식의", lines[0])
+
+		assert.Equal(t, "짧은 문장", b.BreakLines(" 짧은\n문장 "))
+	})
+
+	t.Run("일본어 금칙 처리", func(t *testing.T) {
+		b := NewLineBreaker(JapaneseProfile())
+		assert.Equal(t, "数学の試験があるので、\n早く寝ましょう。", b.BreakLines("数学の試験があるので、早く寝ましょう。"))
+
+		out := b.BreakLines("今日は二次方程式の解の公式を、一緒に勉強しましょう。")
+		lines := strings.Split(out, "\n")
+		require.Len(t, lines, 2)
+		for _, line := range lines {
+			assert.LessOrEqual(t, DisplayWidth(line), 13, line)
+			assert.False(t, strings.HasPrefix(line, "、") || strings.HasPrefix(line, "。"), line)
+		}
+	})
+
+	t.Run("한국어 cue 분할은 rune 기준 + 쉼표 우선", func(t *testing.T) {
+		words := []string{"먼저", "교과서", "삼십", "페이지를", "펴고,", "첫", "번째", "예제부터", "차근차근", "풀어봅시다"}
+		segs := []SubtitleSegment{{
+			Idx:            7,
+			StartTime:      0,
+			EndTime:        5,
+			Sentence:       strings.Join(words, " "),
+			Speaker:        "SPEAKER_00",
+			SentenceFrames: frames(words, 0, 0.5),
+		}}
+
+		out := NewLineBreaker(KoreanProfile()).Split(segs)
+		require.Len(t, out, 2)
+		assert.Equal(t, "먼저 교과서 삼십\n페이지를 펴고,", out[0].Sentence)
+		assert.Equal(t, 2.5, out[0].EndTime)
+		assert.Equal(t, 7, out[0].Idx)
+		assert.Equal(t, 8, out[1].Idx)
+		assert.Equal(t, "SPEAKER_00", out[1].Speaker)
+		assert.Len(t, out[1].SentenceFrames, 5)
+	})
+
+	t.Run("읽기 속도가 모자라면 다음 자막 전까지 늘림", func(t *testing.T) {
+		segs := []SubtitleSegment{
+			{StartTime: 0, EndTime: 0.5, Sentence: "아주 빠르게 지나가는 자막입니다"},
+			{StartTime: 1, EndTime: 2, Sentence: "다음"},
+		}
+		out := NewLineBreaker(KoreanProfile()).Split(segs)
+		assert.Equal(t, 1.0, out[0].EndTime)
+		assert.Equal(t, 2.0, out[1].EndTime)
+		assert.LessOrEqual(t, ReadingSpeed(out[1]), 12.0)
+	})
+
+	assert.Equal(t, "ja", ProfileForLanguage("JA").Language)
+	assert.Equal(t, "de", ProfileForLanguage("de").Language)
+	assert.Equal(t, 42, ProfileForLanguage("de").MaxLineChars)
+}
+
+// randomCues : 겹치거나 순서가 뒤섞이고 음수/역전된 시간이 섞인 임의의 자막 (property test 입력)
+type randomCues []SubtitleSegment
+
+func (randomCues) Generate(r *rand.Rand, size int) reflect.Value {
+	cues := make(randomCues, r.Intn(size+1))
+	for i := range cues {
+		start := r.Float64()*60 - 1
+		cues[i] = SubtitleSegment{
+			StartTime: start,
+			EndTime:   start + r.Float64()*8 - 1,
+			Sentence:  strings.Repeat("가", r.Intn(40)),
+		}
+	}
+	return reflect.ValueOf(cues)
+}
+
+func TestNormalizeTimingProperties(t *testing.T) {
+	configs := map[string]TimingConfig{
+		"default": DefaultTimingConfig(),
+		"23.976":  {MergeGap: 0.1, SplitGap: 0.5, MinGap: 0.083, MinDuration: 0.8, MinDurationPerChar: 0.06, MaxDuration: 6, FrameRate: FPS23976},
+		"25":      {MinGap: 0.04, MinDuration: 1, MaxDuration: 7, FrameRate: FPS25},
+		"29.97":   {SplitGap: 0.3, FrameRate: FPS2997},
+		"empty":   {},
+	}
+
+	for name, config := range configs {
+		t.Run(name, func(t *testing.T) {
+			frame := 0.001
+			if config.FrameRate > 0 {
+				frame = 1 / config.FrameRate
+			}
+
+			property := func(cues randomCues) bool {
+				out := NormalizeTiming(cues, config)
+				if len(out) != len(cues) {
+					return false
+				}
+
+				for i, seg := range out {
+					// 음수 시간, 길이 0 이하 없음
+					if seg.StartTime < 0 || seg.EndTime <= seg.StartTime {
+						t.Logf("invalid cue %d: %+v", i, seg)
+						return false
+					}
+					// 프레임 경계
+					if config.FrameRate > 0 {
+						for _, v := range []float64{seg.StartTime, seg.EndTime} {
+							if f := v / frame; math.Abs(f-math.Round(f)) > 1e-6 {
+								t.Logf("not on frame boundary %d: %v", i, v)
+								return false
+							}
+						}
+					}
+					// 겹침 없음 + 최소 간격
+					if i > 0 && seg.StartTime-out[i-1].EndTime < config.MinGap-1e-9 {
+						t.Logf("overlap %d: %+v %+v", i, out[i-1], seg)
+						return false
+					}
+					// 최대 길이 (스냅 반올림 1프레임 허용)
+					if config.MaxDuration > 0 && seg.EndTime-seg.StartTime > config.MaxDuration+frame+1e-9 {
+						t.Logf("too long %d: %+v", i, seg)
+						return false
+					}
+				}
+
+				// 어떤 writer 로 출력해도 겹치는 cue 가 없음
+				times := cueTimes(out)
+				for i := 1; i < len(times); i++ {
+					if times[i][0] < times[i-1][1] || times[i-1][0] < 0 {
+						return false
+					}
+				}
+				return true
+			}
+
+			require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 500, Rand: rand.New(rand.NewSource(1))}))
+		})
+	}
+}
+
+func TestNormalizeTiming(t *testing.T) {
+	config := DefaultTimingConfig()
+	segs := []SubtitleSegment{
+		{StartTime: 0, EndTime: 1.0, Sentence: "가나다"},
+		{StartTime: 1.02, EndTime: 2.0, Sentence: "라마바"},                  // 40ms 미만 갭 : 앞 자막을 늘림
+		{StartTime: 2.1, EndTime: 3.0, Sentence: "사아자"},                   // 150ms 미만 갭 : 가운데에서 나눔
+		{StartTime: 5.0, EndTime: 5.1, Sentence: strings.Repeat("차", 20)}, // 최소 표시 시간 (20자 x 50ms)
+		{StartTime: 10, EndTime: 20, Sentence: "길다"},                      // 최대 5초
+	}
+
+	out := NormalizeTiming(segs, config)
+	assert.Equal(t, 1.019, out[0].EndTime)
+	assert.Equal(t, 2.05, out[1].EndTime)
+	assert.Equal(t, 2.051, out[2].StartTime)
+	assert.Equal(t, 6.0, out[3].EndTime)
+	assert.Equal(t, 15.0, out[4].EndTime)
+
+	// 원본은 그대로
+	assert.Equal(t, 1.0, segs[0].EndTime)
+
+	// 다음 자막 때문에 뒤로 못 늘리면 앞으로 당김
+	out = NormalizeTiming([]SubtitleSegment{
+		{StartTime: 0, EndTime: 1, Sentence: "a"},
+		{StartTime: 3, EndTime: 3.1, Sentence: "b"},
+		{StartTime: 3.2, EndTime: 4, Sentence: "c"},
+	}, config)
+	assert.Equal(t, 2.699, out[1].StartTime)
+	assert.Equal(t, 3.199, out[1].EndTime)
+
+	// 프레임 스냅 (25fps = 40ms)
+	config = TimingConfig{FrameRate: FPS25}
+	out = NormalizeTiming([]SubtitleSegment{{StartTime: 1.013, EndTime: 2.031}}, config)
+	assert.InDelta(t, 1.0, out[0].StartTime, 1e-9)
+	assert.InDelta(t, 2.04, out[0].EndTime, 1e-9)
+
+	// writer 옵션으로 사용
+	timing := DefaultTimingConfig()
+	srt := string(ConvertSegmentToSrtFormatWithOptions(segs[:2], WriteOptions{Timing: &timing}))
+	assert.Contains(t, srt, "00:00:00,000 --> 00:00:01,019\n")
+	vtt, err := Render(&VTTWriter{Options: WriteOptions{Timing: &timing}}, segs[:2])
+	require.NoError(t, err)
+	assert.Contains(t, string(vtt), "00:00:00.000 --> 00:00:01.019\n")
+}
@@ -0,0 +1,77 @@
+package subtitle
+
+import (
+	"log"
+	"math"
+	"regexp"
+	"strings"
+
+	"example/stt/vad"
+)
+
+var multiSpace = regexp.MustCompile(`\s+`)
+
+// NormalizeWhitespace : 앞뒤 공백 제거 + 연속 공백을 한 칸으로
+func NormalizeWhitespace(s string) string {
+	s = strings.TrimSpace(s)
+	s = multiSpace.ReplaceAllString(s, " ")
+	return s
+}
+
+// RoundSeconds : 반올림 헬퍼: 2자리 소수로
+func RoundSeconds(x float64) float64 {
+	pow := math.Pow(10, 2) // 100
+	return math.Round(x*pow) / pow
+}
+
+// RemoveDuplicateTexts : 연속된 같은 텍스트 제거
+func RemoveDuplicateTexts(segments []SubtitleSegment) []SubtitleSegment {
+	if len(segments) == 0 {
+		return segments
+	}
+
+	filtered := make([]SubtitleSegment, 0, len(segments))
+	filtered = append(filtered, segments[0])
+
+	for i := 1; i < len(segments); i++ {
+		current := NormalizeWhitespace(segments[i].Sentence)
+		previous := NormalizeWhitespace(filtered[len(filtered)-1].Sentence)
+
+		// 연속된 같은 텍스트는 건너뜀
+		if current == previous {
+			log.Printf("반복 텍스트 제거: %.2fs-%.2fs \"%s\"\n",
+				segments[i].StartTime, segments[i].EndTime, current)
+			continue
+		}
+
+		filtered = append(filtered, segments[i])
+	}
+
+	return filtered
+}
+
+// FilterBySpeech : 음성구간이 아님에도 자막데이터가 만들어질 경우, 자막 생성에서 제외처리 (KOL-7916)
+// 시작 또는 끝이 VAD 구간(앞뒤 tolerance 초 허용)에 들어가면 인정, 완전히 벗어난 데이터만 제거
+// speech 가 비어있으면 필터링 하지 않음
+func FilterBySpeech(segments []SubtitleSegment, speech []vad.Segment, tolerance float64) []SubtitleSegment {
+	if len(speech) == 0 {
+		return segments
+	}
+
+	// 이진탐색 처리를 위해 데이터 정렬
+	SortSubtitleSegment(segments)
+	vad.SortSegments(speech)
+
+	valid := make([]SubtitleSegment, 0, len(segments))
+	for _, seg := range segments {
+		startIdx := vad.FindContainingSegment(speech, seg.StartTime, tolerance)
+		endIdx := vad.FindContainingSegment(speech, seg.EndTime, tolerance)
+
+		// 둘중에 하나라도 index 를 찾았으면 범위 안에 들어간다고 간주함
+		if startIdx != -1 || endIdx != -1 {
+			valid = append(valid, seg)
+		}
+	}
+
+	return valid
+}
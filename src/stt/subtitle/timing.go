@@ -0,0 +1,154 @@
+package subtitle
+
+import (
+	"math"
+	"strings"
+)
+
+// 자주 쓰는 프레임레이트 (FrameRate 에 그대로 넣으면 됨)
+const (
+	FPS23976 = 24000.0 / 1001
+	FPS25    = 25.0
+	FPS2997  = 30000.0 / 1001
+)
+
+// TimingConfig : 자막 타이밍 보정 규칙 (단위 초, 0 이면 해당 규칙 생략)
+// whisper json 타임스탬프는 말하는 순간만 잡혀서 자막이 깜빡이거나 너무 빨리 사라짐 -> 갭을 메우고 최소 표시 시간을 보장
+type TimingConfig struct {
+	MergeGap           float64 // 이보다 짧은 갭은 이전 자막 끝을 늘려서 붙임
+	SplitGap           float64 // 이보다 짧은 갭은 가운데 지점에서 나눠서 붙임
+	MinGap             float64 // 자막 사이 최소 간격 (겹침 방지, 프레임 스냅시 프레임 단위로 올림)
+	MinDuration        float64 // 최소 표시 시간
+	MinDurationPerChar float64 // 글자당 최소 표시 시간 (DisplayWidth 기준)
+	MaxDuration        float64 // 최대 표시 시간
+	FrameRate          float64 // 프레임 경계로 스냅 (23.976 / 25 / 29.97 ...), 0 이면 ms 단위
+}
+
+// DefaultTimingConfig : 20250918_vad_filter 의 SRT 보정값 (40/150ms 갭, 최소 0.5초 + 글자당 50ms, 최대 5초)
+func DefaultTimingConfig() TimingConfig {
+	return TimingConfig{
+		MergeGap:           0.04,
+		SplitGap:           0.15,
+		MinGap:             0.001,
+		MinDuration:        0.5,
+		MinDurationPerChar: 0.05,
+		MaxDuration:        5.0,
+	}
+}
+
+// NormalizeTiming : 시작 시간 순으로 정렬한 복사본의 타이밍을 규칙대로 보정
+// 결과는 항상 시작 >= 0, 끝 > 시작, 다음 자막 시작 - 끝 >= MinGap (겹침 없음)
+// 최소 표시 시간은 다음 자막을 침범하지 않는 범위에서만 보장 (앞 공백으로 먼저 늘리고, 그래도 모자라면 앞으로 당김)
+func NormalizeTiming(segments []SubtitleSegment, config TimingConfig) []SubtitleSegment {
+	out := make([]SubtitleSegment, len(segments))
+	copy(out, segments)
+	SortSubtitleSegment(out)
+
+	if len(out) == 0 {
+		return out
+	}
+
+	frameMs := 1.0
+	if config.FrameRate > 0 {
+		frameMs = 1000 / config.FrameRate
+	}
+
+	toMs := func(sec float64) int { return int(math.Round(sec * 1000)) }
+	mergeGap, splitGap, maxDuration := toMs(config.MergeGap), toMs(config.SplitGap), toMs(config.MaxDuration)
+	minGap := max(toMs(config.MinGap), 0)
+	if config.FrameRate > 0 {
+		minGap = int(math.Ceil(float64(minGap)/frameMs) * frameMs) // 스냅 후에도 간격이 유지되게 프레임 단위로 올림
+	}
+
+	times := make([][2]int, len(out)) // [startMs, endMs]
+	for i, seg := range out {
+		start := max(toMs(seg.StartTime), 0)
+		end := max(toMs(seg.EndTime), start)
+
+		if i > 0 {
+			prev := &times[i-1]
+			gap := start - prev[1]
+
+			switch {
+			case gap < 0:
+				// 겹침 : 이전 자막 끝을 자름
+				prev[1] = max(start-minGap, prev[0]+1)
+			case gap < mergeGap:
+				prev[1] = max(start-minGap, prev[0]+1)
+			case gap < splitGap:
+				mid := prev[1] + gap/2
+				prev[1] = max(mid-minGap/2, prev[1])
+				start = prev[1] + minGap
+			}
+			if maxDuration > 0 && prev[1]-prev[0] > maxDuration {
+				prev[1] = prev[0] + maxDuration
+			}
+
+			// 이전 자막을 더 줄일 수 없으면 이번 자막을 밀어냄
+			if start < prev[1]+minGap {
+				start = prev[1] + minGap
+			}
+			end = max(end, start)
+		}
+
+		// 최소 표시 시간 : 뒤로 먼저 늘리고 (다음 자막 전까지), 모자라면 앞으로 당김 (이전 자막 뒤까지)
+		need := max(toMs(config.MinDuration), toMs(float64(DisplayWidth(strings.TrimSpace(seg.Sentence)))*config.MinDurationPerChar))
+		if end-start < need {
+			limit := math.MaxInt
+			if i+1 < len(out) {
+				limit = toMs(out[i+1].StartTime) - minGap
+			}
+			end = max(end, min(start+need, limit))
+
+			if end-start < need {
+				floor := 0
+				if i > 0 {
+					floor = times[i-1][1] + minGap
+				}
+				start = min(start, max(end-need, floor))
+			}
+		}
+
+		if maxDuration > 0 && end-start > maxDuration {
+			end = start + maxDuration
+		}
+		if end <= start {
+			end = start + 1
+		}
+
+		times[i] = [2]int{start, end}
+	}
+
+	// 프레임 스냅 : 반올림으로 생긴 겹침은 다음 자막을 밀어서 해결
+	gapFrames := int(math.Round(float64(minGap) / frameMs))
+	prevEnd := math.MinInt / 2
+	for i := range out {
+		startFrame := int(math.Round(float64(times[i][0]) / frameMs))
+		endFrame := int(math.Round(float64(times[i][1]) / frameMs))
+
+		startFrame = max(startFrame, prevEnd+gapFrames, 0)
+		endFrame = max(endFrame, startFrame+1)
+		prevEnd = endFrame
+
+		out[i].StartTime = float64(startFrame) * frameMs / 1000
+		out[i].EndTime = float64(endFrame) * frameMs / 1000
+	}
+
+	return out
+}
+
+// withTiming : writer 공통 전처리 (Timing 옵션이 있으면 보정, 없으면 원본 그대로)
+// 인식 실패 placeholder 는 빈 cue 가 되니까 뺌 (실패 구간은 JSON / 작업 리포트의 failed_ranges 로 확인)
+func (o WriteOptions) withTiming(segments []SubtitleSegment) []SubtitleSegment {
+	cues := make([]SubtitleSegment, 0, len(segments))
+	for _, seg := range segments {
+		if !seg.Failed {
+			cues = append(cues, seg)
+		}
+	}
+
+	if o.Timing == nil {
+		return cues
+	}
+	return NormalizeTiming(cues, *o.Timing)
+}
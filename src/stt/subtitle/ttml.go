@@ -0,0 +1,114 @@
+package subtitle
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// TTMLWriter : TTML / DFXP (방송 납품용)
+// 화자는 ttm:agent 로 선언하고 p 에 연결, SpeakerPrefix 면 본문에도 이름을 붙임
+type TTMLWriter struct {
+	Options  WriteOptions
+	Language string // xml:lang, 비어있으면 en
+}
+
+func (t *TTMLWriter) Write(w io.Writer, segments []SubtitleSegment) error {
+	lang := t.Language
+	if lang == "" {
+		lang = "en"
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString(xml.Header)
+	buffer.WriteString(fmt.Sprintf(`<tt xmlns="http://www.w3.org/ns/ttml" xmlns:tts="http://www.w3.org/ns/ttml#styling" `+
+		`xmlns:ttm="http://www.w3.org/ns/ttml#metadata" xml:lang="%s">`+"\n", escapeXML(lang)))
+
+	buffer.WriteString("  <head>\n")
+	if speakers := speakerLabels(segments); len(speakers) > 0 {
+		buffer.WriteString("    <metadata>\n")
+		for i, label := range speakers {
+			buffer.WriteString(fmt.Sprintf(`      <ttm:agent xml:id="agent%d" type="person"><ttm:name type="full">%s</ttm:name></ttm:agent>`+"\n",
+				i+1, escapeXML(t.Options.SpeakerName(label))))
+		}
+		buffer.WriteString("    </metadata>\n")
+	}
+	buffer.WriteString("    <styling>\n")
+	buffer.WriteString(`      <style xml:id="default" tts:textAlign="center" tts:fontFamily="proportionalSansSerif" tts:color="white" tts:backgroundColor="transparent"/>` + "\n")
+	buffer.WriteString("    </styling>\n")
+	buffer.WriteString("    <layout>\n")
+	buffer.WriteString(`      <region xml:id="bottom" tts:origin="10% 80%" tts:extent="80% 15%" tts:displayAlign="after"/>` + "\n")
+	buffer.WriteString("    </layout>\n")
+	buffer.WriteString("  </head>\n")
+
+	buffer.WriteString(`  <body style="default" region="bottom">` + "\n")
+	buffer.WriteString("    <div>\n")
+
+	agents := make(map[string]int)
+	for i, label := range speakerLabels(segments) {
+		agents[label] = i + 1
+	}
+
+	segments = t.Options.withTiming(segments)
+	times := cueTimes(segments)
+	for idx, current := range segments {
+		agent := ""
+		if id, ok := agents[current.Speaker]; ok {
+			agent = fmt.Sprintf(` ttm:agent="agent%d"`, id)
+		}
+
+		lines := textLines(current.Sentence)
+		for i := range lines {
+			lines[i] = escapeXML(lines[i])
+		}
+		text := strings.Join(lines, "<br/>")
+		if prefix := t.Options.speakerPrefix(current.Speaker); prefix != "" {
+			text = escapeXML(prefix) + text
+		}
+
+		buffer.WriteString(fmt.Sprintf(`      <p xml:id="c%d" begin="%s" end="%s"%s>%s</p>`+"\n",
+			idx+1, FormatTTMLTime(times[idx][0]), FormatTTMLTime(times[idx][1]), agent, text))
+	}
+
+	buffer.WriteString("    </div>\n")
+	buffer.WriteString("  </body>\n")
+	buffer.WriteString("</tt>\n")
+
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
+
+func (t *TTMLWriter) Extension() string {
+	return ".ttml"
+}
+
+// speakerLabels : 등장하는 화자 라벨 (정렬)
+func speakerLabels(segments []SubtitleSegment) []string {
+	seen := make(map[string]struct{})
+	for _, seg := range segments {
+		if seg.Speaker != "" {
+			seen[seg.Speaker] = struct{}{}
+		}
+	}
+
+	labels := make([]string, 0, len(seen))
+	for label := range seen {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+func escapeXML(text string) string {
+	var buffer bytes.Buffer
+	xml.EscapeText(&buffer, []byte(text))
+	return buffer.String()
+}
+
+// FormatTTMLTime : timestamp format 변경 (ex. 20150 -> 00:00:20.150, clock-time)
+func FormatTTMLTime(ms int) string {
+	return FormatVTTTime(ms)
+}
@@ -0,0 +1,111 @@
+package subtitle
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// VTTWriter : WebVTT
+// 화자 표시는 voice 태그(<v 이름>), Karaoke 면 SentenceFrames 로 단어별 타임스탬프 태그(<00:00:01.200>) 삽입
+type VTTWriter struct {
+	Options     WriteOptions
+	CueSettings string // 타임라인 뒤에 붙는 cue 설정 (e.g. "line:85% position:50% align:center")
+	Karaoke     bool
+}
+
+func (v *VTTWriter) Write(w io.Writer, segments []SubtitleSegment) error {
+	var buffer bytes.Buffer
+	buffer.WriteString("WEBVTT\n\n")
+
+	segments = v.Options.withTiming(segments)
+	times := cueTimes(segments)
+	for idx, current := range segments {
+		v.writeCue(&buffer, current, times[idx][0], times[idx][1])
+	}
+
+	result := buffer.Bytes()
+	if len(result) > 1 && result[len(result)-1] == '\n' {
+		result = result[:len(result)-1]
+	}
+
+	_, err := w.Write(result)
+	return err
+}
+
+// AppendCue : cue 하나만 씀 (라이브 자막처럼 파일 뒤에 계속 붙이는 경우, WEBVTT 헤더는 호출자가 한번 씀)
+// 다음 cue 를 모르니까 겹침 보정은 하지 않음
+func (v *VTTWriter) AppendCue(w io.Writer, seg SubtitleSegment) error {
+	var buffer bytes.Buffer
+	v.writeCue(&buffer, seg, int(math.Round(seg.StartTime*1000)), int(math.Round(seg.EndTime*1000)))
+
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
+
+func (v *VTTWriter) writeCue(buffer *bytes.Buffer, seg SubtitleSegment, startMs, endMs int) {
+	timing := fmt.Sprintf("%s --> %s", FormatVTTTime(startMs), FormatVTTTime(endMs))
+	if v.CueSettings != "" {
+		timing += " " + v.CueSettings
+	}
+
+	text := v.cueText(seg, startMs, endMs)
+	if v.Options.SpeakerPrefix && seg.Speaker != "" {
+		text = fmt.Sprintf("<v %s>%s", escapeVTT(v.Options.SpeakerName(seg.Speaker)), text)
+	}
+
+	buffer.WriteString(timing + "\n")
+	buffer.WriteString(fmt.Sprintf("%s\n\n", text))
+}
+
+func (v *VTTWriter) Extension() string {
+	return ".vtt"
+}
+
+// cueText : 본문. 카라오케는 단어 시작 시간이 cue 범위 안에 있을때만 태그를 붙임 (범위 밖 태그는 플레이어가 무시하거나 깨짐)
+func (v *VTTWriter) cueText(seg SubtitleSegment, startMs, endMs int) string {
+	if !v.Karaoke || len(seg.SentenceFrames) == 0 {
+		return escapeVTT(strings.Join(textLines(seg.Sentence), "\n"))
+	}
+
+	var text strings.Builder
+	for _, frame := range seg.SentenceFrames {
+		word := escapeVTT(strings.TrimSpace(frame.Word))
+		if word == "" {
+			continue
+		}
+		first := text.Len() == 0 // 빈 frame 은 건너뛰니까 인덱스 대신 실제로 쓴 단어 기준
+		if !first {
+			text.WriteString(" ")
+		}
+
+		wordStart := int(math.Round(frame.WordStartTime * 1000))
+		if !first && wordStart > startMs && wordStart < endMs {
+			text.WriteString(fmt.Sprintf("<%s>", FormatVTTTime(wordStart)))
+		}
+		text.WriteString(fmt.Sprintf("<c>%s</c>", word))
+	}
+	return text.String()
+}
+
+// escapeVTT : cue 본문에서 의미있는 문자 escape
+func escapeVTT(text string) string {
+	text = strings.ReplaceAll(text, "&", "&amp;")
+	text = strings.ReplaceAll(text, "<", "&lt;")
+	text = strings.ReplaceAll(text, ">", "&gt;")
+	// "-->" 는 위에서 > 가 escape 되어서 안전함
+	return text
+}
+
+// ConvertSegmentToVttFormat : segments data WebVTT 포맷으로 변경 (겹침 처리는 SRT 와 동일)
+func ConvertSegmentToVttFormat(segments []SubtitleSegment, opts WriteOptions) []byte {
+	data, _ := Render(&VTTWriter{Options: opts}, segments) // bytes.Buffer 라 에러 없음
+	return data
+}
+
+// FormatVTTTime : timestamp format 변경 (ex. 20150 -> 00:00:20.150)
+func FormatVTTTime(ms int) string {
+	return strings.Replace(FormatSRTTime(ms), ",", ".", 1)
+}
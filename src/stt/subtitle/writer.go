@@ -0,0 +1,85 @@
+package subtitle
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+)
+
+// SubtitleWriter : 자막 포맷별 출력. 구현체는 SRTWriter, VTTWriter, ASSWriter, TTMLWriter
+type SubtitleWriter interface {
+	Write(w io.Writer, segments []SubtitleSegment) error
+	Extension() string // ".srt" 처럼 점 포함
+}
+
+// NewWriter : 포맷 이름(srt, vtt, ass, ssa, ttml, dfxp)으로 기본 설정 writer 생성
+func NewWriter(format string, opts WriteOptions) (SubtitleWriter, error) {
+	switch strings.ToLower(strings.TrimPrefix(format, ".")) {
+	case "srt":
+		return &SRTWriter{Options: opts}, nil
+	case "vtt", "webvtt":
+		return &VTTWriter{Options: opts}, nil
+	case "ass", "ssa":
+		return &ASSWriter{Options: opts, Style: DefaultASSStyle()}, nil
+	case "ttml", "dfxp", "xml":
+		return &TTMLWriter{Options: opts, Language: "en"}, nil
+	default:
+		return nil, fmt.Errorf("unsupported subtitle format: %s", format)
+	}
+}
+
+// Render : writer 결과를 바이트로 반환
+func Render(writer SubtitleWriter, segments []SubtitleSegment) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := writer.Write(&buffer, segments); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// SaveSubtitle : outputPath 에 writer 포맷으로 저장
+func SaveSubtitle(writer SubtitleWriter, segments []SubtitleSegment, outputPath string) error {
+	data, err := Render(writer, segments)
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("file write failed: %w", err)
+	}
+
+	return nil
+}
+
+// cueTimes : 세그먼트별 [start, end] ms. 다음 자막 시작을 넘는 끝 시간은 잘라냄 (SRT 와 동일 규칙)
+func cueTimes(segments []SubtitleSegment) [][2]int {
+	times := make([][2]int, len(segments))
+	for idx, current := range segments {
+		startTime := int(math.Round(current.StartTime * 1000))
+		endTime := int(math.Round(current.EndTime * 1000))
+
+		// 다음 segment 시작 시간보다 크면 안되니까 조정함
+		if idx < len(segments)-1 {
+			nextStart := int(math.Round(segments[idx+1].StartTime * 1000))
+			if endTime > nextStart {
+				endTime = nextStart - 1
+			}
+		}
+		times[idx] = [2]int{startTime, endTime}
+	}
+	return times
+}
+
+// textLines : 문장을 줄 단위로 (빈 줄은 제거, 빈 줄이 cue 를 끊는 포맷이 있어서)
+func textLines(sentence string) []string {
+	lines := make([]string, 0, 2)
+	for _, line := range strings.Split(strings.TrimSpace(sentence), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,173 @@
+package vad
+
+import "math"
+
+// SoftGateAtt : Whisper 환각 방지를 위해 완전히 0이 아닌 작은 값으로 감쇠
+const SoftGateAtt = 0.1
+
+// ApplySoftGate : VAD 세그먼트 + 에너지 마스크 밖의 구간을 SoftGateAtt 로 감쇠한 복사본 반환 (전체 길이 유지)
+// 세그먼트가 하나도 없으면 에너지 마스크만으로 구간을 살림
+func ApplySoftGate(pcm []float32, sr int, segments []Segment) []float32 {
+	processed := make([]float32, len(pcm))
+	copy(processed, pcm)
+
+	noise := percentile(FrameRMS(pcm, sr, rmsWinSec, rmsHopSec), 20) // 노이즈 바닥
+	energyThr := noise * 1.6                                         // 1.4~1.8 사이 튜닝 권장
+
+	var mask []bool
+	if len(segments) == 0 {
+		// VAD가 전부 놓쳤다면: 에너지 마스크로라도 구간을 살림
+		mask = BuildEnergyMask(pcm, sr, rmsWinSec, rmsHopSec, energyThr)
+
+		// 마스크가 거의 전부 false일 수도 있으니, 최소 완충을 위해 dilation
+		DilateSpeechMask(mask, sr, 150, 250)
+	} else {
+		mask = SegmentMask(segments, sr, len(pcm))
+
+		// 에너지 마스크 OR 결합
+		energyMask := BuildEnergyMask(pcm, sr, rmsWinSec, rmsHopSec, energyThr)
+		for i := range mask {
+			mask[i] = mask[i] || energyMask[i]
+		}
+
+		DilateSpeechMask(mask, sr, 200, 300)
+	}
+
+	for i := range processed {
+		if !mask[i] {
+			processed[i] *= SoftGateAtt
+		}
+	}
+
+	// 경계 페이드 + 감쇠
+	ApplyBoundaryFades(processed, mask, sr, 35, 45, SoftGateAtt)
+
+	return processed
+}
+
+// SegmentMask : 세그먼트를 샘플 단위 마스크로 변환 (end 가 0 이하이거나 범위를 넘으면 파일 끝까지)
+func SegmentMask(segments []Segment, sr int, n int) []bool {
+	mask := make([]bool, n)
+	for _, segment := range segments {
+		startSample := int(segment.SpeechStartAt * float64(sr))
+		endSample := int(segment.SpeechEndAt * float64(sr))
+		if endSample <= 0 || endSample > n {
+			endSample = n
+		}
+		if startSample < 0 {
+			startSample = 0
+		}
+		for j := startSample; j < endSample; j++ {
+			mask[j] = true
+		}
+	}
+	return mask
+}
+
+// BuildEnergyMask : RMS 기반 에너지 마스크 생성 (win/hop 단위 RMS를 샘플 마스크로 확장)
+func BuildEnergyMask(pcm []float32, sr int, winSec, hopSec float64, energyThr float64) []bool {
+	n := len(pcm)
+	mask := make([]bool, n)
+
+	win := int(winSec * float64(sr))
+	hop := int(hopSec * float64(sr))
+	rms := FrameRMS(pcm, sr, winSec, hopSec)
+	if len(rms) == 0 {
+		return mask
+	}
+
+	// 프레임 히트를 샘플 마스크로 펼치기
+	s := 0
+	for i := 0; i+win <= n && s < len(rms); i += hop {
+		if rms[s] > energyThr {
+			for j := i; j < i+win; j++ {
+				mask[j] = true
+			}
+		}
+		s++
+	}
+	return mask
+}
+
+// DilateSpeechMask : speechMask를 앞/뒤로 확장해 경계 누락 메움
+func DilateSpeechMask(mask []bool, sr int, preMs, postMs int) {
+	n := len(mask)
+	pre := int(float64(sr) * float64(preMs) / 1000.0)
+	post := int(float64(sr) * float64(postMs) / 1000.0)
+	if pre < 0 {
+		pre = 0
+	}
+	if post < 0 {
+		post = 0
+	}
+
+	src := make([]bool, n)
+	copy(src, mask)
+
+	for i := 0; i < n; i++ {
+		if src[i] {
+			st := i - pre
+			if st < 0 {
+				st = 0
+			}
+			en := i + post
+			if en >= n {
+				en = n - 1
+			}
+			for j := st; j <= en; j++ {
+				mask[j] = true
+			}
+		}
+	}
+}
+
+// ApplyBoundaryFades : 경계에서 페이드아웃(speech->non), 페이드인(non->speech)
+func ApplyBoundaryFades(data []float32, speechMask []bool, sampleRate, fadeOutMs, fadeInMs int, softGate float32) {
+	n := len(data)
+	if n == 0 {
+		return
+	}
+	fo := int(float64(sampleRate) * float64(fadeOutMs) / 1000.0)
+	fi := int(float64(sampleRate) * float64(fadeInMs) / 1000.0)
+	if fo < 1 {
+		fo = 1
+	}
+	if fi < 1 {
+		fi = 1
+	}
+
+	// speech -> non-speech : 페이드아웃 (코사인 곡선)
+	for i := 1; i < n; i++ {
+		if speechMask[i-1] && !speechMask[i] {
+			end := i - 1
+			start := end - fo + 1
+			if start < 0 {
+				start = 0
+			}
+			span := end - start + 1
+			for k := 0; k < span; k++ {
+				theta := float64(k+1) / float64(span+1) * math.Pi * 0.5
+				alpha := float32(math.Cos(theta)) // 1..0
+				data[start+k] *= alpha
+			}
+		}
+	}
+
+	// non-speech -> speech : 페이드인 (softGate -> 1.0)
+	for i := 1; i < n; i++ {
+		if !speechMask[i-1] && speechMask[i] {
+			start := i
+			end := start + fi - 1
+			if end >= n {
+				end = n - 1
+			}
+			span := end - start + 1
+			for k := 0; k < span; k++ {
+				theta := float64(k+1) / float64(span+1) * math.Pi * 0.5
+				alpha := float32(math.Sin(theta)) // 0..1
+				gain := softGate + (1.0-softGate)*alpha
+				data[start+k] *= gain
+			}
+		}
+	}
+}
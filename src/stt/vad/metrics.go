@@ -0,0 +1,206 @@
+package vad
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	rmsWinSec = 0.020 // RMS window 20ms
+	rmsHopSec = 0.010 // RMS hop 10ms
+)
+
+// PadMetrics : VAD Filter 적용 전 speech pad, min silence 결정을 위한 객체
+type PadMetrics struct {
+	SNRdB                 float64
+	AvgSilenceSec         float64
+	ShortSilenceRatio     float64
+	NoiseFloorRMS         float64
+	SpeechLevelRMS        float64
+	RawPadMs              float64
+	FinalPadMs            int
+	SuggestedMinSilenceMs int
+}
+
+// VADSidecar : 필터 관련 활용값이 필요하다면
+type VADSidecar struct {
+	SampleRate    int       `json:"sample_rate"`
+	HopSec        float64   `json:"hop_sec"`         // e.g. 0.01
+	NoiseFloorRMS float64   `json:"noise_floor_rms"` // 파일 레벨 바닥
+	RMSEnvelope   []float64 `json:"rms_envelope"`    // 10ms frame RMS
+}
+
+// EstimatePadAndMinSilence : pcm, sr 기준으로 speech_pad, min_silence_duration_ms 설정(파일에 따른 유동화)
+func EstimatePadAndMinSilence(pcm []float32, sr int) PadMetrics {
+	return EstimatePadAndMinSilenceFromRMS(FrameRMS(pcm, sr, rmsWinSec, rmsHopSec))
+}
+
+// EstimatePadAndMinSilenceFromRMS : 20ms window / 10ms hop RMS 엔벨로프 기준으로 pad, min silence 계산
+// 스트리밍 경로에서는 엔벨로프만 모아서 호출하므로 PCM 전체를 들고있지 않아도 됨
+func EstimatePadAndMinSilenceFromRMS(rms []float64) PadMetrics {
+	m := PadMetrics{FinalPadMs: 200, SuggestedMinSilenceMs: 800}
+
+	if len(rms) == 0 {
+		return m
+	}
+
+	m.NoiseFloorRMS = percentile(rms, 20)
+	m.SpeechLevelRMS = percentile(rms, 80)
+	noise := m.NoiseFloorRMS
+	if noise <= 1e-9 {
+		noise = 1e-9
+	}
+	m.SNRdB = 20.0 * math.Log10(m.SpeechLevelRMS/noise)
+
+	// 침묵 통계 (임계치 = noise*2.0)
+	silThr := m.NoiseFloorRMS * 2.0
+	m.AvgSilenceSec, m.ShortSilenceRatio = estimateSilenceStats(rms, rmsHopSec, silThr)
+
+	// 하한/상한선 지정
+	var capHi float64
+	switch {
+	case m.SNRdB >= 18:
+		capHi = 500
+	case m.SNRdB >= 15:
+		capHi = 600
+	case m.SNRdB >= 10:
+		capHi = 700
+	default:
+		capHi = 850
+	}
+
+	base := 300.0
+	snrAdj := clamp((15.0-m.SNRdB)*6.0, 0, 160)
+	silAdj := clamp((0.30-m.AvgSilenceSec)*200.0, 0, 150) // 평균 침묵이 0.3s보다 짧으면 증가
+	shortAdj := clamp(m.ShortSilenceRatio*100.0, 0, 120)
+	m.RawPadMs = base + snrAdj + silAdj + shortAdj
+	m.FinalPadMs = int(clamp(m.RawPadMs, 400, capHi))
+
+	minSil := 650.0
+	minSil += clamp(m.ShortSilenceRatio*400.0, 0, 300)
+	minSil += clamp((m.SNRdB-18.0)*12.0, 0, 120)
+	minSil -= clamp((m.AvgSilenceSec-0.45)*500.0, 0, 200)
+
+	// 하한/상한선 지정
+	m.SuggestedMinSilenceMs = int(clamp(minSil, 400, 1200))
+	return m
+}
+
+// FrameRMS : winSec 윈도우, hopSec 간격으로 RMS 계산
+func FrameRMS(pcm []float32, sr int, winSec, hopSec float64) []float64 {
+	win := int(winSec * float64(sr))
+	hop := int(hopSec * float64(sr))
+	if win <= 0 || hop <= 0 || len(pcm) < win {
+		return nil
+	}
+	out := make([]float64, 0, 1+len(pcm)/hop)
+	for i := 0; i+win <= len(pcm); i += hop {
+		out = append(out, windowRMS(pcm[i:i+win]))
+	}
+	return out
+}
+
+func windowRMS(pcm []float32) float64 {
+	var s float64
+	for _, v := range pcm {
+		x := float64(v)
+		s += x * x
+	}
+	return math.Sqrt(s / float64(len(pcm)))
+}
+
+// RMSAccumulator : FrameRMS 를 스트리밍으로 계산 (결과는 FrameRMS 와 동일)
+type RMSAccumulator struct {
+	win     int
+	hop     int
+	pending []float32
+	rms     []float64
+}
+
+// NewRMSAccumulator : 20ms window / 10ms hop 기준 누적기
+func NewRMSAccumulator(sr int) *RMSAccumulator {
+	win := int(rmsWinSec * float64(sr))
+	return &RMSAccumulator{
+		win:     win,
+		hop:     int(rmsHopSec * float64(sr)),
+		pending: make([]float32, 0, win*2),
+		rms:     make([]float64, 0),
+	}
+}
+
+func (a *RMSAccumulator) Write(pcm []float32) {
+	if a.win <= 0 || a.hop <= 0 {
+		return
+	}
+
+	a.pending = append(a.pending, pcm...)
+
+	offset := 0
+	for offset+a.win <= len(a.pending) {
+		a.rms = append(a.rms, windowRMS(a.pending[offset:offset+a.win]))
+		offset += a.hop
+	}
+
+	// 아직 프레임을 못 만든 샘플만 남김
+	n := copy(a.pending, a.pending[offset:])
+	a.pending = a.pending[:n]
+}
+
+// Envelope : 지금까지 계산된 RMS 엔벨로프
+func (a *RMSAccumulator) Envelope() []float64 {
+	return a.rms
+}
+
+func estimateSilenceStats(rms []float64, hopSec float64, thr float64) (float64, float64) {
+	var cur float64
+	var sils []float64
+	for _, v := range rms {
+		if v < thr {
+			cur += hopSec
+		} else if cur > 0 {
+			sils = append(sils, cur)
+			cur = 0
+		}
+	}
+	if cur > 0 {
+		sils = append(sils, cur)
+	}
+	if len(sils) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	var short int
+	for _, s := range sils {
+		sum += s
+		if s < 0.3 {
+			short++
+		} // 300ms 미만
+	}
+	return sum / float64(len(sils)), float64(short) / float64(len(sils))
+}
+
+func percentile(xs []float64, p int) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	tmp := append([]float64(nil), xs...)
+	sort.Float64s(tmp)
+	if p <= 0 {
+		return tmp[0]
+	}
+	if p >= 100 {
+		return tmp[len(tmp)-1]
+	}
+	idx := int(math.Round((float64(p) / 100.0) * float64(len(tmp)-1)))
+	return tmp[idx]
+}
+
+func clamp(x, lo, hi float64) float64 {
+	if x < lo {
+		return lo
+	}
+	if x > hi {
+		return hi
+	}
+	return x
+}
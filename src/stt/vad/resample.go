@@ -0,0 +1,245 @@
+package vad
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// resampleZeroCrossings : 싱크 커널 한쪽에 들어가는 영점 개수 (클수록 전이대역이 좁아짐)
+	resampleZeroCrossings = 16
+
+	// resampleRolloff : 나이퀴스트 대비 차단 주파수 비율 (전이대역을 나이퀴스트 아래에 둬서 에일리어싱 방지)
+	resampleRolloff = 0.95
+
+	// resampleKaiserBeta : 카이저 윈도우 beta (약 80dB 저지대역 감쇠)
+	resampleKaiserBeta = 8.6
+)
+
+// DownMixMode : 다채널 -> 모노 변환 방식
+type DownMixMode int
+
+const (
+	DownMixAverage DownMixMode = iota // 전 채널 평균 (기본값)
+	DownMixChannel                    // 지정한 채널 하나만 사용 (한쪽 채널에만 마이크가 물린 경우)
+)
+
+// DownMixConfig : 다운믹스 설정. Channel 은 DownMixChannel 일때만 사용 (0부터 시작)
+type DownMixConfig struct {
+	Mode    DownMixMode
+	Channel int
+}
+
+// DownMix : 인터리브된 PCM 을 모노로 변환. channels 가 1이면 복사본 반환
+func DownMix(interleaved []float32, channels int, config DownMixConfig) ([]float32, error) {
+	if channels <= 0 {
+		return nil, fmt.Errorf("invalid channel count: %d", channels)
+	}
+	if config.Mode == DownMixChannel && (config.Channel < 0 || config.Channel >= channels) {
+		return nil, fmt.Errorf("down-mix channel %d out of range (%dch)", config.Channel, channels)
+	}
+
+	frames := len(interleaved) / channels
+	out := make([]float32, frames)
+	if channels == 1 {
+		copy(out, interleaved)
+		return out, nil
+	}
+
+	for i := 0; i < frames; i++ {
+		frame := interleaved[i*channels : (i+1)*channels]
+		switch config.Mode {
+		case DownMixChannel:
+			out[i] = frame[config.Channel]
+		default:
+			var sum float32
+			for _, v := range frame {
+				sum += v
+			}
+			out[i] = sum / float32(channels)
+		}
+	}
+	return out, nil
+}
+
+// Resampler : 카이저 윈도우 싱크 기반 폴리페이즈 리샘플러
+// inSR:outSR 을 기약분수 L/M 으로 만들고, 출력 샘플이 떨어지는 입력 위치의 소수부는 L 가지뿐이므로
+// 위상별 필터 계수를 미리 계산해둠. Process 를 여러번 나눠 호출해도 결과는 한번에 처리한 것과 같음
+type Resampler struct {
+	inSR  int
+	outSR int
+	l     int64 // 업샘플 비율
+	m     int64 // 다운샘플 비율
+	half  int   // 한쪽 탭 수
+	table [][]float32
+
+	buf      []float32 // 입력 히스토리. buf[0] 은 입력 인덱스 bufStart
+	bufStart int64
+	inTotal  int64 // 지금까지 받은 입력 샘플 수
+	next     int64 // 다음 출력 샘플 인덱스
+}
+
+// NewResampler : inSR -> outSR 리샘플러 생성
+func NewResampler(inSR, outSR int) (*Resampler, error) {
+	if inSR <= 0 || outSR <= 0 {
+		return nil, fmt.Errorf("invalid sample rate: %d -> %d", inSR, outSR)
+	}
+
+	g := gcd(inSR, outSR)
+	l := outSR / g
+	m := inSR / g
+
+	// 다운샘플일때는 출력 나이퀴스트 기준으로 차단 주파수를 낮추고, 그만큼 커널을 넓힘
+	scale := resampleRolloff
+	if outSR < inSR {
+		scale *= float64(outSR) / float64(inSR)
+	}
+	half := int(math.Ceil(resampleZeroCrossings / scale))
+
+	r := &Resampler{
+		inSR:     inSR,
+		outSR:    outSR,
+		l:        int64(l),
+		m:        int64(m),
+		half:     half,
+		table:    buildSincTable(l, half, scale),
+		buf:      make([]float32, half), // 시작 전 구간은 0으로 간주
+		bufStart: -int64(half),
+	}
+	return r, nil
+}
+
+// buildSincTable : 위상 p(소수부 p/l)마다 입력 [base-half+1, base+half] 에 곱할 계수
+func buildSincTable(l, half int, scale float64) [][]float32 {
+	table := make([][]float32, l)
+	norm := besselI0(resampleKaiserBeta)
+
+	for p := 0; p < l; p++ {
+		frac := float64(p) / float64(l)
+		taps := make([]float64, 2*half)
+
+		var sum float64
+		for j := range taps {
+			d := frac + float64(half-1-j) // 출력 위치와 입력 샘플 사이 거리
+			x := d / float64(half)
+			if math.Abs(x) >= 1 {
+				continue
+			}
+			w := besselI0(resampleKaiserBeta*math.Sqrt(1-x*x)) / norm
+			taps[j] = scale * sinc(scale*d) * w
+			sum += taps[j]
+		}
+
+		// DC 이득을 1로 맞춤
+		coeffs := make([]float32, 2*half)
+		for j, v := range taps {
+			coeffs[j] = float32(v / sum)
+		}
+		table[p] = coeffs
+	}
+	return table
+}
+
+// Process : 입력을 이어서 넣고, 계산 가능한 출력만 반환 (나머지는 다음 호출이나 Flush 에서 나옴)
+func (r *Resampler) Process(in []float32) []float32 {
+	r.buf = append(r.buf, in...)
+	r.inTotal += int64(len(in))
+
+	return r.drain(r.bufStart + int64(len(r.buf)))
+}
+
+// Flush : 스트림 종료. 남은 입력 뒤를 0으로 채워서 마지막 출력까지 계산
+func (r *Resampler) Flush() []float32 {
+	r.buf = append(r.buf, make([]float32, r.half)...)
+
+	limit := r.bufStart + int64(len(r.buf))
+	out := make([]float32, 0)
+	for {
+		num := r.next * r.m
+		if num >= r.inTotal*r.l {
+			break
+		}
+		base := num / r.l
+		if base+int64(r.half) >= limit {
+			break
+		}
+		out = append(out, r.compute(base, int(num%r.l)))
+		r.next++
+	}
+	return out
+}
+
+func (r *Resampler) drain(limit int64) []float32 {
+	out := make([]float32, 0, int(int64(len(r.buf))*r.l/r.m)+1)
+	for {
+		num := r.next * r.m
+		base := num / r.l
+		if base+int64(r.half) >= limit {
+			break
+		}
+		out = append(out, r.compute(base, int(num%r.l)))
+		r.next++
+	}
+
+	// 다음 출력에 더 이상 필요없는 앞부분 정리
+	keepFrom := (r.next*r.m)/r.l - int64(r.half) + 1
+	if drop := keepFrom - r.bufStart; drop > 0 {
+		n := copy(r.buf, r.buf[drop:])
+		r.buf = r.buf[:n]
+		r.bufStart = keepFrom
+	}
+	return out
+}
+
+func (r *Resampler) compute(base int64, phase int) float32 {
+	coeffs := r.table[phase]
+	start := int(base - int64(r.half) + 1 - r.bufStart)
+
+	var acc float32
+	for j, c := range coeffs {
+		acc += c * r.buf[start+j]
+	}
+	return acc
+}
+
+// Resample : 전체 PCM 을 한번에 리샘플링
+func Resample(in []float32, inSR, outSR int) ([]float32, error) {
+	if inSR == outSR {
+		out := make([]float32, len(in))
+		copy(out, in)
+		return out, nil
+	}
+
+	r, err := NewResampler(inSR, outSR)
+	if err != nil {
+		return nil, err
+	}
+	return append(r.Process(in), r.Flush()...), nil
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// besselI0 : 0차 변형 베셀 함수 (급수 전개)
+func besselI0(x float64) float64 {
+	sum, term := 1.0, 1.0
+	for k := 1; k < 50; k++ {
+		term *= (x / (2 * float64(k))) * (x / (2 * float64(k)))
+		sum += term
+		if term < sum*1e-12 {
+			break
+		}
+	}
+	return sum
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,249 @@
+package vad
+
+import (
+	"math"
+	"sort"
+)
+
+// Segment : 음성 구간 (초 단위)
+// silero-vad-go 의 speech.Segment 와 필드 구성이 같아서 vad.Segment(seg) 로 바로 변환 가능
+type Segment struct {
+	SpeechStartAt float64 `json:"speech_start_at"`
+	SpeechEndAt   float64 `json:"speech_end_at"`
+}
+
+// Duration : 구간 길이(초)
+func (s Segment) Duration() float64 {
+	return s.SpeechEndAt - s.SpeechStartAt
+}
+
+// PostProcessConfig : raw VAD 세그먼트 후처리 단계별 파라미터
+type PostProcessConfig struct {
+	MinSegmentSec float64 // 1. 이보다 짧은 raw 세그먼트 제거
+	MergeGapSec   float64 // 2. 간격이 이 이하면 병합
+	ShortGapMs    int     // 3. 짧은 무성 병합 (히스테리시스 release)
+	MinSpeechMs   int     // 4. 짧은 발화 제거 (히스테리시스 enter)
+	PrePaddingMs  int     // 5. 앞쪽 패딩
+	PostPaddingMs int     // 5. 뒤쪽 패딩
+}
+
+// DefaultPostProcessConfig : 20251218_audio_chunks 에서 튜닝한 값
+func DefaultPostProcessConfig() PostProcessConfig {
+	return PostProcessConfig{
+		MinSegmentSec: 0.2,
+		MergeGapSec:   1.5,
+		ShortGapMs:    600,
+		MinSpeechMs:   120,
+		PrePaddingMs:  300,
+		PostPaddingMs: 500,
+	}
+}
+
+// PostProcess : raw 세그먼트 후처리 (짧은 구간 제거 -> 병합 -> 짧은 무성 병합 -> 짧은 발화 제거 -> 비대칭 패딩)
+// totalDuration : end 가 0 으로 들어온 세그먼트(파일 끝까지 발화)를 보정할 때 사용
+func PostProcess(segments []Segment, totalDuration float64, config PostProcessConfig) []Segment {
+	// 1. 너무 짧은 세그먼트 제거
+	filtered := make([]Segment, 0, len(segments))
+	for _, seg := range segments {
+		if seg.SpeechEndAt <= 0 {
+			seg.SpeechEndAt = totalDuration
+		}
+
+		duration := seg.Duration()
+		if duration < 0 || duration < config.MinSegmentSec {
+			continue
+		}
+		filtered = append(filtered, seg)
+	}
+
+	// 2. 가까운 세그먼트 병합
+	out := MergeCloseSegments(filtered, config.MergeGapSec)
+
+	// 3. 짧은 무성 병합
+	out = MergeShortGaps(out, config.ShortGapMs)
+
+	// 4. 짧은 발화 제거
+	out = DropShortSpeech(out, config.MinSpeechMs)
+
+	// 5. 비대칭 패딩
+	return ApplyAsymmetricPadding(out, config.PrePaddingMs, config.PostPaddingMs)
+}
+
+// SortSegments : start_at 오름차순으로 객체 정렬
+func SortSegments(segments []Segment) {
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].SpeechStartAt < segments[j].SpeechStartAt
+	})
+}
+
+// AvgDuration : 세그먼트 평균 길이 계산
+func AvgDuration(segments []Segment) float64 {
+	if len(segments) == 0 {
+		return 0.0
+	}
+	var total float64
+	for _, seg := range segments {
+		total += seg.Duration()
+	}
+	return total / float64(len(segments))
+}
+
+// MergeCloseSegments : 간격이 maxGap(초) 이하인 세그먼트는 합침 (입력은 정렬되어 있어야 함)
+func MergeCloseSegments(segments []Segment, maxGap float64) []Segment {
+	if len(segments) <= 1 {
+		return segments
+	}
+
+	merged := []Segment{segments[0]}
+
+	for i := 1; i < len(segments); i++ {
+		prev := &merged[len(merged)-1]
+		curr := segments[i]
+
+		if curr.SpeechStartAt-prev.SpeechEndAt <= maxGap {
+			if curr.SpeechEndAt > prev.SpeechEndAt {
+				prev.SpeechEndAt = curr.SpeechEndAt
+			}
+		} else {
+			merged = append(merged, curr)
+		}
+	}
+
+	return merged
+}
+
+// MergeOverlapping : 정렬 후 gap <= maxGapSec 이면 병합 (입력 슬라이스 순서가 바뀜)
+func MergeOverlapping(segments []Segment, maxGapSec float64) []Segment {
+	if len(segments) <= 1 {
+		return segments
+	}
+	SortSegments(segments)
+	return MergeCloseSegments(segments, maxGapSec)
+}
+
+// MergeShortGaps : 짧은 무성 병합(히스테리시스의 "release" 완화 효과)
+func MergeShortGaps(segments []Segment, maxGapMs int) []Segment {
+	return MergeOverlapping(segments, float64(maxGapMs)/1000.0)
+}
+
+// DropShortSpeech : 짧은 발화 제거(히스테리시스의 "enter" 강화 효과)
+func DropShortSpeech(segments []Segment, minSpeechMs int) []Segment {
+	minDur := float64(minSpeechMs) / 1000.0
+	out := make([]Segment, 0, len(segments))
+	for _, s := range segments {
+		if s.Duration() >= minDur {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ApplyAsymmetricPadding : 앞(preMs)과 뒤(postMs)를 다르게 늘리고, 겹치는 세그먼트는 병합
+func ApplyAsymmetricPadding(segments []Segment, preMs, postMs int) []Segment {
+	if len(segments) == 0 {
+		return segments
+	}
+
+	pre := float64(preMs) / 1000.0
+	post := float64(postMs) / 1000.0
+
+	out := make([]Segment, 0, len(segments))
+	for _, s := range segments {
+		st := s.SpeechStartAt - pre
+		en := s.SpeechEndAt + post
+		if st < 0 {
+			st = 0
+		}
+		if en < st {
+			en = st
+		}
+		out = append(out, Segment{SpeechStartAt: st, SpeechEndAt: en})
+	}
+
+	return MergeOverlapping(out, 0.0)
+}
+
+// RemoveIsolatedNoise : 앞뒤로 minIsolationGap 이상 무음에 둘러싸인 maxNoiseDuration 이하 구간은 노이즈로 판단해서 제거
+func RemoveIsolatedNoise(segments []Segment, minIsolationGap, maxNoiseDuration float64) []Segment {
+	if len(segments) <= 1 {
+		return segments
+	}
+
+	cleaned := make([]Segment, 0, len(segments))
+	for i, seg := range segments {
+		if seg.Duration() > maxNoiseDuration {
+			cleaned = append(cleaned, seg)
+			continue
+		}
+
+		// 첫/마지막 구간은 한쪽 간격만 확인
+		isolatedBefore := i == 0 || seg.SpeechStartAt-segments[i-1].SpeechEndAt >= minIsolationGap
+		isolatedAfter := i == len(segments)-1 || segments[i+1].SpeechStartAt-seg.SpeechEndAt >= minIsolationGap
+
+		if !(isolatedBefore && isolatedAfter) {
+			cleaned = append(cleaned, seg)
+		}
+	}
+
+	return cleaned
+}
+
+// GetOverlapRatio : 자막 구간 대비 VAD 구간과 겹치는 비율
+func GetOverlapRatio(segStart, segEnd, vadStart, vadEnd float64) float64 {
+	if segEnd <= vadStart || vadEnd <= segStart {
+		return 0.0 // 겹침 없음
+	}
+
+	segDuration := segEnd - segStart
+	if segDuration <= 0 {
+		return 0.0
+	}
+
+	overlapDuration := math.Min(segEnd, vadEnd) - math.Max(segStart, vadStart)
+	return overlapDuration / segDuration
+}
+
+// FindBestMatchingSegments : 자막 구간과 겹치는 모든 VAD 세그먼트 찾기
+func FindBestMatchingSegments(segments []Segment, startTime, endTime float64) (float64, []int) {
+	if len(segments) == 0 {
+		return 0.0, nil
+	}
+
+	matchedIndices := make([]int, 0)
+	maxOverlap := 0.0
+
+	for i, seg := range segments {
+		overlap := GetOverlapRatio(startTime, endTime, seg.SpeechStartAt, seg.SpeechEndAt)
+		if overlap > 0 {
+			matchedIndices = append(matchedIndices, i)
+			if overlap > maxOverlap {
+				maxOverlap = overlap
+			}
+		}
+	}
+
+	return maxOverlap, matchedIndices
+}
+
+// FindContainingSegment : time 이 포함되는 세그먼트 인덱스 (이진탐색, 앞뒤 tolerance 초 허용). 못찾으면 -1
+func FindContainingSegment(segments []Segment, time, tolerance float64) int {
+	left, right := 0, len(segments)-1
+
+	for left <= right {
+		mid := (left + right) / 2
+		segment := segments[mid]
+
+		adjustedStart := segment.SpeechStartAt - tolerance
+		adjustedEnd := segment.SpeechEndAt + tolerance
+
+		if time >= adjustedStart && time <= adjustedEnd {
+			return mid
+		} else if time < adjustedStart {
+			right = mid - 1
+		} else {
+			left = mid + 1
+		}
+	}
+
+	return -1
+}
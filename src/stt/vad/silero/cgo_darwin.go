@@ -1,6 +1,6 @@
 //go:build darwin
 
-package main
+package silero
 
 /*
 #cgo CFLAGS: -I/usr/local/onnxruntime-osx-arm64-1.18.1/include
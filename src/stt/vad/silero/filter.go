@@ -0,0 +1,190 @@
+package silero
+
+import (
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/streamer45/silero-vad-go/speech"
+
+	"example/stt/vad"
+)
+
+// SampleRate : silero 모델이 받는 샘플레이트. 입력 파일이 다르면 내부에서 리샘플링함
+const SampleRate = 16000
+
+// DownMix : 다채널 입력을 모노로 만드는 방식 (기본은 채널 평균)
+var DownMix = vad.DownMixConfig{Mode: vad.DownMixAverage}
+
+// Detect : PCM 전체에 대해 silero raw 세그먼트 탐지 (후처리 없음)
+func Detect(config speech.DetectorConfig, pcm []float32) ([]vad.Segment, error) {
+	sd, err := speech.NewDetector(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create speech detector: %v", err)
+	}
+	defer sd.Destroy()
+
+	raw, err := sd.Detect(pcm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect PCM segments: %v", err)
+	}
+
+	segments := make([]vad.Segment, len(raw))
+	for i, seg := range raw {
+		segments[i] = vad.Segment(seg)
+	}
+	return segments, nil
+}
+
+// Filter : 무음구간 처리 필터 (탐지 + 후처리 + 무음 구간 소프트 게이트 적용 파일 저장)
+// 반환값 : 후처리된 세그먼트, sidecar, 전체 길이(초)
+// 입력은 샘플레이트/채널 수와 상관없이 받고, 출력 파일은 16kHz 모노로 저장됨
+func Filter(config *speech.DetectorConfig, wavPath, outputPath string) ([]vad.Segment, *vad.VADSidecar, float64, error) {
+	if config == nil {
+		return nil, nil, 0, fmt.Errorf("speech config is nil")
+	}
+
+	pcm, err := readInput(config, wavPath)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	metrics := vad.EstimatePadAndMinSilence(pcm, SampleRate)
+	applyPadMetrics(config, metrics)
+
+	raw, err := Detect(*config, pcm)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	totalDuration := float64(len(pcm)) / float64(SampleRate)
+	segments := postProcess(raw, totalDuration, config)
+
+	processed := vad.ApplySoftGate(pcm, SampleRate, segments)
+	if err = vad.WriteWavMono(outputPath, processed, SampleRate); err != nil {
+		return nil, nil, 0, err
+	}
+
+	sidecar := &vad.VADSidecar{
+		SampleRate:    SampleRate,
+		HopSec:        0.010,
+		NoiseFloorRMS: metrics.NoiseFloorRMS,
+		RMSEnvelope:   vad.FrameRMS(pcm, SampleRate, 0.020, 0.010),
+	}
+
+	return segments, sidecar, totalDuration, nil
+}
+
+// DetectOnly : 음성 구간 탐지만 수행 (오디오 변경 없음)
+func DetectOnly(config *speech.DetectorConfig, wavPath string) ([]vad.Segment, float64, error) {
+	if config == nil {
+		return nil, 0, fmt.Errorf("speech config is nil")
+	}
+
+	pcm, err := readInput(config, wavPath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	applyPadMetrics(config, vad.EstimatePadAndMinSilence(pcm, SampleRate))
+
+	raw, err := Detect(*config, pcm)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	totalDuration := float64(len(pcm)) / float64(SampleRate)
+	return postProcess(raw, totalDuration, config), totalDuration, nil
+}
+
+// DetectOnlyStream : DetectOnly 의 스트리밍 버전
+// 파일 전체를 메모리에 올리지 않고 두번 스트리밍(1. pad/min silence 추정, 2. VAD)으로 처리함
+// onSegment 가 있으면 raw 세그먼트가 확정될때마다 바로 전달됨
+func DetectOnlyStream(config *speech.DetectorConfig, wavPath string, onSegment func(vad.Segment)) ([]vad.Segment, float64, error) {
+	if config == nil {
+		return nil, 0, fmt.Errorf("speech config is nil")
+	}
+
+	reader, err := vad.OpenMonoStream(wavPath, SampleRate, DownMix)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer reader.Close()
+
+	if reader.SourceSampleRate() != SampleRate {
+		log.Printf("[DEBUG] VAD input resampled: %dHz -> %dHz\n", reader.SourceSampleRate(), SampleRate)
+	}
+	config.SampleRate = SampleRate
+
+	// Pad와 MinSilence 설정
+	if config.SpeechPadMs == 0 && config.MinSilenceDurationMs == 0 {
+		metrics, _, err := vad.EstimatePadAndMinSilenceStream(wavPath, DownMix)
+		if err != nil {
+			return nil, 0, err
+		}
+		applyPadMetrics(config, metrics)
+	}
+
+	raw := make([]vad.Segment, 0)
+	detector, err := NewStreamingDetector(*config, func(seg vad.Segment) {
+		raw = append(raw, seg)
+		if onSegment != nil {
+			onSegment(seg)
+		}
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer detector.Destroy()
+
+	for {
+		pcm, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if err = detector.Write(pcm); err != nil {
+			return nil, 0, err
+		}
+	}
+	detector.Flush()
+
+	totalDuration := reader.Duration()
+	return postProcess(raw, totalDuration, config), totalDuration, nil
+}
+
+// readInput : 파일을 읽어서 16kHz 모노로 변환. silero 는 16000 고정이라 config.SampleRate 도 같이 맞춤
+func readInput(config *speech.DetectorConfig, wavPath string) ([]float32, error) {
+	pcm, err := vad.ReadWavAs(wavPath, SampleRate, DownMix)
+	if err != nil {
+		return nil, err
+	}
+	config.SampleRate = SampleRate
+	return pcm, nil
+}
+
+// applyPadMetrics : 둘다 0일경우 파일 기준으로 지정하고, 아닐 경우 테스트용으로 지정해서 설정한것으로 간주하고 해당 값 그대로 사용
+func applyPadMetrics(config *speech.DetectorConfig, metrics vad.PadMetrics) {
+	if config.SpeechPadMs != 0 || config.MinSilenceDurationMs != 0 {
+		return
+	}
+
+	log.Printf("[DEBUG] Audio quality metrics: snr_db=%.2f, avg_silence_sec=%.2f\n", metrics.SNRdB, metrics.AvgSilenceSec)
+	config.SpeechPadMs = metrics.FinalPadMs
+	config.MinSilenceDurationMs = metrics.SuggestedMinSilenceMs
+}
+
+func postProcess(raw []vad.Segment, totalDuration float64, config *speech.DetectorConfig) []vad.Segment {
+	log.Printf("[DEBUG] VAD config: speech_pad_ms=%d, min_silence_duration_ms=%d, sample_rate=%d, threshold=%.2f\n",
+		config.SpeechPadMs, config.MinSilenceDurationMs, config.SampleRate, config.Threshold)
+
+	segments := vad.PostProcess(raw, totalDuration, vad.DefaultPostProcessConfig())
+
+	log.Printf("VAD segments: raw=%d, final=%d, avg_duration=%.2fs, total_duration=%.2fs\n",
+		len(raw), len(segments), vad.AvgDuration(segments), totalDuration)
+
+	return segments
+}
@@ -0,0 +1,137 @@
+package silero
+
+import (
+	"fmt"
+
+	"github.com/streamer45/silero-vad-go/speech"
+
+	"example/stt/vad"
+)
+
+const (
+	// silero 는 16kHz 기준 512 샘플(32ms) 단위로 추론함
+	windowSize = 512
+
+	// speech.Detector 가 이전 호출에서 시작된 발화의 끝을 만났을때 돌려주는 에러
+	errUnexpectedSpeechEnd = "unexpected speech end"
+)
+
+// StreamingDetector : silero 감지기를 윈도우 단위로 돌리면서 상태(모델 state, 발화 진행 여부)를 이어감
+// speech.Detector.Detect 는 한번의 호출 안에서 시작과 끝이 모두 있어야 세그먼트를 돌려주기 때문에
+// 512 샘플씩 따로 호출하고, 시작/끝 이벤트를 여기서 직접 이어붙임
+type StreamingDetector struct {
+	sd  *speech.Detector
+	cfg speech.DetectorConfig
+
+	scratch []float32 // Detect 가 마지막 윈도우를 처리하지 않아서 1샘플 여유를 둠
+	pending []float32
+
+	currSample int
+	open       bool
+	current    vad.Segment
+
+	onSegment func(vad.Segment)
+}
+
+// NewStreamingDetector : onSegment 는 발화가 끝날때마다 호출됨
+func NewStreamingDetector(config speech.DetectorConfig, onSegment func(vad.Segment)) (*StreamingDetector, error) {
+	sd, err := speech.NewDetector(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create speech detector: %v", err)
+	}
+
+	return &StreamingDetector{
+		sd:        sd,
+		cfg:       config,
+		scratch:   make([]float32, windowSize+1),
+		pending:   make([]float32, 0, windowSize*2),
+		onSegment: onSegment,
+	}, nil
+}
+
+// Write : PCM 을 이어서 넣음. 512 샘플 미만으로 남은 부분은 다음 호출로 넘김
+func (d *StreamingDetector) Write(pcm []float32) error {
+	d.pending = append(d.pending, pcm...)
+
+	offset := 0
+	for offset+windowSize <= len(d.pending) {
+		copy(d.scratch, d.pending[offset:offset+windowSize])
+		offset += windowSize
+
+		segments, err := d.sd.Detect(d.scratch)
+		d.currSample += windowSize
+
+		if err != nil {
+			if err.Error() != errUnexpectedSpeechEnd {
+				return fmt.Errorf("failed to detect PCM segments: %v", err)
+			}
+			d.closeSegment(d.estimateSpeechEnd())
+			continue
+		}
+
+		// 한 윈도우 안에서는 시작만 발생할 수 있음
+		if len(segments) > 0 && !d.open {
+			d.open = true
+			d.current = vad.Segment(segments[0])
+		}
+	}
+
+	n := copy(d.pending, d.pending[offset:])
+	d.pending = d.pending[:n]
+
+	return nil
+}
+
+// Flush : 스트림 종료. 진행중인 발화가 있으면 현재 위치에서 닫음
+func (d *StreamingDetector) Flush() {
+	end := float64(d.currSample+len(d.pending)) / float64(d.cfg.SampleRate)
+	d.closeSegment(end)
+	d.pending = d.pending[:0]
+}
+
+// Speaking : 진행중인 발화가 있으면 시작 시각(초)
+func (d *StreamingDetector) Speaking() (float64, bool) {
+	return d.current.SpeechStartAt, d.open
+}
+
+// Split : 진행중인 발화를 현재 위치에서 닫고 같은 위치에서 이어서 새로 시작 (긴 발화를 지연 한도 안에서 끊을때)
+// 모델 상태는 그대로라 나머지 발화의 끝은 평소처럼 감지됨
+func (d *StreamingDetector) Split() {
+	if !d.open {
+		return
+	}
+
+	at := float64(d.currSample) / float64(d.cfg.SampleRate)
+	d.closeSegment(at)
+	d.open = true
+	d.current = vad.Segment{SpeechStartAt: at}
+}
+
+func (d *StreamingDetector) Destroy() error {
+	return d.sd.Destroy()
+}
+
+// estimateSpeechEnd : Detect 가 끝을 알려줬지만 시각을 잃어버린 경우 추정
+// 끝 판정은 무음이 MinSilenceDurationMs 이상 이어진 첫 윈도우에서 일어나므로 오차는 한 윈도우(32ms) 미만
+func (d *StreamingDetector) estimateSpeechEnd() float64 {
+	minSilenceSamples := d.cfg.MinSilenceDurationMs * d.cfg.SampleRate / 1000
+	speechPadSamples := d.cfg.SpeechPadMs * d.cfg.SampleRate / 1000
+
+	return float64(d.currSample-minSilenceSamples+speechPadSamples) / float64(d.cfg.SampleRate)
+}
+
+func (d *StreamingDetector) closeSegment(end float64) {
+	if !d.open {
+		return
+	}
+
+	if end < d.current.SpeechStartAt {
+		end = d.current.SpeechStartAt
+	}
+	d.current.SpeechEndAt = end
+	d.open = false
+
+	if d.onSegment != nil {
+		d.onSegment(d.current)
+	}
+}
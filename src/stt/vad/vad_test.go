@@ -0,0 +1,307 @@
+package vad
+
+import (
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-audio/audio"
+	"github.com/go-audio/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostProcess(t *testing.T) {
+	raw := []Segment{
+		{SpeechStartAt: 0.5, SpeechEndAt: 0.6}, // 0.2초 미만 제거
+		{SpeechStartAt: 1.0, SpeechEndAt: 2.0},
+		{SpeechStartAt: 3.0, SpeechEndAt: 4.0}, // 1.5초 이내 병합
+		{SpeechStartAt: 10.0, SpeechEndAt: 0},  // 파일 끝까지
+	}
+
+	out := PostProcess(raw, 12.0, DefaultPostProcessConfig())
+	require.Len(t, out, 2)
+	assert.InDelta(t, 0.7, out[0].SpeechStartAt, 1e-9)
+	assert.InDelta(t, 4.5, out[0].SpeechEndAt, 1e-9)
+	assert.InDelta(t, 9.7, out[1].SpeechStartAt, 1e-9)
+	assert.InDelta(t, 12.5, out[1].SpeechEndAt, 1e-9)
+}
+
+func TestMergeCloseSegments(t *testing.T) {
+	segs := []Segment{
+		{SpeechStartAt: 0, SpeechEndAt: 10},
+		{SpeechStartAt: 2, SpeechEndAt: 3}, // 포함된 구간이 끝을 줄이면 안됨
+		{SpeechStartAt: 11, SpeechEndAt: 12},
+		{SpeechStartAt: 20, SpeechEndAt: 21},
+	}
+
+	out := MergeCloseSegments(segs, 1.5)
+	require.Len(t, out, 2)
+	assert.Equal(t, Segment{SpeechStartAt: 0, SpeechEndAt: 12}, out[0])
+	assert.Equal(t, Segment{SpeechStartAt: 20, SpeechEndAt: 21}, out[1])
+}
+
+func TestApplyAsymmetricPadding(t *testing.T) {
+	segs := []Segment{{SpeechStartAt: 0.1, SpeechEndAt: 1.0}, {SpeechStartAt: 1.5, SpeechEndAt: 2.0}}
+	out := ApplyAsymmetricPadding(segs, 300, 500)
+	require.Len(t, out, 1)
+	assert.Equal(t, 0.0, out[0].SpeechStartAt)
+	assert.InDelta(t, 2.5, out[0].SpeechEndAt, 1e-9)
+}
+
+func TestRemoveIsolatedNoise(t *testing.T) {
+	segs := []Segment{
+		{SpeechStartAt: 0, SpeechEndAt: 5},
+		{SpeechStartAt: 10, SpeechEndAt: 10.2}, // 앞뒤로 고립된 짧은 구간
+		{SpeechStartAt: 15, SpeechEndAt: 20},
+		{SpeechStartAt: 20.5, SpeechEndAt: 20.7}, // 짧지만 앞 구간과 가까움
+	}
+
+	out := RemoveIsolatedNoise(segs, 2.0, 0.3)
+	assert.Len(t, out, 3)
+	assert.NotContains(t, out, Segment{SpeechStartAt: 10, SpeechEndAt: 10.2})
+}
+
+func TestFindContainingSegment(t *testing.T) {
+	segs := []Segment{{SpeechStartAt: 0, SpeechEndAt: 1}, {SpeechStartAt: 5, SpeechEndAt: 6}}
+	assert.Equal(t, 1, FindContainingSegment(segs, 5.5, 0))
+	assert.Equal(t, 1, FindContainingSegment(segs, 4.6, 0.5))
+	assert.Equal(t, -1, FindContainingSegment(segs, 3, 0.5))
+	assert.Equal(t, -1, FindContainingSegment(nil, 3, 0.5))
+}
+
+func TestOverlap(t *testing.T) {
+	assert.InDelta(t, 0.5, GetOverlapRatio(0, 2, 1, 5), 1e-9)
+	assert.Equal(t, 0.0, GetOverlapRatio(0, 1, 1, 2))
+
+	maxOverlap, idx := FindBestMatchingSegments([]Segment{{SpeechStartAt: 0, SpeechEndAt: 1}, {SpeechStartAt: 1.5, SpeechEndAt: 3}}, 0.5, 2)
+	assert.InDelta(t, 1.0/3.0, maxOverlap, 1e-9)
+	assert.Equal(t, []int{0, 1}, idx)
+}
+
+// tone : 앞 절반은 무음(약한 노이즈), 뒤 절반은 440Hz 사인파
+func tone(sr int, sec float64) []float32 {
+	n := int(float64(sr) * sec)
+	pcm := make([]float32, n)
+	for i := range pcm {
+		if i < n/2 {
+			pcm[i] = float32(0.001 * math.Sin(float64(i)*0.37))
+		} else {
+			pcm[i] = float32(0.5 * math.Sin(2*math.Pi*440*float64(i)/float64(sr)))
+		}
+	}
+	return pcm
+}
+
+func TestRMSAccumulatorMatchesFrameRMS(t *testing.T) {
+	sr := 16000
+	pcm := tone(sr, 1.3)
+
+	acc := NewRMSAccumulator(sr)
+	for i := 0; i < len(pcm); i += 777 { // hop 과 맞지 않는 크기로 잘라서 넣음
+		end := i + 777
+		if end > len(pcm) {
+			end = len(pcm)
+		}
+		acc.Write(pcm[i:end])
+	}
+
+	expected := FrameRMS(pcm, sr, 0.020, 0.010)
+	require.Len(t, acc.Envelope(), len(expected))
+	for i := range expected {
+		assert.InDelta(t, expected[i], acc.Envelope()[i], 1e-9)
+	}
+}
+
+func TestEstimatePadAndMinSilence(t *testing.T) {
+	m := EstimatePadAndMinSilence(tone(16000, 2), 16000)
+	assert.Greater(t, m.SNRdB, 18.0)
+	assert.GreaterOrEqual(t, m.FinalPadMs, 400)
+	assert.LessOrEqual(t, m.FinalPadMs, 850)
+	assert.GreaterOrEqual(t, m.SuggestedMinSilenceMs, 400)
+	assert.LessOrEqual(t, m.SuggestedMinSilenceMs, 1200)
+
+	empty := EstimatePadAndMinSilenceFromRMS(nil)
+	assert.Equal(t, 200, empty.FinalPadMs)
+	assert.Equal(t, 800, empty.SuggestedMinSilenceMs)
+}
+
+func TestApplySoftGate(t *testing.T) {
+	sr := 16000
+	pcm := tone(sr, 2)
+
+	out := ApplySoftGate(pcm, sr, []Segment{{SpeechStartAt: 1.0, SpeechEndAt: 2.0}})
+	require.Len(t, out, len(pcm))
+
+	// 발화 구간 안쪽은 그대로, 무음 구간은 감쇠
+	assert.Equal(t, pcm[sr+sr/2], out[sr+sr/2])
+	assert.InDelta(t, float64(pcm[100])*SoftGateAtt, float64(out[100]), 1e-6)
+
+	// 세그먼트가 없으면 에너지 마스크로 살림
+	out = ApplySoftGate(pcm, sr, nil)
+	assert.Equal(t, pcm[sr+sr/2], out[sr+sr/2])
+}
+
+func TestWavRoundTrip(t *testing.T) {
+	sr := 16000
+	pcm := tone(sr, 1.5)
+	path := filepath.Join(t.TempDir(), "tone.wav")
+
+	require.NoError(t, WriteWavMono(path, pcm, sr))
+
+	full, gotSR, err := ReadWavMono(path)
+	require.NoError(t, err)
+	assert.Equal(t, sr, gotSR)
+	require.Len(t, full, len(pcm))
+	assert.InDelta(t, pcm[sr], full[sr], 1e-4)
+
+	reader, err := OpenWavStream(path, 4000)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	streamed := make([]float32, 0, len(pcm))
+	for {
+		chunk, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		streamed = append(streamed, chunk...)
+	}
+	assert.Equal(t, full, streamed)
+	assert.InDelta(t, 1.5, reader.Duration(), 1e-9)
+
+	metrics, duration, err := EstimatePadAndMinSilenceStream(path, DownMixConfig{})
+	require.NoError(t, err)
+	assert.InDelta(t, 1.5, duration, 1e-9)
+	assert.Equal(t, EstimatePadAndMinSilence(full, sr), metrics)
+}
+
+func sine(sr int, freq float64, n int) []float32 {
+	pcm := make([]float32, n)
+	for i := range pcm {
+		pcm[i] = float32(0.5 * math.Sin(2*math.Pi*freq*float64(i)/float64(sr)))
+	}
+	return pcm
+}
+
+func rms(pcm []float32) float64 {
+	var sum float64
+	for _, v := range pcm {
+		sum += float64(v) * float64(v)
+	}
+	return math.Sqrt(sum / float64(len(pcm)))
+}
+
+func TestResample(t *testing.T) {
+	in := []float32{0, 1, 2, 3}
+	out, err := Resample(in, 16000, 16000)
+	require.NoError(t, err)
+	assert.Equal(t, in, out)
+
+	_, err = Resample(in, 0, 16000)
+	assert.Error(t, err)
+
+	// 48k -> 16k : 1kHz 는 그대로 통과, 출력 나이퀴스트(8kHz) 넘는 10kHz 는 에일리어싱 없이 제거되어야 함
+	pass, err := Resample(sine(48000, 1000, 48000), 48000, 16000)
+	require.NoError(t, err)
+	require.Len(t, pass, 16000)
+	assert.InDelta(t, 0.5/math.Sqrt2, rms(pass[1000:15000]), 0.01)
+
+	stop, err := Resample(sine(48000, 10000, 48000), 48000, 16000)
+	require.NoError(t, err)
+	assert.Less(t, rms(stop[1000:15000]), 0.005)
+
+	// 44.1k -> 16k 위상 확인 : 기준 신호와 샘플 단위로 비교
+	out, err = Resample(sine(44100, 440, 44100), 44100, 16000)
+	require.NoError(t, err)
+	require.Len(t, out, 16000)
+	want := sine(16000, 440, 16000)
+	for i := 1000; i < 15000; i += 997 {
+		assert.InDelta(t, want[i], out[i], 0.01)
+	}
+}
+
+func TestResamplerStreamMatchesFull(t *testing.T) {
+	in := sine(44100, 300, 44100)
+	full, err := Resample(in, 44100, 16000)
+	require.NoError(t, err)
+
+	r, err := NewResampler(44100, 16000)
+	require.NoError(t, err)
+	streamed := make([]float32, 0, len(full))
+	for i := 0; i < len(in); i += 1234 {
+		end := min(i+1234, len(in))
+		streamed = append(streamed, r.Process(in[i:end])...)
+	}
+	streamed = append(streamed, r.Flush()...)
+
+	require.Len(t, streamed, len(full))
+	for i := range full {
+		assert.InDelta(t, full[i], streamed[i], 1e-6)
+	}
+}
+
+func TestDownMix(t *testing.T) {
+	stereo := []float32{1, 0, 0.5, 0.5, 0, -1}
+
+	out, err := DownMix(stereo, 2, DownMixConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, []float32{0.5, 0.5, -0.5}, out)
+
+	out, err = DownMix(stereo, 2, DownMixConfig{Mode: DownMixChannel, Channel: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []float32{0, 0.5, -1}, out)
+
+	_, err = DownMix(stereo, 2, DownMixConfig{Mode: DownMixChannel, Channel: 2})
+	assert.Error(t, err)
+}
+
+func TestMonoStreamReader(t *testing.T) {
+	sr := 48000
+	left := sine(sr, 440, sr)
+	interleaved := make([]int, 0, len(left)*2)
+	for _, v := range left {
+		interleaved = append(interleaved, ClipAndQuantizeInt16(v), 0)
+	}
+
+	path := filepath.Join(t.TempDir(), "stereo.wav")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	enc := wav.NewEncoder(f, sr, 16, 2, 1)
+	require.NoError(t, enc.Write(&audio.IntBuffer{
+		Format:         &audio.Format{NumChannels: 2, SampleRate: sr},
+		Data:           interleaved,
+		SourceBitDepth: 16,
+	}))
+	require.NoError(t, enc.Close())
+	require.NoError(t, f.Close())
+
+	full, err := ReadWavAs(path, 16000, DownMixConfig{Mode: DownMixChannel})
+	require.NoError(t, err)
+	require.Len(t, full, 16000)
+
+	reader, err := OpenMonoStream(path, 16000, DownMixConfig{Mode: DownMixChannel})
+	require.NoError(t, err)
+	defer reader.Close()
+
+	streamed := make([]float32, 0, len(full))
+	for {
+		chunk, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		streamed = append(streamed, chunk...)
+	}
+	require.Len(t, streamed, len(full))
+	assert.InDelta(t, full[8000], streamed[8000], 1e-6)
+	assert.InDelta(t, 1.0, reader.Duration(), 1e-9)
+
+	// 평균 다운믹스는 한쪽 채널만 있으니 절반 크기
+	avg, err := ReadWavAs(path, 16000, DownMixConfig{})
+	require.NoError(t, err)
+	assert.InDelta(t, rms(full[1000:15000])/2, rms(avg[1000:15000]), 1e-3)
+}
@@ -0,0 +1,313 @@
+package vad
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/go-audio/audio"
+	"github.com/go-audio/wav"
+)
+
+// streamWindowSec : 스트리밍 경로에서 한번에 읽어들이는 PCM 길이
+const streamWindowSec = 10.0
+
+// ReadWavMono : WAV 파일 전체를 float32 로 읽음 (모노만 허용)
+func ReadWavMono(path string) ([]float32, int, error) {
+	inputFile, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open audio file: %v", err)
+	}
+	defer inputFile.Close()
+
+	decoder := wav.NewDecoder(inputFile)
+	if !decoder.IsValidFile() {
+		return nil, 0, fmt.Errorf("audio file is not a valid file")
+	}
+
+	buf, err := decoder.FullPCMBuffer()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read PCM buffer: %v", err)
+	}
+
+	// 모노채널 체크 : ffmpeg으로 모노채널로 만들긴 하지만 혹시모를 방어코드임
+	if buf.Format.NumChannels != 1 {
+		return nil, 0, fmt.Errorf("expected mono(1ch), got %dch (preprocess with -ac 1)", buf.Format.NumChannels)
+	}
+
+	return buf.AsFloat32Buffer().Data, buf.Format.SampleRate, nil
+}
+
+// ReadWavAs : WAV 파일 전체를 읽어서 다운믹스 + targetSR 로 리샘플링한 모노 PCM 반환
+// 녹화 서버에서 넘어오는 44.1/48kHz 스테레오를 ffmpeg 없이 바로 VAD 에 넣을때 사용
+func ReadWavAs(path string, targetSR int, downMix DownMixConfig) ([]float32, error) {
+	inputFile, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audio file: %v", err)
+	}
+	defer inputFile.Close()
+
+	decoder := wav.NewDecoder(inputFile)
+	if !decoder.IsValidFile() {
+		return nil, fmt.Errorf("audio file is not a valid file")
+	}
+
+	buf, err := decoder.FullPCMBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PCM buffer: %v", err)
+	}
+
+	mono, err := DownMix(buf.AsFloat32Buffer().Data, buf.Format.NumChannels, downMix)
+	if err != nil {
+		return nil, err
+	}
+
+	return Resample(mono, buf.Format.SampleRate, targetSR)
+}
+
+// WriteWavMono : float32 PCM 을 16-bit 모노 WAV 로 저장
+func WriteWavMono(path string, pcm []float32, sr int) error {
+	outputFile, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer outputFile.Close()
+
+	encoder := wav.NewEncoder(outputFile, sr, 16, 1, 1)
+
+	intData := make([]int, len(pcm))
+	for i, sample := range pcm {
+		intData[i] = ClipAndQuantizeInt16(sample)
+	}
+
+	err = encoder.Write(&audio.IntBuffer{
+		Format:         &audio.Format{NumChannels: 1, SampleRate: sr},
+		Data:           intData,
+		SourceBitDepth: 16,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write audio: %v", err)
+	}
+
+	if err = encoder.Close(); err != nil {
+		return fmt.Errorf("failed to close encoder: %v", err)
+	}
+
+	return nil
+}
+
+// ClipAndQuantizeInt16 : [-1, 1] 클리핑 후 16-bit 정수로 반올림
+func ClipAndQuantizeInt16(x float32) int {
+	if x > 1.0 {
+		x = 1.0
+	} else if x < -1.0 {
+		x = -1.0
+	}
+	v := int(math.Round(float64(x * 32767.0)))
+	if v > 32767 {
+		v = 32767
+	}
+	if v < -32768 {
+		v = -32768
+	}
+	return v
+}
+
+// WavStreamReader : WAV 파일을 고정 크기 윈도우 단위로 읽는 리더 (FullPCMBuffer 대체)
+type WavStreamReader struct {
+	file    *os.File
+	decoder *wav.Decoder
+	intBuf  *audio.IntBuffer
+	out     []float32
+	factor  float64
+
+	SampleRate  int
+	NumChannels int
+	SamplesRead int64 // 지금까지 읽은 프레임 수 (채널 수로 나눈 값)
+}
+
+// OpenWavStream : windowSamples 단위로 읽는 스트림 리더 생성 (채널이 여러개면 인터리브 그대로 반환)
+// windowSamples 가 0 이하이면 10초 단위
+func OpenWavStream(path string, windowSamples int) (*WavStreamReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audio file: %v", err)
+	}
+
+	decoder := wav.NewDecoder(file)
+	if !decoder.IsValidFile() {
+		file.Close()
+		return nil, fmt.Errorf("audio file is not a valid file")
+	}
+
+	if err = decoder.FwdToPCM(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to find PCM chunk: %v", err)
+	}
+
+	format := decoder.Format()
+	if windowSamples <= 0 {
+		windowSamples = int(streamWindowSec * float64(format.SampleRate))
+	}
+	windowSamples *= format.NumChannels
+
+	return &WavStreamReader{
+		file:    file,
+		decoder: decoder,
+		intBuf: &audio.IntBuffer{
+			Data:           make([]int, windowSamples),
+			Format:         format,
+			SourceBitDepth: int(decoder.BitDepth),
+		},
+		out:         make([]float32, windowSamples),
+		factor:      math.Pow(2, float64(decoder.BitDepth)-1),
+		SampleRate:  format.SampleRate,
+		NumChannels: format.NumChannels,
+	}, nil
+}
+
+// Next : 다음 윈도우를 float32 로 반환, 더 읽을게 없으면 io.EOF
+// 반환된 슬라이스는 다음 호출에서 재사용되므로 보관하려면 복사해야 함
+func (r *WavStreamReader) Next() ([]float32, error) {
+	n, err := r.decoder.PCMBuffer(r.intBuf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PCM buffer: %v", err)
+	}
+	if n == 0 {
+		return nil, io.EOF
+	}
+
+	// AsFloat32Buffer 와 동일한 스케일링
+	for i := 0; i < n; i++ {
+		r.out[i] = float32(float64(r.intBuf.Data[i]) / r.factor)
+	}
+	r.SamplesRead += int64(n / r.NumChannels)
+
+	return r.out[:n], nil
+}
+
+// Duration : 지금까지 읽은 길이(초)
+func (r *WavStreamReader) Duration() float64 {
+	return float64(r.SamplesRead) / float64(r.SampleRate)
+}
+
+func (r *WavStreamReader) Close() error {
+	return r.file.Close()
+}
+
+// MonoStreamReader : WavStreamReader 위에서 다운믹스 + 리샘플링까지 해서 모노 PCM 을 돌려주는 리더
+type MonoStreamReader struct {
+	reader    *WavStreamReader
+	resampler *Resampler // 샘플레이트가 같으면 nil
+	downMix   DownMixConfig
+	flushed   bool
+
+	SampleRate int // 출력 샘플레이트
+}
+
+// OpenMonoStream : targetSR 가 0 이하이면 원본 샘플레이트 유지
+func OpenMonoStream(path string, targetSR int, downMix DownMixConfig) (*MonoStreamReader, error) {
+	reader, err := OpenWavStream(path, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if reader.NumChannels <= 0 {
+		reader.Close()
+		return nil, fmt.Errorf("invalid channel count: %d", reader.NumChannels)
+	}
+	if downMix.Mode == DownMixChannel && downMix.Channel >= reader.NumChannels {
+		reader.Close()
+		return nil, fmt.Errorf("down-mix channel %d out of range (%dch)", downMix.Channel, reader.NumChannels)
+	}
+
+	if targetSR <= 0 {
+		targetSR = reader.SampleRate
+	}
+
+	s := &MonoStreamReader{
+		reader:     reader,
+		downMix:    downMix,
+		SampleRate: targetSR,
+	}
+
+	if targetSR != reader.SampleRate {
+		s.resampler, err = NewResampler(reader.SampleRate, targetSR)
+		if err != nil {
+			reader.Close()
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Next : 다음 모노 PCM 조각, 더 읽을게 없으면 io.EOF
+// 리샘플링 중에는 필터 지연 때문에 빈 슬라이스가 나올 수 있음
+func (s *MonoStreamReader) Next() ([]float32, error) {
+	if s.flushed {
+		return nil, io.EOF
+	}
+
+	pcm, err := s.reader.Next()
+	if err == io.EOF {
+		s.flushed = true
+		if s.resampler == nil {
+			return nil, io.EOF
+		}
+		return s.resampler.Flush(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mono, err := DownMix(pcm, s.reader.NumChannels, s.downMix)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.resampler == nil {
+		return mono, nil
+	}
+	return s.resampler.Process(mono), nil
+}
+
+// SourceSampleRate : 원본 파일 샘플레이트
+func (s *MonoStreamReader) SourceSampleRate() int {
+	return s.reader.SampleRate
+}
+
+// Duration : 지금까지 읽은 원본 길이(초)
+func (s *MonoStreamReader) Duration() float64 {
+	return s.reader.Duration()
+}
+
+func (s *MonoStreamReader) Close() error {
+	return s.reader.Close()
+}
+
+// EstimatePadAndMinSilenceStream : 파일을 한번만 훑으면서 pad, min silence 계산, 파일 길이(초)를 같이 반환
+// PCM 전체 대신 10ms RMS 엔벨로프만 메모리에 남김 (1시간 기준 약 360,000개)
+// 다채널이면 downMix 기준으로 모노로 만든 뒤 계산 (RMS 는 원본 샘플레이트 그대로 사용)
+func EstimatePadAndMinSilenceStream(path string, downMix DownMixConfig) (PadMetrics, float64, error) {
+	reader, err := OpenMonoStream(path, 0, downMix)
+	if err != nil {
+		return PadMetrics{}, 0, err
+	}
+	defer reader.Close()
+
+	acc := NewRMSAccumulator(reader.SampleRate)
+	for {
+		pcm, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return PadMetrics{}, 0, err
+		}
+		acc.Write(pcm)
+	}
+
+	return EstimatePadAndMinSilenceFromRMS(acc.Envelope()), reader.Duration(), nil
+}
@@ -0,0 +1,151 @@
+package whisper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	DefaultEndpoint = "https://api.openai.com/v1/audio/transcriptions"
+
+	// maxFileSize : whisper 업로드 최대 용량
+	maxFileSize      = 25 * 1024 * 1024 // 25MB = 25MiB
+	mb               = 1024 * 1024
+	modelNameWhisper = "whisper-1"
+)
+
+// Client : OpenAI Whisper Speech To Text API 클라이언트
+type Client struct {
+	APIKey   string
+	Endpoint string
+	Model    string
+	Language string
+	Timeout  time.Duration // 요청 1건당 타임아웃 (호출자 ctx 와 함께 적용)
+
+	HTTPClient *http.Client
+}
+
+func NewClient(apiKey string) *Client {
+	return &Client{
+		APIKey:     apiKey,
+		Endpoint:   DefaultEndpoint,
+		Model:      modelNameWhisper, // timestamp issue 로 인해 whisper 모델 강제됨
+		Language:   "en",
+		Timeout:    5 * time.Minute, // 일단 5분 정도로 잡음
+		HTTPClient: &http.Client{},
+	}
+}
+
+// Transcribe : audioPath 파일을 업로드해서 verbose_json 응답을 받음
+// audioPath : 원본파일과 일부 재시도 하는 temp 파일 경로 둘다 해당됨, fileName 은 multipart 에 실릴 이름
+func (c *Client) Transcribe(ctx context.Context, audioPath, fileName string) (*WhisperResponse, error) {
+	// 용량체크
+	if _, err := CheckFileSize(audioPath); err != nil {
+		return nil, fmt.Errorf("check audio fail: %w", err)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	writer.WriteField("model", c.Model)
+	writer.WriteField("response_format", "verbose_json")
+	if c.Language != "" {
+		writer.WriteField("language", c.Language)
+	}
+	writer.WriteField("timestamp_granularities[]", "word")
+	writer.WriteField("timestamp_granularities[]", "segment")
+	writer.WriteField("temperature", "0") // 환각 줄이기: 결정론적 디코딩
+
+	file, err := os.Open(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("open audio file: %w", err)
+	}
+	defer file.Close()
+
+	fileWriter, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		return nil, fmt.Errorf("create form file: %w", err)
+	}
+	if _, err := io.Copy(fileWriter, file); err != nil {
+		return nil, fmt.Errorf("copy audio file: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart writer: %w", err)
+	}
+
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint, &body)
+	if err != nil {
+		return nil, fmt.Errorf("http request fail: %w", err)
+	}
+
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request fail: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("response read fail: %w", err)
+	}
+
+	// POST 는 206 응답을 주지 않음
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("whisper api error: status=%s body=%s", resp.Status, string(responseBody))
+	}
+
+	ret := &WhisperResponse{}
+	if err = json.Unmarshal(responseBody, ret); err != nil {
+		return nil, fmt.Errorf("json parsing error: %w", err)
+	}
+
+	return ret, nil
+}
+
+// CheckFileSize : 업로드 가능한 용량인지 확인, 파일 크기(bytes) 반환
+func CheckFileSize(path string) (int64, error) {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, fmt.Errorf("file %s does not exist", path)
+		}
+		return 0, fmt.Errorf("file info not found: %v", err)
+	}
+
+	fileSize := fileInfo.Size() // bytes
+
+	if fileSize > maxFileSize {
+		currentMB := float64(fileSize) / float64(mb) // 실제 MB
+		maxMB := float64(maxFileSize) / float64(mb)  // 25.00
+
+		return 0, fmt.Errorf(
+			"file size too big :: current - %.2fMB, max - %.2fMB",
+			currentMB,
+			maxMB,
+		)
+	}
+
+	return fileSize, nil
+}
@@ -0,0 +1,101 @@
+package whisper
+
+import "example/stt/subtitle"
+
+// wordTolerance : 세그먼트 경계와 단어 타임스탬프 비교시 오차 (30~80ms 사이에서 샘플 보며 조정)
+const wordTolerance = 0.05
+
+// ConvertWhisperResponse : OpenAI 응답 데이터를 json, srt 파일로 만들기 위한 struct 생성 (json format)
+// OpenAI Whisper 는 segment, word 데이터를 따로 주기 때문에, segment 먼저 변환한 후 시간 범위로 word 를 다시 조합함
+func ConvertWhisperResponse(response *WhisperResponse) []subtitle.SubtitleSegment {
+	convertedSegments := make([]subtitle.SubtitleSegment, 0, len(response.Segments))
+
+	wordTotalLength := len(response.Words)
+	wordCursor := 0 // 과도한 탐색 방지를 위해 직전에 탐색한 인덱스 값 담아두는 용도
+
+	for _, seg := range response.Segments {
+
+		// Text 가 없으면 건너뜀
+		if len(subtitle.NormalizeWhitespace(seg.Text)) == 0 {
+			continue
+		}
+
+		// subtitle object create
+		sub := subtitle.SubtitleSegment{
+			Idx:                     seg.ID,
+			StartTime:               subtitle.RoundSeconds(seg.Start),
+			EndTime:                 subtitle.RoundSeconds(seg.End),
+			Sentence:                subtitle.NormalizeWhitespace(seg.Text),
+			SentenceConfidenceScore: seg.AvgLogProb,
+			NoSpeechProb:            seg.NoSpeechProb,
+			CompressionRatio:        seg.CompressionRatio,
+		}
+
+		// words array index search
+		startIdx := wordCursor
+		for startIdx < wordTotalLength && (response.Words[startIdx].End+wordTolerance) < seg.Start {
+			startIdx++
+		}
+
+		endIdx := startIdx
+		for endIdx < wordTotalLength && (response.Words[endIdx].Start-wordTolerance) <= seg.End {
+			endIdx++
+		}
+
+		// [startIdx, endIdx)
+		frames := make([]subtitle.SentenceFrames, 0, endIdx-startIdx)
+		frameIdx := 0
+
+		for k := startIdx; k < endIdx; k++ {
+			word := response.Words[k]
+
+			// 혹시모르니 가드
+			if (word.End+wordTolerance) < seg.Start || (word.Start-wordTolerance) > seg.End {
+				continue
+			}
+
+			wordStart := word.Start
+			wordEnd := word.End
+
+			if wordStart < sub.StartTime {
+				wordStart = sub.StartTime
+			}
+			if wordEnd > sub.EndTime {
+				wordEnd = sub.EndTime // segment 끝 시간으로 자름 (오버타임이슈 방지)
+			}
+
+			frames = append(frames, subtitle.SentenceFrames{
+				WordIdx:       frameIdx,
+				Word:          subtitle.NormalizeWhitespace(word.Word),
+				WordStartTime: subtitle.RoundSeconds(wordStart),
+				WordEndTime:   subtitle.RoundSeconds(wordEnd),
+				// Whisper는 confidence_score 안줌
+			})
+			frameIdx++
+		}
+
+		sub.SentenceFrames = frames
+		convertedSegments = append(convertedSegments, sub)
+
+		// next search index update
+		wordCursor = endIdx
+	}
+
+	return convertedSegments
+}
+
+// FilterNoSpeech : NoSpeechProb 기반 환청 필터링 + 너무 짧은 구간 제거
+// 20250918_vad_filter 에서 쓰던 기준 : noSpeechProb 0.6 초과, minDuration 0.3초 미만
+func FilterNoSpeech(segments []WhisperSegment, maxNoSpeechProb, minDuration float64) []WhisperSegment {
+	filtered := make([]WhisperSegment, 0, len(segments))
+	for _, seg := range segments {
+		if seg.NoSpeechProb > maxNoSpeechProb {
+			continue
+		}
+		if seg.End-seg.Start < minDuration {
+			continue
+		}
+		filtered = append(filtered, seg)
+	}
+	return filtered
+}
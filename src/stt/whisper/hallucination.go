@@ -0,0 +1,185 @@
+package whisper
+
+import (
+	"sort"
+	"strings"
+
+	"example/stt/subtitle"
+)
+
+type HallucinationSegment struct {
+	StartIdx  int
+	EndIdx    int
+	StartTime float64
+	EndTime   float64
+	Text      string
+}
+
+// DetectAllHallucinations : 환각 범위 체크 고도화 Ver. (N-gram check)
+func DetectAllHallucinations(segments []WhisperSegment, minRepetitions int, minRepetitionRatio float64) []HallucinationSegment {
+	hallucinations := make([]HallucinationSegment, 0)
+
+	// 1. 세그먼트 간 반복 체크
+	crossSegmentHalls := DetectHallucinations(segments, minRepetitions)
+	hallucinations = append(hallucinations, crossSegmentHalls...)
+
+	// 2. 단위 세그먼트 내 반복체크
+	inSegmentHalls := DetectInSegmentHallucinations(segments, minRepetitionRatio)
+
+	// 1 + 2
+	existingRanges := make(map[int]bool)
+	for _, h := range hallucinations {
+		for i := h.StartIdx; i < h.EndIdx; i++ {
+			existingRanges[i] = true
+		}
+	}
+
+	for _, h := range inSegmentHalls {
+		if !existingRanges[h.StartIdx] {
+			hallucinations = append(hallucinations, h)
+		}
+	}
+
+	// 오름차순으로 정렬 (chunk 재요청 할때 역순으로 접근하기 때문에 인덱스 꼬임 방지)
+	sort.Slice(hallucinations, func(i, j int) bool {
+		return hallucinations[i].StartIdx < hallucinations[j].StartIdx
+	})
+
+	// duration 0.1 미만의 반복 데이터면 텍스트 비움(0.1 미만은 단일 데이터일 확률이 높음)
+	// 데이터 자체를 제거는 하지 않고 이대로 convert 영역까지 들고가서 거기서 걸러냄
+	for i := range hallucinations {
+		if hallucinations[i].EndTime-hallucinations[i].StartTime < 0.1 {
+			hallucinations[i].Text = ""
+		}
+	}
+
+	return hallucinations
+}
+
+// DetectHallucinations : 세그먼트 간 같은 문장이 minRepetitions 번 이상 연속되는 범위 체크
+func DetectHallucinations(segments []WhisperSegment, minRepetitions int) []HallucinationSegment {
+	hallucinations := make([]HallucinationSegment, 0)
+	i := 0
+
+	for i < len(segments) {
+		text := subtitle.NormalizeWhitespace(segments[i].Text)
+
+		if len(text) == 0 {
+			i++
+			continue
+		}
+
+		startIdx := i
+		lastValidIdx := i
+		j := i + 1
+		consecutiveCount := 1
+
+		for j < len(segments) {
+			nextText := subtitle.NormalizeWhitespace(segments[j].Text)
+			if len(nextText) == 0 {
+				j++
+				continue
+			}
+
+			if !strings.EqualFold(text, nextText) {
+				break
+			}
+
+			consecutiveCount++
+			lastValidIdx = j
+			j++
+		}
+
+		if consecutiveCount >= minRepetitions {
+			hallucinations = append(hallucinations, HallucinationSegment{
+				StartIdx:  startIdx,
+				EndIdx:    lastValidIdx + 1,
+				StartTime: segments[startIdx].Start,
+				EndTime:   segments[lastValidIdx].End,
+				Text:      text,
+			})
+			i = j
+		} else {
+			i++
+		}
+	}
+
+	return hallucinations
+}
+
+// DetectInSegmentHallucinations : 단일 세그먼트 내 단어 반복 체크
+func DetectInSegmentHallucinations(segments []WhisperSegment, minRepetitionRatio float64) []HallucinationSegment {
+	hallucinations := make([]HallucinationSegment, 0)
+
+	for i, seg := range segments {
+		text := subtitle.NormalizeWhitespace(seg.Text)
+		if len(text) < 20 { // 너무 짧은건 체크 안함
+			continue
+		}
+
+		if HasRepetitivePattern(text, minRepetitionRatio) {
+			hallucinations = append(hallucinations, HallucinationSegment{
+				StartIdx:  i,
+				EndIdx:    i + 1,
+				StartTime: seg.Start,
+				EndTime:   seg.End,
+				Text:      text,
+			})
+		}
+	}
+
+	return hallucinations
+}
+
+// HasRepetitivePattern : 세그먼트 내 반복패턴 확인 (최빈 단어 비율 또는 2-gram 반복)
+func HasRepetitivePattern(text string, minRatio float64) bool {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) < 10 {
+		return false
+	}
+
+	wordCount := make(map[string]int, len(words))
+	for _, word := range words {
+		if len(word) > 2 {
+			wordCount[word]++
+		}
+	}
+
+	maxCount := 0
+	for _, count := range wordCount {
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+
+	if float64(maxCount)/float64(len(words)) >= minRatio {
+		return true
+	}
+
+	return hasRepeatingNgrams(words, 2, 5, minRatio)
+}
+
+// hasRepeatingNgrams : N-gram 반복패턴 체크
+func hasRepeatingNgrams(words []string, ngramSize, minRepeat int, minRatio float64) bool {
+	if len(words) < ngramSize*minRepeat {
+		return false
+	}
+
+	ngramCount := make(map[string]int)
+
+	for i := 0; i <= len(words)-ngramSize; i++ {
+		ngram := strings.Join(words[i:i+ngramSize], " ")
+		ngramCount[ngram]++
+	}
+
+	totalNgrams := len(words) - ngramSize + 1
+	for _, count := range ngramCount {
+		if count >= minRepeat {
+			if float64(count)/float64(totalNgrams) >= minRatio {
+				return true
+			}
+		}
+	}
+
+	return false
+}
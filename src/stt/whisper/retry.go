@@ -0,0 +1,289 @@
+package whisper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+
+	"example/stt/ffmpeg"
+)
+
+// HallucinationRetryConfig : 환각 구간 재요청 설정
+type HallucinationRetryConfig struct {
+	MaxRetry           int     // 재요청 후 재감지 반복 횟수
+	MinRepetitions     int     // 세그먼트 간 반복 기준
+	MinRepetitionRatio float64 // 세그먼트 내 n-gram 반복 비율 기준
+	PaddingSec         float64 // 구간을 잘라낼때 앞뒤로 붙이는 여유 (문맥이 있어야 whisper 가 덜 헛소리함)
+	TempDir            string  // 잘라낸 오디오 임시 경로, 비어있으면 os.TempDir()
+}
+
+// DefaultHallucinationRetryConfig : 기존 하드코딩 값 (minRepetitions 5, hallucinationRetry 1)
+func DefaultHallucinationRetryConfig() HallucinationRetryConfig {
+	return HallucinationRetryConfig{
+		MaxRetry:           1,
+		MinRepetitions:     5,
+		MinRepetitionRatio: 0.5,
+		PaddingSec:         0.5,
+	}
+}
+
+// HallucinationFix : 환각 구간 처리 결과 (작업 리포트용)
+type HallucinationFix struct {
+	Attempt   int     `json:"attempt"`
+	StartTime float64 `json:"start_time"`
+	EndTime   float64 `json:"end_time"`
+	Text      string  `json:"text"`            // 환각으로 판단된 원래 텍스트
+	Fixed     bool    `json:"fixed"`           // 재감지 결과 해당 구간에 환각이 남지 않음
+	Result    string  `json:"result"`          // retried, cleared(너무 짧아서 비움), failed
+	Error     string  `json:"error,omitempty"` // failed 일때 원인
+}
+
+const (
+	FixResultRetried = "retried"
+	FixResultCleared = "cleared"
+	FixResultFailed  = "failed"
+)
+
+// RetryHallucinationSegments : 환각이 발생한 자막 재호출 시도
+// audioPath(original 을 만든 오디오)에서 환각 구간을 패딩 포함해서 잘라 재요청하고 결과를 mergeSegments 로 교체
+// 환각이 없으면 original 을 그대로 반환함
+func (c *Client) RetryHallucinationSegments(ctx context.Context, original *WhisperResponse, audioPath, fileName string, config HallucinationRetryConfig) (*WhisperResponse, []HallucinationFix, error) {
+	fixes := make([]HallucinationFix, 0)
+
+	if original == nil || len(original.Segments) == 0 {
+		return original, fixes, nil
+	}
+
+	hallucinations := DetectAllHallucinations(original.Segments, config.MinRepetitions, config.MinRepetitionRatio)
+	if len(hallucinations) == 0 {
+		return original, fixes, nil
+	}
+
+	merged := original
+
+	for i := 0; i < config.MaxRetry; i++ {
+		// 인덱스가 꼬이는것 같아서 역순으로 처리하겠음
+		for idx := len(hallucinations) - 1; idx >= 0; idx-- {
+			if err := ctx.Err(); err != nil {
+				return merged, fixes, err
+			}
+
+			halluc := hallucinations[idx]
+			fix := HallucinationFix{
+				Attempt:   i + 1,
+				StartTime: halluc.StartTime,
+				EndTime:   halluc.EndTime,
+				Text:      halluc.Text,
+				Result:    FixResultRetried,
+			}
+
+			var segmentResponse *WhisperResponse
+			duration := halluc.EndTime - halluc.StartTime
+
+			// 환각 데이터가 너무 짧으면 요청하지 않고 건너뜀 (이후 convert 단계에서도 자막 데이터 생성하지 않을 것)
+			// 요청하면 400 bad request 발생함
+			if duration < 0.1 {
+				// 해당 경우는 단일 세그먼트가 환각을 일으켰는데, 자막 데이터가 너무 짧아서 재요청 할 수 없는 상황임
+				// 이후 convert 하는 과정에서 text 가 빈 값이면 제외되는 로직이 있으므로 빈 객체 만들어서 자동 merged 처리
+				segmentResponse = &WhisperResponse{
+					Task:     merged.Task,
+					Language: merged.Language,
+					Duration: duration,
+					Segments: []WhisperSegment{
+						{
+							ID:    0,
+							Start: 0,
+							End:   duration,
+							Text:  "",
+						},
+					},
+					Words: []WhisperWord{},
+				}
+				fix.Result = FixResultCleared
+			} else {
+				var err error
+				segmentResponse, err = c.transcribeRange(ctx, audioPath, fileName, halluc, config)
+				if err != nil {
+					log.Println("hallucination retry fail", "err", err)
+					fix.Result = FixResultFailed
+					fix.Error = err.Error()
+					fixes = append(fixes, fix)
+					continue
+				}
+			}
+
+			if segmentResponse != nil && len(segmentResponse.Segments) > 0 { // 정상 응답이든 빈 응답이든 merge
+				merged = mergeSegments(merged, segmentResponse, halluc)
+			}
+			fixes = append(fixes, fix)
+		}
+
+		// 다음 시도를 위해 환각 재감지 (없으면 중지)
+		hallucinations = DetectAllHallucinations(merged.Segments, config.MinRepetitions, config.MinRepetitionRatio)
+		log.Println("Re-checking hallucinations on merged segments", "count", len(hallucinations))
+		if len(hallucinations) == 0 {
+			break
+		}
+	}
+
+	// 재감지 결과 남아있는 환각과 겹치지 않으면 고쳐진 것으로 봄
+	for i := range fixes {
+		fixes[i].Fixed = fixes[i].Result != FixResultFailed && !overlapsAny(hallucinations, fixes[i].StartTime, fixes[i].EndTime)
+	}
+
+	return merged, fixes, nil
+}
+
+// transcribeRange : 환각 구간을 패딩 포함해서 임시 파일로 잘라 재요청
+// 응답 타임스탬프는 환각 구간 시작 기준으로 맞추고, 패딩 영역에 걸친 세그먼트/단어는 버림 (앞뒤 자막과 중복되니까)
+func (c *Client) transcribeRange(ctx context.Context, audioPath, fileName string, halluc HallucinationSegment, config HallucinationRetryConfig) (*WhisperResponse, error) {
+	if audioPath == "" {
+		return nil, fmt.Errorf("source audio path is empty")
+	}
+
+	tempFile, err := os.CreateTemp(config.TempDir, "halluc_*"+filepath.Ext(audioPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tempPath := tempFile.Name()
+	tempFile.Close()
+	defer os.Remove(tempPath)
+
+	rangeStart := math.Max(halluc.StartTime-config.PaddingSec, 0)
+	rangeEnd := halluc.EndTime + config.PaddingSec
+
+	if err = ffmpeg.ExtractSegment(ctx, audioPath, tempPath, rangeStart, rangeEnd); err != nil {
+		return nil, fmt.Errorf("audio extractor fail: %w", err)
+	}
+
+	resp, err := c.Transcribe(ctx, tempPath, fileName)
+	if err != nil {
+		return nil, fmt.Errorf("whisper api fail: %w", err)
+	}
+
+	return clipToRange(resp, halluc.StartTime-rangeStart, halluc.EndTime-halluc.StartTime), nil
+}
+
+// clipToRange : lead 만큼 당겨서 0 ~ duration 기준으로 맞추고, 중심이 범위 밖인 세그먼트/단어는 제외
+func clipToRange(resp *WhisperResponse, lead, duration float64) *WhisperResponse {
+	clipped := &WhisperResponse{
+		Task:     resp.Task,
+		Language: resp.Language,
+		Duration: duration,
+		Segments: make([]WhisperSegment, 0, len(resp.Segments)),
+		Words:    make([]WhisperWord, 0, len(resp.Words)),
+	}
+
+	inRange := func(start, end float64) bool {
+		mid := (start + end) / 2
+		return mid >= 0 && mid <= duration
+	}
+
+	for _, seg := range resp.Segments {
+		seg.Start -= lead
+		seg.End -= lead
+		if inRange(seg.Start, seg.End) {
+			seg.Start = math.Max(seg.Start, 0)
+			seg.End = math.Min(seg.End, duration)
+			clipped.Segments = append(clipped.Segments, seg)
+		}
+	}
+
+	for _, word := range resp.Words {
+		word.Start -= lead
+		word.End -= lead
+		if inRange(word.Start, word.End) {
+			word.Start = math.Max(word.Start, 0)
+			word.End = math.Min(word.End, duration)
+			clipped.Words = append(clipped.Words, word)
+		}
+	}
+
+	// 패딩 영역 말고는 아무 말도 없었으면 빈 세그먼트로 교체 (환각 구간은 지워짐)
+	if len(clipped.Segments) == 0 {
+		clipped.Segments = append(clipped.Segments, WhisperSegment{Start: 0, End: duration, Text: ""})
+	}
+
+	return clipped
+}
+
+func overlapsAny(hallucinations []HallucinationSegment, start, end float64) bool {
+	for _, h := range hallucinations {
+		if h.StartTime < end && start < h.EndTime {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeSegments : 환각 구간(halluc)을 재요청 결과(retried)로 교체, retried 타임스탬프는 구간 시작 기준이라 offset 보정
+func mergeSegments(original *WhisperResponse, retried *WhisperResponse, halluc HallucinationSegment) *WhisperResponse {
+	ret := &WhisperResponse{
+		Task:     original.Task,
+		Language: original.Language,
+		Duration: original.Duration,
+		Words:    []WhisperWord{},
+		Segments: []WhisperSegment{},
+		// Text 어차피 안쓰니까 비워둠
+	}
+
+	timeOffset := halluc.StartTime
+
+	adjustedSegments := make([]WhisperSegment, len(retried.Segments))
+	for idx, seg := range retried.Segments {
+		adjustedSegments[idx] = seg
+		adjustedSegments[idx].Start += timeOffset
+		adjustedSegments[idx].End += timeOffset
+		adjustedSegments[idx].ID = halluc.StartIdx + idx
+	}
+
+	adjustedWords := make([]WhisperWord, len(retried.Words))
+	for idx, word := range retried.Words {
+		adjustedWords[idx] = word
+		adjustedWords[idx].Start += timeOffset
+		adjustedWords[idx].End += timeOffset
+	}
+
+	// original segment 와 adjust segment 를 결합
+	ret.Segments = append(ret.Segments, original.Segments[:halluc.StartIdx]...)
+	ret.Segments = append(ret.Segments, adjustedSegments...)
+
+	afterSegments := original.Segments[halluc.EndIdx:]
+	idOffset := len(ret.Segments)
+
+	for i, seg := range afterSegments {
+		seg.ID = idOffset + i
+		ret.Segments = append(ret.Segments, seg)
+	}
+
+	wordStartIdx := len(original.Words)
+	wordEndIdx := 0
+
+	for i, word := range original.Words {
+		if word.Start >= halluc.StartTime {
+			wordStartIdx = i
+			break
+		}
+	}
+
+	for i := len(original.Words) - 1; i >= 0; i-- {
+		if original.Words[i].End <= halluc.EndTime {
+			wordEndIdx = i + 1
+			break
+		}
+	}
+
+	// 환각 구간에 단어가 하나도 없으면 교체할 범위도 없음
+	if wordEndIdx < wordStartIdx {
+		wordEndIdx = wordStartIdx
+	}
+
+	ret.Words = append(ret.Words, original.Words[:wordStartIdx]...)
+	ret.Words = append(ret.Words, adjustedWords...)
+	ret.Words = append(ret.Words, original.Words[wordEndIdx:]...)
+
+	return ret
+}
@@ -0,0 +1,270 @@
+package whisper
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"example/stt/subtitle"
+	"example/stt/vad"
+)
+
+// 위험 신호 이름 (리포트 reasons 에 그대로 기록됨)
+const (
+	SignalRepetition       = "repetition"         // 세그먼트 간 같은 문장 반복
+	SignalInSegmentRepeat  = "in_segment_repeat"  // 세그먼트 내 단어/n-gram 반복
+	SignalLowLogProb       = "low_logprob"        // avg_logprob 가 기준보다 낮음
+	SignalHighCompression  = "high_compression"   // compression_ratio 가 기준보다 높음
+	SignalNoSpeech         = "no_speech"          // no_speech_prob 가 높음
+	SignalOutsideSpeech    = "outside_vad_speech" // VAD 발화 구간 밖 텍스트
+	SignalPhantomPhrase    = "phantom_phrase"     // 무음에서 자주 나오는 문구
+	SignalTemperatureRetry = "temperature_retry"  // whisper 가 온도를 올려서 다시 디코딩함
+)
+
+// DefaultPhantomPhrases : 무음/음악 구간에서 whisper 가 자주 만들어내는 문구 (정규화된 형태)
+var DefaultPhantomPhrases = []string{
+	"thanks for watching",
+	"thank you for watching",
+	"thank you so much for watching",
+	"please subscribe",
+	"like and subscribe",
+	"subscribe to my channel",
+	"see you in the next video",
+	"subtitles by the amaraorg community",
+	"you",
+	"시청해주셔서 감사합니다",
+	"구독과 좋아요 부탁드립니다",
+	"구독과 좋아요",
+}
+
+// ScoreConfig : 신호별 기준값과 가중치
+// 점수는 noisy-OR (1 - Π(1 - weight)) 로 합쳐서 0~1 사이, 신호가 여러개 겹칠수록 1 에 가까워짐
+type ScoreConfig struct {
+	MinRepetitions     int
+	MinRepetitionRatio float64
+
+	LogProbThreshold          float64 // 이보다 낮으면 신호 (whisper 기본 -1.0)
+	CompressionRatioThreshold float64 // 이보다 높으면 신호 (whisper 기본 2.4)
+	NoSpeechThreshold         float64 // 이보다 높으면 신호 (whisper 기본 0.6)
+	TemperatureThreshold      float64 // 이 이상이면 fallback 디코딩으로 봄
+	MinSpeechOverlap          float64 // VAD 발화와 겹치는 비율이 이보다 낮으면 신호
+
+	PhantomPhrases []string
+
+	Weights       map[string]float64
+	FlagThreshold float64 // 이 이상이면 flagged
+}
+
+// DefaultScoreConfig : whisper 자체 기준값 + 샘플 보면서 잡은 가중치
+func DefaultScoreConfig() ScoreConfig {
+	return ScoreConfig{
+		MinRepetitions:            5,
+		MinRepetitionRatio:        0.5,
+		LogProbThreshold:          -1.0,
+		CompressionRatioThreshold: 2.4,
+		NoSpeechThreshold:         0.6,
+		TemperatureThreshold:      0.6,
+		MinSpeechOverlap:          0.3,
+		PhantomPhrases:            DefaultPhantomPhrases,
+		Weights: map[string]float64{
+			SignalRepetition:       0.6,
+			SignalInSegmentRepeat:  0.6,
+			SignalLowLogProb:       0.3,
+			SignalHighCompression:  0.45,
+			SignalNoSpeech:         0.35,
+			SignalOutsideSpeech:    0.5,
+			SignalPhantomPhrase:    0.7,
+			SignalTemperatureRetry: 0.15,
+		},
+		FlagThreshold: 0.5,
+	}
+}
+
+// RiskReason : 점수에 반영된 신호 하나
+type RiskReason struct {
+	Signal string  `json:"signal"`
+	Weight float64 `json:"weight"`
+	Detail string  `json:"detail"`
+}
+
+// SegmentRisk : 세그먼트별 환각 위험도
+type SegmentRisk struct {
+	Idx     int          `json:"idx"`
+	Start   float64      `json:"start"`
+	End     float64      `json:"end"`
+	Text    string       `json:"text"`
+	Score   float64      `json:"score"`
+	Flagged bool         `json:"flagged"`
+	Reasons []RiskReason `json:"reasons"`
+}
+
+// HallucinationReport : 세그먼트별 점수 리포트 (신호가 하나라도 있는 세그먼트만 기록)
+type HallucinationReport struct {
+	TotalSegments   int           `json:"total_segments"`
+	FlaggedSegments int           `json:"flagged_segments"`
+	Segments        []SegmentRisk `json:"segments"`
+}
+
+// ScoreHallucinations : 반복, 신뢰도 값, VAD, 알려진 문구를 합쳐서 세그먼트별 위험도 계산
+// speech 는 segments 와 같은 타임라인의 VAD 발화 구간 (nil 이면 VAD 신호는 건너뜀)
+func ScoreHallucinations(segments []WhisperSegment, speech []vad.Segment, config ScoreConfig) HallucinationReport {
+	reasons := make([][]RiskReason, len(segments))
+	add := func(i int, signal, detail string) {
+		weight := config.Weights[signal]
+		if weight <= 0 {
+			return
+		}
+		reasons[i] = append(reasons[i], RiskReason{Signal: signal, Weight: weight, Detail: detail})
+	}
+
+	// 1. 세그먼트 간 반복
+	for _, h := range DetectHallucinations(segments, config.MinRepetitions) {
+		for i := h.StartIdx; i < h.EndIdx; i++ {
+			add(i, SignalRepetition, fmt.Sprintf("repeated %d times", h.EndIdx-h.StartIdx))
+		}
+	}
+
+	// 2. 세그먼트 내 반복
+	for _, h := range DetectInSegmentHallucinations(segments, config.MinRepetitionRatio) {
+		add(h.StartIdx, SignalInSegmentRepeat, "repetitive words or bigrams")
+	}
+
+	sortedSpeech := make([]vad.Segment, len(speech))
+	copy(sortedSpeech, speech)
+	vad.SortSegments(sortedSpeech)
+
+	phantoms := make(map[string]struct{}, len(config.PhantomPhrases))
+	for _, p := range config.PhantomPhrases {
+		phantoms[normalizePhrase(p)] = struct{}{}
+	}
+
+	for i, seg := range segments {
+		if len(subtitle.NormalizeWhitespace(seg.Text)) == 0 {
+			continue
+		}
+
+		// 3. whisper 신뢰도 값
+		if seg.AvgLogProb < config.LogProbThreshold {
+			add(i, SignalLowLogProb, fmt.Sprintf("avg_logprob %.2f < %.2f", seg.AvgLogProb, config.LogProbThreshold))
+		}
+		if seg.CompressionRatio > config.CompressionRatioThreshold {
+			add(i, SignalHighCompression, fmt.Sprintf("compression_ratio %.2f > %.2f", seg.CompressionRatio, config.CompressionRatioThreshold))
+		}
+		if seg.NoSpeechProb > config.NoSpeechThreshold {
+			add(i, SignalNoSpeech, fmt.Sprintf("no_speech_prob %.2f > %.2f", seg.NoSpeechProb, config.NoSpeechThreshold))
+		}
+		if config.TemperatureThreshold > 0 && seg.Temperature >= config.TemperatureThreshold {
+			add(i, SignalTemperatureRetry, fmt.Sprintf("temperature %.1f", seg.Temperature))
+		}
+
+		// 4. VAD 발화 구간 밖
+		if speech != nil {
+			if overlap := speechOverlap(sortedSpeech, seg.Start, seg.End); overlap < config.MinSpeechOverlap {
+				add(i, SignalOutsideSpeech, fmt.Sprintf("vad speech overlap %.0f%%", overlap*100))
+			}
+		}
+
+		// 5. 알려진 문구
+		if _, ok := phantoms[normalizePhrase(seg.Text)]; ok {
+			add(i, SignalPhantomPhrase, fmt.Sprintf("known phrase %q", subtitle.NormalizeWhitespace(seg.Text)))
+		}
+	}
+
+	report := HallucinationReport{
+		TotalSegments: len(segments),
+		Segments:      make([]SegmentRisk, 0),
+	}
+
+	for i, seg := range segments {
+		if len(reasons[i]) == 0 {
+			continue
+		}
+
+		keep := 1.0
+		for _, r := range reasons[i] {
+			keep *= 1 - r.Weight
+		}
+		score := math.Round((1-keep)*1000) / 1000
+
+		risk := SegmentRisk{
+			Idx:     seg.ID,
+			Start:   seg.Start,
+			End:     seg.End,
+			Text:    subtitle.NormalizeWhitespace(seg.Text),
+			Score:   score,
+			Flagged: score >= config.FlagThreshold,
+			Reasons: reasons[i],
+		}
+		if risk.Flagged {
+			report.FlaggedSegments++
+		}
+		report.Segments = append(report.Segments, risk)
+	}
+
+	return report
+}
+
+// Append : 청크별 리포트를 합침. offset 은 청크 시작 시간 (원본 타임라인 보정)
+func (r *HallucinationReport) Append(other HallucinationReport, offset float64) {
+	r.TotalSegments += other.TotalSegments
+	r.FlaggedSegments += other.FlaggedSegments
+
+	for _, risk := range other.Segments {
+		risk.Start += offset
+		risk.End += offset
+		r.Segments = append(r.Segments, risk)
+	}
+
+	sort.SliceStable(r.Segments, func(i, j int) bool {
+		return r.Segments[i].Start < r.Segments[j].Start
+	})
+}
+
+// SaveHallucinationReport : 리포트 JSON 저장
+func SaveHallucinationReport(report HallucinationReport, outputPath string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("JSON marshal failed: %w", err)
+	}
+
+	if err = os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("file write failed: %w", err)
+	}
+
+	return nil
+}
+
+// speechOverlap : start~end 중 VAD 발화와 겹치는 비율 (speech 는 정렬되어 있어야 함)
+func speechOverlap(speech []vad.Segment, start, end float64) float64 {
+	duration := end - start
+	if duration <= 0 {
+		return 0
+	}
+
+	var overlap float64
+	for _, s := range speech {
+		if s.SpeechStartAt >= end {
+			break
+		}
+		o := math.Min(end, s.SpeechEndAt) - math.Max(start, s.SpeechStartAt)
+		if o > 0 {
+			overlap += o
+		}
+	}
+	return math.Min(overlap/duration, 1)
+}
+
+// normalizePhrase : 소문자 + 문장부호 제거 + 공백 정리 (문구 비교용)
+func normalizePhrase(text string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, text)
+	return subtitle.NormalizeWhitespace(cleaned)
+}
@@ -0,0 +1,336 @@
+package whisper
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/stt/ffmpeg"
+	"example/stt/vad"
+)
+
+func TestConvertWhisperResponse(t *testing.T) {
+	resp := &WhisperResponse{
+		Segments: []WhisperSegment{
+			{ID: 0, Start: 0.0, End: 1.004, Text: " hello   world ", AvgLogProb: -0.2},
+			{ID: 1, Start: 1.2, End: 1.5, Text: "  "},
+			{ID: 2, Start: 2.0, End: 3.0, Text: "bye"},
+		},
+		Words: []WhisperWord{
+			{Word: " hello", Start: 0.0, End: 0.5},
+			{Word: "world", Start: 0.5, End: 1.1},
+			{Word: "bye", Start: 2.0, End: 3.0},
+		},
+	}
+
+	subs := ConvertWhisperResponse(resp)
+	require.Len(t, subs, 2)
+
+	assert.Equal(t, "hello world", subs[0].Sentence)
+	assert.Equal(t, 1.0, subs[0].EndTime)
+	require.Len(t, subs[0].SentenceFrames, 2)
+	assert.Equal(t, "hello", subs[0].SentenceFrames[0].Word)
+	assert.Equal(t, 1.0, subs[0].SentenceFrames[1].WordEndTime) // 세그먼트 끝으로 잘림
+
+	assert.Equal(t, 2, subs[1].Idx)
+	require.Len(t, subs[1].SentenceFrames, 1)
+}
+
+func TestFilterNoSpeech(t *testing.T) {
+	segs := []WhisperSegment{
+		{Start: 0, End: 1, NoSpeechProb: 0.1},
+		{Start: 1, End: 2, NoSpeechProb: 0.9},
+		{Start: 2, End: 2.1, NoSpeechProb: 0.1},
+	}
+	assert.Len(t, FilterNoSpeech(segs, 0.6, 0.3), 1)
+}
+
+func TestDetectHallucinations(t *testing.T) {
+	segs := make([]WhisperSegment, 0)
+	segs = append(segs, WhisperSegment{Start: 0, End: 1, Text: "intro"})
+	for i := 0; i < 5; i++ {
+		segs = append(segs, WhisperSegment{Start: float64(i + 1), End: float64(i + 2), Text: "Thank you."})
+	}
+	segs = append(segs, WhisperSegment{Start: 6, End: 7, Text: "outro"})
+
+	halls := DetectHallucinations(segs, 5)
+	require.Len(t, halls, 1)
+	assert.Equal(t, 1, halls[0].StartIdx)
+	assert.Equal(t, 6, halls[0].EndIdx)
+	assert.Equal(t, 1.0, halls[0].StartTime)
+	assert.Equal(t, 6.0, halls[0].EndTime)
+
+	assert.Empty(t, DetectHallucinations(segs, 6))
+}
+
+func TestDetectAllHallucinations(t *testing.T) {
+	repeated := strings.Repeat("go go go ", 5)
+	segs := []WhisperSegment{
+		{Start: 0, End: 3, Text: "this is a perfectly normal sentence with enough words in it"},
+		{Start: 3, End: 6, Text: repeated + "gopher gopher gopher gopher"},
+	}
+
+	halls := DetectAllHallucinations(segs, 5, 0.5)
+	require.Len(t, halls, 1)
+	assert.Equal(t, 1, halls[0].StartIdx)
+}
+
+func TestHasRepetitivePattern(t *testing.T) {
+	assert.False(t, HasRepetitivePattern("short text", 0.5))
+	assert.True(t, HasRepetitivePattern(strings.Repeat("thank you ", 6), 0.3))
+	assert.False(t, HasRepetitivePattern("the quick brown fox jumps over the lazy dog near the river bank", 0.5))
+}
+
+func TestScoreHallucinations(t *testing.T) {
+	segs := []WhisperSegment{
+		{ID: 0, Start: 0, End: 2, Text: "normal speech here", AvgLogProb: -0.2, CompressionRatio: 1.2},
+		{ID: 1, Start: 2, End: 4, Text: "mumble", AvgLogProb: -1.3, CompressionRatio: 1.1},
+		{ID: 2, Start: 10, End: 12, Text: " Thanks for watching! ", AvgLogProb: -0.8, NoSpeechProb: 0.9},
+		{ID: 3, Start: 4, End: 6, Text: "la la la", CompressionRatio: 3.1, Temperature: 0.8},
+	}
+	speech := []vad.Segment{{SpeechStartAt: 0, SpeechEndAt: 6}}
+
+	report := ScoreHallucinations(segs, speech, DefaultScoreConfig())
+	assert.Equal(t, 4, report.TotalSegments)
+	require.Len(t, report.Segments, 3) // 신호 없는 0번은 제외
+
+	byIdx := make(map[int]SegmentRisk)
+	for _, r := range report.Segments {
+		byIdx[r.Idx] = r
+	}
+
+	signals := func(r SegmentRisk) []string {
+		out := make([]string, 0)
+		for _, reason := range r.Reasons {
+			out = append(out, reason.Signal)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{SignalLowLogProb}, signals(byIdx[1]))
+	assert.InDelta(t, 0.3, byIdx[1].Score, 1e-9)
+	assert.False(t, byIdx[1].Flagged)
+
+	// 무음 + 구간 밖 + 알려진 문구 : noisy-OR 로 1 에 가까움
+	assert.ElementsMatch(t, []string{SignalNoSpeech, SignalOutsideSpeech, SignalPhantomPhrase}, signals(byIdx[2]))
+	assert.InDelta(t, 1-(0.65*0.5*0.3), byIdx[2].Score, 1e-3)
+	assert.True(t, byIdx[2].Flagged)
+
+	// 압축률 + fallback 디코딩
+	assert.ElementsMatch(t, []string{SignalHighCompression, SignalTemperatureRetry}, signals(byIdx[3]))
+	assert.True(t, byIdx[3].Flagged)
+	assert.Equal(t, 2, report.FlaggedSegments)
+
+	// VAD 없으면 VAD 신호 제외
+	noVad := ScoreHallucinations(segs, nil, DefaultScoreConfig())
+	for _, r := range noVad.Segments {
+		assert.NotContains(t, signals(r), SignalOutsideSpeech)
+	}
+
+	// 반복은 구간 전체에 표시
+	repeated := make([]WhisperSegment, 5)
+	for i := range repeated {
+		repeated[i] = WhisperSegment{ID: i, Start: float64(i), End: float64(i + 1), Text: "okay"}
+	}
+	rep := ScoreHallucinations(repeated, nil, DefaultScoreConfig())
+	require.Len(t, rep.Segments, 5)
+	assert.True(t, rep.Segments[4].Flagged)
+
+	var merged HallucinationReport
+	merged.Append(rep, 100)
+	merged.Append(report, 0)
+	assert.Equal(t, 9, merged.TotalSegments)
+	assert.Equal(t, 2.0, merged.Segments[0].Start)
+	assert.Equal(t, 100.0, merged.Segments[3].Start)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, SaveHallucinationReport(report, path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"signal": "phantom_phrase"`)
+}
+
+func TestMergeSegments(t *testing.T) {
+	original := &WhisperResponse{
+		Segments: []WhisperSegment{
+			{ID: 0, Start: 0, End: 1, Text: "a"},
+			{ID: 1, Start: 1, End: 2, Text: "x"},
+			{ID: 2, Start: 2, End: 3, Text: "x"},
+			{ID: 3, Start: 3, End: 4, Text: "b"},
+		},
+		Words: []WhisperWord{
+			{Word: "a", Start: 0, End: 1},
+			{Word: "x", Start: 1, End: 2},
+			{Word: "x", Start: 2, End: 3},
+			{Word: "b", Start: 3, End: 4},
+		},
+	}
+	retried := &WhisperResponse{
+		Segments: []WhisperSegment{{Start: 0, End: 2, Text: "fixed"}},
+		Words:    []WhisperWord{{Word: "fixed", Start: 0, End: 2}},
+	}
+	halluc := HallucinationSegment{StartIdx: 1, EndIdx: 3, StartTime: 1, EndTime: 3}
+
+	merged := mergeSegments(original, retried, halluc)
+
+	require.Len(t, merged.Segments, 3)
+	assert.Equal(t, "fixed", merged.Segments[1].Text)
+	assert.Equal(t, 1.0, merged.Segments[1].Start)
+	assert.Equal(t, 3.0, merged.Segments[1].End)
+	assert.Equal(t, 2, merged.Segments[2].ID)
+
+	words := make([]string, 0)
+	for _, w := range merged.Words {
+		words = append(words, w.Word)
+	}
+	assert.Equal(t, []string{"a", "fixed", "b"}, words)
+}
+
+func TestClientTranscribe(t *testing.T) {
+	audioPath := filepath.Join(t.TempDir(), "chunk.webm")
+	require.NoError(t, os.WriteFile(audioPath, []byte("fake audio"), 0644))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "whisper-1", r.FormValue("model"))
+		assert.Equal(t, "verbose_json", r.FormValue("response_format"))
+
+		_, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		assert.Equal(t, "job.webm", header.Filename)
+
+		json.NewEncoder(w).Encode(WhisperResponse{
+			Language: "en",
+			Segments: []WhisperSegment{{ID: 0, Start: 0, End: 1, Text: "hi"}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key")
+	client.Endpoint = server.URL
+
+	resp, err := client.Transcribe(context.Background(), audioPath, "job.webm")
+	require.NoError(t, err)
+	require.Len(t, resp.Segments, 1)
+	assert.Equal(t, "hi", resp.Segments[0].Text)
+}
+
+func TestClientTranscribe_Errors(t *testing.T) {
+	audioPath := filepath.Join(t.TempDir(), "chunk.webm")
+	require.NoError(t, os.WriteFile(audioPath, []byte("fake audio"), 0644))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key")
+	client.Endpoint = server.URL
+
+	_, err := client.Transcribe(context.Background(), audioPath, "job.webm")
+	assert.ErrorContains(t, err, "400")
+
+	_, err = client.Transcribe(context.Background(), filepath.Join(t.TempDir(), "missing.webm"), "job.webm")
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Transcribe(ctx, audioPath, "job.webm")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// fakeFFmpeg : 입력 파일을 그대로 복사하고 인자를 기록하는 ffmpeg 대역
+func fakeFFmpeg(t *testing.T) string {
+	dir := t.TempDir()
+	argsPath := filepath.Join(dir, "args")
+	script := filepath.Join(dir, "ffmpeg")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho \"$@\" > "+argsPath+"\nfor last; do :; done\ncp \"$2\" \"$last\"\n"), 0755))
+
+	prev := ffmpeg.Binary
+	ffmpeg.Binary = script
+	t.Cleanup(func() { ffmpeg.Binary = prev })
+
+	return argsPath
+}
+
+func TestRetryHallucinationSegments(t *testing.T) {
+	argsPath := fakeFFmpeg(t)
+
+	audioPath := filepath.Join(t.TempDir(), "chunk.wav")
+	require.NoError(t, os.WriteFile(audioPath, []byte("fake audio"), 0644))
+
+	original := &WhisperResponse{Segments: []WhisperSegment{{ID: 0, Start: 0, End: 1, Text: "hello"}}}
+	for i := 1; i <= 6; i++ {
+		original.Segments = append(original.Segments, WhisperSegment{ID: i, Start: float64(i), End: float64(i + 1), Text: "thank you"})
+	}
+	original.Segments = append(original.Segments, WhisperSegment{ID: 7, Start: 7, End: 8, Text: "bye"})
+
+	// 재요청 응답은 패딩(0.5초) 포함 구간 기준 타임스탬프, 첫 세그먼트는 패딩 영역이라 버려져야 함
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(WhisperResponse{
+			Segments: []WhisperSegment{
+				{Start: 0, End: 0.4, Text: "hello"},
+				{Start: 0.5, End: 6.5, Text: "the real sentence"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key")
+	client.Endpoint = server.URL
+
+	tempDir := t.TempDir()
+	config := DefaultHallucinationRetryConfig()
+	config.TempDir = tempDir
+
+	merged, fixes, err := client.RetryHallucinationSegments(context.Background(), original, audioPath, "job.wav", config)
+	require.NoError(t, err)
+
+	texts := make([]string, 0)
+	for _, s := range merged.Segments {
+		texts = append(texts, s.Text)
+	}
+	assert.Equal(t, []string{"hello", "the real sentence", "bye"}, texts)
+	assert.Equal(t, 1.0, merged.Segments[1].Start)
+	assert.Equal(t, 7.0, merged.Segments[1].End)
+
+	require.Len(t, fixes, 1)
+	assert.True(t, fixes[0].Fixed)
+	assert.Equal(t, FixResultRetried, fixes[0].Result)
+	assert.Equal(t, 1.0, fixes[0].StartTime)
+
+	args, err := os.ReadFile(argsPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(args), "-ss 0.500 -to 7.500")
+
+	// 임시 파일 정리
+	entries, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// 환각이 없으면 그대로
+	clean := &WhisperResponse{Segments: []WhisperSegment{{Text: "hello"}}}
+	same, fixes, err := client.RetryHallucinationSegments(context.Background(), clean, audioPath, "job.wav", config)
+	require.NoError(t, err)
+	assert.Same(t, clean, same)
+	assert.Empty(t, fixes)
+
+	// 재요청 실패는 리포트에 남기고 원본 유지
+	server.Close()
+	merged, fixes, err = client.RetryHallucinationSegments(context.Background(), original, audioPath, "job.wav", config)
+	require.NoError(t, err)
+	assert.Len(t, merged.Segments, len(original.Segments))
+	require.Len(t, fixes, 1)
+	assert.False(t, fixes[0].Fixed)
+	assert.Equal(t, FixResultFailed, fixes[0].Result)
+	assert.NotEmpty(t, fixes[0].Error)
+}